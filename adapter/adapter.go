@@ -7,6 +7,7 @@ import (
 
 	"github.com/futurehomeno/fimpgo"
 	"github.com/futurehomeno/fimpgo/fimptype"
+	log "github.com/sirupsen/logrus"
 
 	"github.com/futurehomeno/cliffhanger/event"
 )
@@ -342,6 +343,10 @@ func (a *adapter) SendConnectivityReport() error {
 func (a *adapter) registerThing(t Thing) {
 	a.things[t.Address()] = t
 
+	if rt, ok := t.(ReportingThing); ok {
+		rt.restoreReportingStrategies()
+	}
+
 	t.Connect()
 }
 
@@ -354,6 +359,8 @@ func (a *adapter) unregisterThing(t Thing) {
 
 // createThing utilizes factory to create a thing, persists it in the state and adds to the adapter.
 func (a *adapter) createThing(seed *ThingSeed) error {
+	previousAddress, released := a.state.releasedAddress(seed.ID)
+
 	ts, err := a.createThingState(seed)
 	if err != nil {
 		return fmt.Errorf("failed to create state for thing with ID %s: %w", seed.ID, err)
@@ -371,16 +378,24 @@ func (a *adapter) createThing(seed *ThingSeed) error {
 
 	a.registerThing(t)
 
+	if released && previousAddress != t.Address() {
+		// Thing is already persisted and registered at this point, therefore failure of the report does not fail the creation.
+		err = a.sendAddressChangeReport(seed.ID, t.Address(), previousAddress)
+		if err != nil {
+			log.WithError(err).Errorf("adapter: failed to send address change report for thing with ID %s", seed.ID)
+		}
+	}
+
 	return nil
 }
 
-// createThingState creates new state of a thing and acquires a new address for it.
+// createThingState creates new state of a thing and acquires a new address for it using the configured address allocator.
 func (a *adapter) createThingState(seed *ThingSeed) (ThingState, error) {
 	var err error
 
 	address := seed.CustomAddress
 	if address == "" {
-		address, err = a.state.acquireAddress()
+		address, err = a.state.acquireAddress(seed.ID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to accquire a new address for thing with ID %s: %w", seed.ID, err)
 		}
@@ -424,6 +439,8 @@ func (a *adapter) destroyThing(address string) error {
 		a.unregisterThing(t)
 	}
 
+	a.publisher.PublishThingEvent(newExclusionEvent(address))
+
 	err = a.sendExclusionReport(address)
 	if err != nil {
 		return fmt.Errorf("failed to send exclusion report for thing with address %s: %w", address, err)
@@ -448,3 +465,22 @@ func (a *adapter) sendExclusionReport(address string) error {
 
 	return a.publisher.PublishAdapterMessage(msg)
 }
+
+func (a *adapter) sendAddressChangeReport(id, address, previousAddress string) error {
+	report := AddressChangeReport{
+		ID:              id,
+		Address:         address,
+		PreviousAddress: previousAddress,
+	}
+
+	msg := fimpgo.NewObjectMessage(
+		EvtThingAddressChangeReport,
+		fimptype.ServiceNameT(a.name),
+		report,
+		nil,
+		nil,
+		nil,
+	)
+
+	return a.publisher.PublishAdapterMessage(msg)
+}
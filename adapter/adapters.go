@@ -0,0 +1,134 @@
+package adapter
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/futurehomeno/fimpgo"
+	"github.com/futurehomeno/fimpgo/fimptype"
+
+	"github.com/futurehomeno/cliffhanger/router"
+	"github.com/futurehomeno/cliffhanger/task"
+)
+
+// Adapters is a collection of adapter instances with distinct addresses running within a single application.
+// It acts as a service registry dispatching lookups to the instance owning the service, so it can be used for service routing.
+type Adapters interface {
+	ServiceRegistry
+
+	// Adapter returns an adapter instance with a given address. Returns nil if instance was not found.
+	Adapter(address string) Adapter
+	// All returns all adapter instances ordered by their addresses.
+	All() []Adapter
+	// Addresses returns addresses of all adapter instances.
+	Addresses() []string
+}
+
+// NewAdapters creates a new collection of adapter instances. Instances must have distinct addresses.
+func NewAdapters(adapters ...Adapter) Adapters {
+	adapters = slices.Clone(adapters)
+
+	slices.SortFunc(adapters, func(a, b Adapter) int {
+		return strings.Compare(a.Address(), b.Address())
+	})
+
+	return &adapterSet{
+		adapters: adapters,
+	}
+}
+
+type adapterSet struct {
+	adapters []Adapter
+}
+
+// Adapter returns an adapter instance with a given address. Returns nil if instance was not found.
+func (s *adapterSet) Adapter(address string) Adapter {
+	for _, a := range s.adapters {
+		if a.Address() == address {
+			return a
+		}
+	}
+
+	return nil
+}
+
+// All returns all adapter instances ordered by their addresses.
+func (s *adapterSet) All() []Adapter {
+	return slices.Clone(s.adapters)
+}
+
+// Addresses returns addresses of all adapter instances.
+func (s *adapterSet) Addresses() []string {
+	addresses := make([]string, 0, len(s.adapters))
+
+	for _, a := range s.adapters {
+		addresses = append(addresses, a.Address())
+	}
+
+	return addresses
+}
+
+// Services returns all services from all things of all instances that match the provided name. If empty all services are returned.
+func (s *adapterSet) Services(name fimptype.ServiceNameT) []Service {
+	var services []Service
+
+	for _, a := range s.adapters {
+		services = append(services, a.Services(name)...)
+	}
+
+	return services
+}
+
+// ServiceByTopic returns a service based on its topic from the instance addressed by the topic. Returns nil if service was not found.
+func (s *adapterSet) ServiceByTopic(topic string) Service {
+	address, err := fimpgo.NewAddressFromString(topic)
+	if err == nil {
+		if a := s.Adapter(address.ResourceAddress); a != nil {
+			return a.ServiceByTopic(topic)
+		}
+
+		return nil
+	}
+
+	for _, a := range s.adapters {
+		if service := a.ServiceByTopic(topic); service != nil {
+			return service
+		}
+	}
+
+	return nil
+}
+
+// IsInitialized returns true if all adapter instances are initialized.
+func (s *adapterSet) IsInitialized() bool {
+	for _, a := range s.adapters {
+		if !a.IsInitialized() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RouteAdapters returns adapter routing for all instances within the collection.
+func RouteAdapters(adapters Adapters) []*router.Routing {
+	var routing []*router.Routing
+
+	for _, a := range adapters.All() {
+		routing = append(routing, RouteAdapter(a)...)
+	}
+
+	return routing
+}
+
+// TaskAdapters returns adapter tasks for all instances within the collection.
+func TaskAdapters(adapters Adapters, reportingInterval time.Duration, reportingVoters ...task.Voter) []*task.Task {
+	var tasks []*task.Task
+
+	for _, a := range adapters.All() {
+		tasks = append(tasks, TaskAdapter(a, reportingInterval, slices.Clone(reportingVoters)...)...)
+	}
+
+	return tasks
+}
@@ -0,0 +1,134 @@
+package adapter_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/futurehomeno/fimpgo"
+	"github.com/futurehomeno/fimpgo/fimptype"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/adapter/service/outbinswitch"
+	"github.com/futurehomeno/cliffhanger/event"
+	"github.com/futurehomeno/cliffhanger/router"
+	"github.com/futurehomeno/cliffhanger/task"
+	adapterhelper "github.com/futurehomeno/cliffhanger/test/helper/adapter"
+	mockedadapter "github.com/futurehomeno/cliffhanger/test/mocks/adapter"
+	mockedoutbinswitch "github.com/futurehomeno/cliffhanger/test/mocks/adapter/service/outbinswitch"
+	"github.com/futurehomeno/cliffhanger/test/suite"
+)
+
+func TestAdapters(t *testing.T) { //nolint:paralleltest
+	var adapters adapter.Adapters
+
+	s := &suite.Suite{
+		Cases: []*suite.Case{
+			{
+				Name:     "multiple adapter instances are dispatched by resource address",
+				TearDown: adapterhelper.TearDownAdapter(testAdapterWorkDir),
+				Setup: setupAdapters(
+					&adapters,
+					mockedoutbinswitch.NewController(t),
+					mockedoutbinswitch.NewController(t).
+						MockedBinarySwitchBinarySet(true, nil, true).
+						MockedBinarySwitchBinaryReport(true, nil, true),
+				),
+				Nodes: []*suite.Node{
+					{
+						Name:    "get node of the second instance",
+						Command: suite.StringMessage("pt:j1/mt:cmd/rt:ad/rn:test_adapter/ad:2", adapter.CmdNetworkGetNode, testAdapterName, testThingAddressB),
+						Expectations: []*suite.Expectation{
+							suite.NewExpectation().
+								ExpectTopic("pt:j1/mt:evt/rt:ad/rn:test_adapter/ad:2").
+								ExpectType(adapter.EvtNetworkNodeReport).
+								ExpectService(testAdapterName).
+								ExactlyOnce(),
+							suite.NewExpectation().
+								ExpectTopic(testAdapterEvtTopic).
+								ExpectType(adapter.EvtNetworkNodeReport).
+								ExpectService(testAdapterName).
+								Never(),
+						},
+					},
+					{
+						Name:    "set binary switch of the second instance",
+						Command: suite.BoolMessage("pt:j1/mt:cmd/rt:dev/rn:test_adapter/ad:2/sv:out_bin_switch/ad:2", outbinswitch.CmdBinarySet, outbinswitch.OutBinSwitch, true),
+						Expectations: []*suite.Expectation{
+							suite.ExpectBool("pt:j1/mt:evt/rt:dev/rn:test_adapter/ad:2/sv:out_bin_switch/ad:2", outbinswitch.EvtBinaryReport, outbinswitch.OutBinSwitch, true),
+						},
+					},
+					{
+						Name: "instances are persisted separately",
+						InitCallbacks: []suite.Callback{
+							func(t *testing.T) {
+								t.Helper()
+
+								assert.Equal(t, []string{"1", "2"}, adapters.Addresses())
+								assert.True(t, adapters.IsInitialized())
+								assert.Len(t, adapters.Services(outbinswitch.OutBinSwitch), 2)
+								assert.Nil(t, adapters.ServiceByTopic("pt:j1/mt:cmd/rt:dev/rn:test_adapter/ad:3/sv:out_bin_switch/ad:2"))
+
+								for _, address := range adapters.Addresses() {
+									_, err := os.Stat(filepath.Join(testAdapterWorkDir, "data", adapter.StateFilename(address)))
+									assert.NoError(t, err)
+								}
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	s.Run(t)
+}
+
+func setupAdapters(adapters *adapter.Adapters, controllers ...*mockedoutbinswitch.Controller) suite.BaseSetup {
+	return func(t *testing.T, mqtt *fimpgo.MqttTransport) ([]*router.Routing, []*task.Task, []suite.Mock) {
+		t.Helper()
+
+		var (
+			instances []adapter.Adapter
+			mocks     []suite.Mock
+		)
+
+		for i, controller := range controllers {
+			address := strconv.Itoa(i + 1)
+
+			state, err := adapter.NewState(testAdapterWorkDir, adapter.WithStateFilename(adapter.StateFilename(address)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			factory := adapterhelper.FactoryHelper(func(a adapter.Adapter, publisher adapter.Publisher, thingState adapter.ThingState) (adapter.Thing, error) {
+				return adapter.NewThing(publisher, thingState, &adapter.ThingConfig{
+					InclusionReport: &fimptype.ThingInclusionReport{Address: thingState.Address()},
+					Connector:       mockedadapter.NewDefaultConnector(t),
+				}, outbinswitch.NewService(publisher, &outbinswitch.Config{
+					Specification: outbinswitch.Specification("test_adapter", a.Address(), thingState.Address(), nil),
+					Controller:    controller,
+				})), nil
+			})
+
+			ad := adapter.NewAdapter(mqtt, event.NewManager(), factory, state, "test_adapter", address)
+
+			if err := ad.InitializeThings(); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := ad.EnsureThings(adapter.ThingSeeds{{ID: "B", CustomAddress: testThingAddressB}}); err != nil {
+				t.Fatal(err)
+			}
+
+			instances = append(instances, ad)
+			mocks = append(mocks, controller)
+		}
+
+		*adapters = adapter.NewAdapters(instances[1], instances[0])
+
+		return router.Combine(adapter.RouteAdapters(*adapters), outbinswitch.RouteService(*adapters)), nil, mocks
+	}
+}
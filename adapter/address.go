@@ -0,0 +1,140 @@
+package adapter
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"time"
+)
+
+// maxAddressDerivationAttempts is a maximum number of attempts of the deterministic allocator to derive an unused address.
+const maxAddressDerivationAttempts = 1000
+
+// AddressChangeReport is a report sent when a thing known to the adapter is included under a different address than before.
+type AddressChangeReport struct {
+	ID              string `json:"id"`
+	Address         string `json:"address"`
+	PreviousAddress string `json:"previous_address"`
+}
+
+// AddressRegistry provides address allocators with access to the persistent addressing state of the adapter.
+type AddressRegistry interface {
+	// Index returns the current value of the sequential address index.
+	Index() int
+	// SetIndex updates the sequential address index.
+	SetIndex(index int)
+	// InUse returns true if the address is assigned to an existing thing.
+	InUse(address string) bool
+	// Released returns the address assigned to a removed thing with a given ID and the time of its removal.
+	Released(id string) (address string, releasedAt time.Time, ok bool)
+	// ForgetReleased removes all records of addresses released before the provided time.
+	ForgetReleased(before time.Time)
+}
+
+// AddressAllocator is an interface representing a strategy of assigning addresses to newly created things.
+type AddressAllocator interface {
+	// Allocate returns an address for a new thing with a given ID.
+	Allocate(id string, registry AddressRegistry) (string, error)
+}
+
+// AddressAllocatorFn is an adapter allowing usage of anonymous function as an address allocator.
+type AddressAllocatorFn func(id string, registry AddressRegistry) (string, error)
+
+// Allocate returns an address for a new thing with a given ID.
+func (f AddressAllocatorFn) Allocate(id string, registry AddressRegistry) (string, error) {
+	return f(id, registry)
+}
+
+// NewSequentialAddressAllocator creates an allocator incrementing the address index. This is the default allocator.
+func NewSequentialAddressAllocator() AddressAllocator {
+	return AddressAllocatorFn(func(_ string, registry AddressRegistry) (string, error) {
+		index := registry.Index()
+
+		for {
+			index++
+
+			if !registry.InUse(strconv.Itoa(index)) {
+				break
+			}
+		}
+
+		registry.SetIndex(index)
+
+		return strconv.Itoa(index), nil
+	})
+}
+
+// NewGapFillingAddressAllocator creates an allocator assigning the lowest positive address not used by any existing thing.
+func NewGapFillingAddressAllocator() AddressAllocator {
+	return AddressAllocatorFn(func(_ string, registry AddressRegistry) (string, error) {
+		index := 1
+
+		for registry.InUse(strconv.Itoa(index)) {
+			index++
+		}
+
+		if index > registry.Index() {
+			registry.SetIndex(index)
+		}
+
+		return strconv.Itoa(index), nil
+	})
+}
+
+// AddressDerivationFn derives an address from the thing ID.
+type AddressDerivationFn func(id string) string
+
+// NewDeterministicAddressAllocator creates an allocator deriving the address from the thing ID.
+// If the derived address is already in use, it is derived again from the ID extended with a sequence number.
+// Allocation fails if no unused address is derived within a limited number of attempts.
+// If derivation function is nil, a decimal representation of 32-bit FNV-1a hash of the ID is used.
+func NewDeterministicAddressAllocator(derive AddressDerivationFn) AddressAllocator {
+	if derive == nil {
+		derive = hashAddress
+	}
+
+	return AddressAllocatorFn(func(id string, registry AddressRegistry) (string, error) {
+		address := derive(id)
+
+		for i := 1; address == "" || registry.InUse(address); i++ {
+			if i > maxAddressDerivationAttempts {
+				return "", fmt.Errorf("failed to derive an unused address for thing with ID %s in %d attempts", id, maxAddressDerivationAttempts)
+			}
+
+			address = derive(id + "#" + strconv.Itoa(i))
+		}
+
+		return address, nil
+	})
+}
+
+// NewReusingAddressAllocator creates an allocator assigning a thing the same address it had before being removed,
+// as long as the address was released within the retention period and is not used by another thing.
+// Otherwise, the address is assigned by the fallback allocator. Retention of zero keeps released addresses indefinitely.
+// If fallback allocator is nil, the sequential allocator is used.
+func NewReusingAddressAllocator(retention time.Duration, fallback AddressAllocator) AddressAllocator {
+	if fallback == nil {
+		fallback = NewSequentialAddressAllocator()
+	}
+
+	return AddressAllocatorFn(func(id string, registry AddressRegistry) (string, error) {
+		if retention > 0 {
+			registry.ForgetReleased(time.Now().Add(-retention))
+		}
+
+		address, _, ok := registry.Released(id)
+		if ok && !registry.InUse(address) {
+			return address, nil
+		}
+
+		return fallback.Allocate(id, registry)
+	})
+}
+
+// hashAddress returns a decimal representation of 32-bit FNV-1a hash of the ID.
+func hashAddress(id string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(id))
+
+	return strconv.FormatUint(uint64(h.Sum32()), 10)
+}
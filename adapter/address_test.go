@@ -0,0 +1,291 @@
+package adapter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/futurehomeno/fimpgo"
+	"github.com/futurehomeno/fimpgo/fimptype"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/event"
+	"github.com/futurehomeno/cliffhanger/router"
+	"github.com/futurehomeno/cliffhanger/task"
+	adapterhelper "github.com/futurehomeno/cliffhanger/test/helper/adapter"
+	mockedadapter "github.com/futurehomeno/cliffhanger/test/mocks/adapter"
+	"github.com/futurehomeno/cliffhanger/test/suite"
+)
+
+func TestAdapter_AddressAllocation(t *testing.T) { //nolint:paralleltest
+	var ad adapter.Adapter
+
+	createThing := func(t *testing.T) {
+		t.Helper()
+
+		assert.NoError(t, ad.CreateThing(&adapter.ThingSeed{ID: "X"}))
+	}
+
+	destroyThing := func(t *testing.T) {
+		t.Helper()
+
+		assert.NoError(t, ad.DestroyThingByID("X"))
+	}
+
+	s := &suite.Suite{
+		Cases: []*suite.Case{
+			{
+				Name:     "sequential allocator reports changed address of a re-added thing",
+				TearDown: adapterhelper.TearDownAdapter(testAdapterWorkDir),
+				Setup:    setupAddressAllocation(&ad, adapter.NewSequentialAddressAllocator()),
+				Nodes: []*suite.Node{
+					{
+						Name:          "create thing",
+						InitCallbacks: []suite.Callback{createThing},
+						Expectations: []*suite.Expectation{
+							expectInclusionReport("1"),
+						},
+					},
+					{
+						Name:          "destroy thing",
+						InitCallbacks: []suite.Callback{destroyThing},
+						Expectations: []*suite.Expectation{
+							suite.ExpectObject(testAdapterEvtTopic, adapter.EvtThingExclusionReport, testAdapterName, fimptype.ThingExclusionReport{Address: "1"}),
+						},
+					},
+					{
+						Name:          "re-create thing",
+						InitCallbacks: []suite.Callback{createThing},
+						Expectations: []*suite.Expectation{
+							expectInclusionReport("2"),
+							suite.ExpectObject(testAdapterEvtTopic, adapter.EvtThingAddressChangeReport, testAdapterName, &adapter.AddressChangeReport{
+								ID:              "X",
+								Address:         "2",
+								PreviousAddress: "1",
+							}),
+						},
+					},
+				},
+			},
+			{
+				Name:     "reusing allocator assigns the same address to a re-added thing",
+				TearDown: adapterhelper.TearDownAdapter(testAdapterWorkDir),
+				Setup:    setupAddressAllocation(&ad, adapter.NewReusingAddressAllocator(time.Hour, nil)),
+				Nodes: []*suite.Node{
+					{
+						Name:          "create thing",
+						InitCallbacks: []suite.Callback{createThing},
+						Expectations: []*suite.Expectation{
+							expectInclusionReport("1"),
+						},
+					},
+					{
+						Name:          "destroy thing",
+						InitCallbacks: []suite.Callback{destroyThing},
+						Expectations: []*suite.Expectation{
+							suite.ExpectObject(testAdapterEvtTopic, adapter.EvtThingExclusionReport, testAdapterName, fimptype.ThingExclusionReport{Address: "1"}),
+						},
+					},
+					{
+						Name:          "re-create thing",
+						InitCallbacks: []suite.Callback{createThing},
+						Expectations: []*suite.Expectation{
+							expectInclusionReport("1"),
+							suite.NewExpectation().
+								ExpectTopic(testAdapterEvtTopic).
+								ExpectType(adapter.EvtThingAddressChangeReport).
+								ExpectService(testAdapterName).
+								Never(),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	s.Run(t)
+}
+
+func TestSequentialAddressAllocator(t *testing.T) {
+	t.Parallel()
+
+	r := newTestAddressRegistry(2, "3", "4")
+
+	address, err := adapter.NewSequentialAddressAllocator().Allocate("X", r)
+	assert.NoError(t, err)
+	assert.Equal(t, "5", address)
+	assert.Equal(t, 5, r.Index())
+}
+
+func TestGapFillingAddressAllocator(t *testing.T) {
+	t.Parallel()
+
+	r := newTestAddressRegistry(4, "1", "2", "4")
+
+	address, err := adapter.NewGapFillingAddressAllocator().Allocate("X", r)
+	assert.NoError(t, err)
+	assert.Equal(t, "3", address)
+	assert.Equal(t, 4, r.Index())
+
+	r = newTestAddressRegistry(0, "1", "2")
+
+	address, err = adapter.NewGapFillingAddressAllocator().Allocate("X", r)
+	assert.NoError(t, err)
+	assert.Equal(t, "3", address)
+	assert.Equal(t, 3, r.Index())
+}
+
+func TestDeterministicAddressAllocator(t *testing.T) {
+	t.Parallel()
+
+	allocator := adapter.NewDeterministicAddressAllocator(nil)
+
+	first, err := allocator.Allocate("X", newTestAddressRegistry(0))
+	assert.NoError(t, err)
+
+	second, err := allocator.Allocate("X", newTestAddressRegistry(0))
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	other, err := allocator.Allocate("Y", newTestAddressRegistry(0))
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, other)
+
+	collided, err := allocator.Allocate("X", newTestAddressRegistry(0, first))
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, collided)
+
+	custom := adapter.NewDeterministicAddressAllocator(func(id string) string {
+		return "dev-" + id
+	})
+
+	address, err := custom.Allocate("X", newTestAddressRegistry(0))
+	assert.NoError(t, err)
+	assert.Equal(t, "dev-X", address)
+
+	address, err = custom.Allocate("X", newTestAddressRegistry(0, "dev-X"))
+	assert.NoError(t, err)
+	assert.Equal(t, "dev-X#1", address)
+
+	constant := adapter.NewDeterministicAddressAllocator(func(string) string {
+		return "dev"
+	})
+
+	_, err = constant.Allocate("X", newTestAddressRegistry(0, "dev"))
+	assert.Error(t, err)
+
+	_, err = adapter.NewDeterministicAddressAllocator(func(string) string { return "" }).Allocate("X", newTestAddressRegistry(0))
+	assert.Error(t, err)
+}
+
+func TestReusingAddressAllocator(t *testing.T) {
+	t.Parallel()
+
+	allocator := adapter.NewReusingAddressAllocator(time.Hour, adapter.NewGapFillingAddressAllocator())
+
+	r := newTestAddressRegistry(5, "1")
+	r.released["X"] = testReleasedAddress{address: "5", releasedAt: time.Now().Add(-time.Minute)}
+	r.released["Y"] = testReleasedAddress{address: "1", releasedAt: time.Now().Add(-time.Minute)}
+	r.released["Z"] = testReleasedAddress{address: "3", releasedAt: time.Now().Add(-2 * time.Hour)}
+
+	address, err := allocator.Allocate("X", r)
+	assert.NoError(t, err)
+	assert.Equal(t, "5", address)
+
+	address, err = allocator.Allocate("Y", r)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", address, "address of Y is used by another thing")
+
+	address, err = allocator.Allocate("Z", r)
+	assert.NoError(t, err)
+	assert.Equal(t, "2", address, "address of Z is past retention")
+
+	_, _, ok := r.Released("Z")
+	assert.False(t, ok)
+}
+
+func setupAddressAllocation(ad *adapter.Adapter, allocator adapter.AddressAllocator) suite.BaseSetup {
+	return func(t *testing.T, mqtt *fimpgo.MqttTransport) ([]*router.Routing, []*task.Task, []suite.Mock) {
+		t.Helper()
+
+		state, err := adapter.NewState(testAdapterWorkDir, adapter.WithAddressAllocator(allocator))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		factory := adapterhelper.FactoryHelper(func(_ adapter.Adapter, publisher adapter.Publisher, thingState adapter.ThingState) (adapter.Thing, error) {
+			return adapter.NewThing(publisher, thingState, &adapter.ThingConfig{
+				InclusionReport: &fimptype.ThingInclusionReport{Address: thingState.Address()},
+				Connector:       mockedadapter.NewDefaultConnector(t),
+			}), nil
+		})
+
+		*ad = adapter.NewAdapter(mqtt, event.NewManager(), factory, state, "test_adapter", "1")
+
+		return adapter.RouteAdapter(*ad), nil, nil
+	}
+}
+
+func expectInclusionReport(address string) *suite.Expectation {
+	return suite.NewExpectation().
+		ExpectTopic(testAdapterEvtTopic).
+		ExpectType(adapter.EvtThingInclusionReport).
+		ExpectService(testAdapterName).
+		Expect(router.MessageVoterFn(func(m *fimpgo.Message) bool {
+			report := &fimptype.ThingInclusionReport{}
+
+			return m.Payload.GetObjectValue(report) == nil && report.Address == address
+		})).
+		ExactlyOnce()
+}
+
+type testReleasedAddress struct {
+	address    string
+	releasedAt time.Time
+}
+
+type testAddressRegistry struct {
+	index    int
+	inUse    map[string]bool
+	released map[string]testReleasedAddress
+}
+
+func newTestAddressRegistry(index int, inUse ...string) *testAddressRegistry {
+	r := &testAddressRegistry{
+		index:    index,
+		inUse:    make(map[string]bool),
+		released: make(map[string]testReleasedAddress),
+	}
+
+	for _, address := range inUse {
+		r.inUse[address] = true
+	}
+
+	return r
+}
+
+func (r *testAddressRegistry) Index() int {
+	return r.index
+}
+
+func (r *testAddressRegistry) SetIndex(index int) {
+	r.index = index
+}
+
+func (r *testAddressRegistry) InUse(address string) bool {
+	return r.inUse[address]
+}
+
+func (r *testAddressRegistry) Released(id string) (string, time.Time, bool) {
+	released, ok := r.released[id]
+
+	return released.address, released.releasedAt, ok
+}
+
+func (r *testAddressRegistry) ForgetReleased(before time.Time) {
+	for id, released := range r.released {
+		if released.releasedAt.Before(before) {
+			delete(r.released, id)
+		}
+	}
+}
@@ -0,0 +1,404 @@
+package adapter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/futurehomeno/fimpgo/fimptype"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/futurehomeno/cliffhanger/adapter/cache"
+	"github.com/futurehomeno/cliffhanger/database"
+)
+
+// StateArchiveVersion is a version of the state archive format produced by the adapter.
+const StateArchiveVersion = 1
+
+// serviceTopicAddressPattern matches the thing address within a service topic, optionally followed by a channel suffix.
+var serviceTopicAddressPattern = regexp.MustCompile(`(/sv:[^/]+/ad:)([^/_]+)((?:_[^/]*)?)$`)
+
+// ArchiveConfig represents a configuration of the adapter state export and import.
+type ArchiveConfig struct {
+	// Database is a database holding data related to the adapter. Optional if no domains are provided.
+	Database database.Database
+	// Domains is a list of database domains related to the adapter, e.g. virtualmeter.DatabaseDomain.
+	Domains []string
+}
+
+// StateArchive is a versioned archive of the adapter state and related database domains.
+type StateArchive struct {
+	Version      int                          `json:"version"`
+	Adapter      fimptype.ResourceNameT       `json:"adapter"`
+	CreatedAt    time.Time                    `json:"created_at"`
+	AddressIndex int                          `json:"address_index"`
+	Things       []*ArchivedThing             `json:"things"`
+	Domains      map[string][]*database.Entry `json:"domains,omitempty"`
+}
+
+// ArchivedThing is a state of a single thing within the archive.
+type ArchivedThing struct {
+	ID       string            `json:"id"`
+	Address  string            `json:"address"`
+	Info     json.RawMessage   `json:"info,omitempty"`
+	State    json.RawMessage   `json:"state,omitempty"`
+	Alias    string            `json:"alias,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	ReportingStrategies map[string]*cache.ReportingStrategySpec `json:"reporting_strategies,omitempty"`
+}
+
+// StateImportReport is a report describing the outcome of the state import.
+type StateImportReport struct {
+	Imported []*ImportedThing `json:"imported"`
+	Skipped  []*ImportedThing `json:"skipped,omitempty"`
+}
+
+// ImportedThing describes the outcome of the import of a single thing.
+type ImportedThing struct {
+	ID      string `json:"id"`
+	Address string `json:"address"`
+	// PreviousAddress is set if the thing had to be assigned a new address, because the archived one was already taken.
+	PreviousAddress string `json:"previous_address,omitempty"`
+	// Reason is set if the thing was skipped.
+	Reason string `json:"reason,omitempty"`
+}
+
+// StateArchiver is an optional interface of the adapter allowing to export and import its state.
+type StateArchiver interface {
+	// ExportState exports state of the adapter and its things together with related database domains.
+	ExportState(cfg *ArchiveConfig) (*StateArchive, error)
+	// ImportState validates the archive and atomically imports things and related database domains into the adapter.
+	// Things already present in the adapter are skipped, while things with colliding addresses are assigned new ones.
+	ImportState(archive *StateArchive, cfg *ArchiveConfig) (*StateImportReport, error)
+}
+
+// ExportState exports state of the adapter and its things together with related database domains.
+func (a *adapter) ExportState(cfg *ArchiveConfig) (*StateArchive, error) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+
+	addressIndex, models := a.state.snapshot()
+
+	archive := &StateArchive{
+		Version:      StateArchiveVersion,
+		Adapter:      a.name,
+		CreatedAt:    time.Now(),
+		AddressIndex: addressIndex,
+		Things:       make([]*ArchivedThing, 0, len(models)),
+	}
+
+	for _, m := range models {
+		archive.Things = append(archive.Things, &ArchivedThing{
+			ID:       m.ID,
+			Address:  m.Address,
+			Info:     m.Info,
+			State:    m.State,
+			Alias:    m.Alias,
+			Metadata: m.Metadata,
+
+			ReportingStrategies: m.ReportingStrategies,
+		})
+	}
+
+	if len(cfg.Domains) > 0 {
+		archive.Domains = make(map[string][]*database.Entry, len(cfg.Domains))
+	}
+
+	for _, domain := range cfg.Domains {
+		entries, err := database.DumpDomain(cfg.Database, domain)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export database domain %s: %w", domain, err)
+		}
+
+		archive.Domains[domain] = entries
+	}
+
+	return archive, nil
+}
+
+// ImportState validates the archive and atomically imports things and related database domains into the adapter.
+// Things already present in the adapter are skipped, while things with colliding addresses are assigned new ones.
+func (a *adapter) ImportState(archive *StateArchive, cfg *ArchiveConfig) (*StateImportReport, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if err := a.validateArchive(archive, cfg); err != nil {
+		return nil, fmt.Errorf("invalid state archive: %w", err)
+	}
+
+	addressIndex, models, remapped, report := a.planImport(archive)
+
+	things := make([]Thing, 0, len(models))
+
+	for _, m := range models {
+		t, err := a.factory.Create(a, a.publisher, a.state.prepare(m))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create thing with ID %s: %w", m.ID, err)
+		}
+
+		things = append(things, t)
+	}
+
+	rollback, err := a.importDomains(archive, cfg, report.archivedAddresses(), remapped)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.state.merge(addressIndex, models); err != nil {
+		rollback()
+
+		return nil, fmt.Errorf("failed to import thing states: %w", err)
+	}
+
+	for _, t := range things {
+		if _, err := t.SendInclusionReport(true); err != nil {
+			log.WithError(err).Errorf("adapter: failed to send inclusion report for imported thing with address %s", t.Address())
+		}
+
+		a.registerThing(t)
+	}
+
+	for _, imported := range report.Imported {
+		if imported.PreviousAddress == "" {
+			continue
+		}
+
+		if err := a.sendAddressChangeReport(imported.ID, imported.Address, imported.PreviousAddress); err != nil {
+			log.WithError(err).Errorf("adapter: failed to send address change report for imported thing with ID %s", imported.ID)
+		}
+	}
+
+	return report, nil
+}
+
+// validateArchive checks if the archive is consistent and can be imported into the adapter.
+func (a *adapter) validateArchive(archive *StateArchive, cfg *ArchiveConfig) error {
+	if archive == nil {
+		return errors.New("archive is empty")
+	}
+
+	if archive.Version != StateArchiveVersion {
+		return fmt.Errorf("unsupported version %d", archive.Version)
+	}
+
+	if archive.Adapter != a.name {
+		return fmt.Errorf("archive of adapter %s cannot be imported into adapter %s", archive.Adapter, a.name)
+	}
+
+	ids := make(map[string]bool)
+	addresses := make(map[string]bool)
+
+	for _, t := range archive.Things {
+		if t == nil || t.ID == "" || t.Address == "" {
+			return errors.New("thing without ID or address")
+		}
+
+		if ids[t.ID] {
+			return fmt.Errorf("duplicated thing ID %s", t.ID)
+		}
+
+		if addresses[t.Address] {
+			return fmt.Errorf("duplicated thing address %s", t.Address)
+		}
+
+		if (len(t.Info) > 0 && !json.Valid(t.Info)) || (len(t.State) > 0 && !json.Valid(t.State)) {
+			return fmt.Errorf("malformed info or state of thing with ID %s", t.ID)
+		}
+
+		ids[t.ID] = true
+		addresses[t.Address] = true
+	}
+
+	for domain := range archive.Domains {
+		if !slices.Contains(cfg.Domains, domain) {
+			return fmt.Errorf("database domain %s is not supported by the adapter", domain)
+		}
+
+		if cfg.Database == nil {
+			return errors.New("database is not configured")
+		}
+	}
+
+	return nil
+}
+
+// planImport determines which things are going to be imported and under which addresses.
+// Returns the new address index, models to persist, a map of remapped addresses and the import report.
+func (a *adapter) planImport(archive *StateArchive) (int, []*thingStateModel, map[string]string, *StateImportReport) {
+	addressIndex, current := a.state.snapshot()
+	addressIndex = max(addressIndex, archive.AddressIndex)
+
+	ids := make(map[string]bool)
+	taken := make(map[string]bool)
+	used := make(map[string]bool)
+
+	for _, m := range current {
+		ids[m.ID] = true
+		taken[m.Address] = true
+		used[m.Address] = true
+	}
+
+	for _, t := range archive.Things {
+		used[t.Address] = true
+	}
+
+	var models []*thingStateModel
+
+	remapped := make(map[string]string)
+	report := &StateImportReport{}
+
+	for _, t := range archive.Things {
+		if ids[t.ID] {
+			report.Skipped = append(report.Skipped, &ImportedThing{
+				ID:      t.ID,
+				Address: t.Address,
+				Reason:  "thing with the same ID already exists",
+			})
+
+			continue
+		}
+
+		imported := &ImportedThing{ID: t.ID, Address: t.Address}
+
+		if taken[t.Address] {
+			addressIndex++
+
+			for used[strconv.Itoa(addressIndex)] {
+				addressIndex++
+			}
+
+			imported.PreviousAddress = t.Address
+			imported.Address = strconv.Itoa(addressIndex)
+			remapped[t.Address] = imported.Address
+			used[imported.Address] = true
+		}
+
+		taken[imported.Address] = true
+
+		strategies := t.ReportingStrategies
+
+		if imported.PreviousAddress != "" && len(strategies) > 0 {
+			strategies = make(map[string]*cache.ReportingStrategySpec, len(t.ReportingStrategies))
+
+			for key, spec := range t.ReportingStrategies {
+				topic := strings.TrimSuffix(key, "#"+spec.Event)
+
+				strategies[ReportingStrategyKey(remapTopicAddress(topic, map[string]string{t.Address: imported.Address}), spec.Event)] = spec
+			}
+		}
+
+		models = append(models, &thingStateModel{
+			ID:       t.ID,
+			Address:  imported.Address,
+			Info:     t.Info,
+			State:    t.State,
+			Alias:    t.Alias,
+			Metadata: t.Metadata,
+
+			ReportingStrategies: strategies,
+		})
+
+		report.Imported = append(report.Imported, imported)
+	}
+
+	return addressIndex, models, remapped, report
+}
+
+// importDomains imports database domains from the archive, remapping service topics of things which were assigned new addresses.
+// Entries related to things which were not imported are dropped, so data of things already present in the adapter is not overwritten.
+// Returns a function restoring the previous contents of all imported domains.
+func (a *adapter) importDomains(archive *StateArchive, cfg *ArchiveConfig, imported map[string]bool, remapped map[string]string) (func(), error) {
+	backups := make(map[string][]*database.Entry)
+
+	rollback := func() {
+		for domain, entries := range backups {
+			if err := database.RestoreDomain(cfg.Database, domain, entries, true); err != nil {
+				log.WithError(err).Errorf("adapter: failed to roll back database domain %s", domain)
+			}
+		}
+	}
+
+	for _, domain := range slices.Sorted(maps.Keys(archive.Domains)) {
+		backup, err := database.DumpDomain(cfg.Database, domain)
+		if err != nil {
+			rollback()
+
+			return nil, fmt.Errorf("failed to back up database domain %s: %w", domain, err)
+		}
+
+		backups[domain] = backup
+
+		entries := make([]*database.Entry, 0, len(archive.Domains[domain]))
+
+		for _, e := range archive.Domains[domain] {
+			if address, ok := topicAddress(e.Key); ok && !imported[address] {
+				continue
+			}
+
+			entries = append(entries, &database.Entry{
+				Key:       remapTopicAddress(e.Key, remapped),
+				Value:     e.Value,
+				ExpiresAt: e.ExpiresAt,
+			})
+		}
+
+		if err := database.RestoreDomain(cfg.Database, domain, entries, false); err != nil {
+			rollback()
+
+			return nil, fmt.Errorf("failed to import database domain %s: %w", domain, err)
+		}
+	}
+
+	return rollback, nil
+}
+
+// archivedAddresses returns archived addresses of all imported things.
+func (r *StateImportReport) archivedAddresses() map[string]bool {
+	addresses := make(map[string]bool, len(r.Imported))
+
+	for _, imported := range r.Imported {
+		if imported.PreviousAddress != "" {
+			addresses[imported.PreviousAddress] = true
+		} else {
+			addresses[imported.Address] = true
+		}
+	}
+
+	return addresses
+}
+
+// topicAddress returns the thing address within a key ending with a service topic.
+func topicAddress(key string) (string, bool) {
+	m := serviceTopicAddressPattern.FindStringSubmatch(key)
+	if m == nil {
+		return "", false
+	}
+
+	return m[2], true
+}
+
+// remapTopicAddress replaces the thing address within a key ending with a service topic, if the address was remapped.
+func remapTopicAddress(key string, remapped map[string]string) string {
+	if len(remapped) == 0 {
+		return key
+	}
+
+	m := serviceTopicAddressPattern.FindStringSubmatchIndex(key)
+	if m == nil {
+		return key
+	}
+
+	address, ok := remapped[key[m[4]:m[5]]]
+	if !ok {
+		return key
+	}
+
+	return key[:m[4]] + address + key[m[5]:]
+}
@@ -0,0 +1,288 @@
+package adapter_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/futurehomeno/fimpgo"
+	"github.com/futurehomeno/fimpgo/fimptype"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/database"
+	"github.com/futurehomeno/cliffhanger/router"
+	"github.com/futurehomeno/cliffhanger/task"
+	adapterhelper "github.com/futurehomeno/cliffhanger/test/helper/adapter"
+	databasehelper "github.com/futurehomeno/cliffhanger/test/helper/database"
+	mockedadapter "github.com/futurehomeno/cliffhanger/test/mocks/adapter"
+	"github.com/futurehomeno/cliffhanger/test/suite"
+)
+
+const (
+	testDatabaseWorkDir = "./testdata/database"
+	testArchiveDomain   = "test_domain"
+)
+
+func TestRouteStateArchive(t *testing.T) { //nolint:paralleltest
+	var (
+		ad adapter.Adapter
+		db database.Database
+	)
+
+	s := &suite.Suite{
+		Cases: []*suite.Case{
+			{
+				Name:     "export and import adapter state",
+				TearDown: adapterhelper.TearDownAdapter(testAdapterWorkDir),
+				Setup:    setupStateArchive(&ad, &db),
+				Nodes: []*suite.Node{
+					{
+						Name:    "export state",
+						Command: suite.NullMessage(testAdapterCmdTopic, adapter.CmdAdapterExportState, testAdapterName),
+						Expectations: []*suite.Expectation{
+							suite.NewExpectation().
+								ExpectTopic(testAdapterEvtTopic).
+								ExpectType(adapter.EvtAdapterStateReport).
+								ExpectService(testAdapterName).
+								Expect(router.MessageVoterFn(func(m *fimpgo.Message) bool {
+									archive := &adapter.StateArchive{}
+
+									if err := m.Payload.GetObjectValue(archive); err != nil {
+										return false
+									}
+
+									return archive.Version == adapter.StateArchiveVersion &&
+										len(archive.Things) == 2 &&
+										archive.Things[0].ID == "B" &&
+										string(archive.Things[0].Info) == `{"model":"b"}` &&
+										len(archive.Domains[testArchiveDomain]) == 1 &&
+										archive.Domains[testArchiveDomain][0].Key == "device:"+testServiceTopic(testThingAddressB)
+								})),
+						},
+					},
+					{
+						Name: "import state with skipped and remapped things",
+						Command: suite.ObjectMessage(testAdapterCmdTopic, adapter.CmdAdapterImportState, testAdapterName, &adapter.StateArchive{
+							Version:      adapter.StateArchiveVersion,
+							Adapter:      "test_adapter",
+							AddressIndex: 3,
+							Things: []*adapter.ArchivedThing{
+								{ID: "B", Address: "7"},
+								{ID: "D", Address: testThingAddressC, Info: json.RawMessage(`{"model":"d"}`)},
+								{ID: "E", Address: "5"},
+							},
+							Domains: map[string][]*database.Entry{
+								testArchiveDomain: {
+									{Key: "device:" + testServiceTopic(testThingAddressC), Value: json.RawMessage(`{"level":3}`)},
+									{Key: "device:" + testServiceTopic("5") + "_1", Value: json.RawMessage(`{"level":5}`)},
+								},
+							},
+						}),
+						Expectations: []*suite.Expectation{
+							suite.ExpectObject(testAdapterEvtTopic, adapter.EvtAdapterImportReport, testAdapterName, &adapter.StateImportReport{
+								Imported: []*adapter.ImportedThing{
+									{ID: "D", Address: "4", PreviousAddress: testThingAddressC},
+									{ID: "E", Address: "5"},
+								},
+								Skipped: []*adapter.ImportedThing{
+									{ID: "B", Address: "7", Reason: "thing with the same ID already exists"},
+								},
+							}),
+							suite.NewExpectation().
+								ExpectTopic(testAdapterEvtTopic).
+								ExpectType(adapter.EvtThingInclusionReport).
+								ExpectService(testAdapterName).
+								ExactlyOnce().
+								Expect(router.MessageVoterFn(func(m *fimpgo.Message) bool {
+									report := &fimptype.ThingInclusionReport{}
+
+									return m.Payload.GetObjectValue(report) == nil && report.Address == "4"
+								})),
+						},
+					},
+					{
+						Name: "import state with invalid archive",
+						InitCallbacks: []suite.Callback{
+							func(t *testing.T) {
+								t.Helper()
+
+								assert.Len(t, ad.Things(), 4)
+
+								address, ok := ad.ExchangeID("D")
+								assert.True(t, ok)
+								assert.Equal(t, "4", address)
+
+								var level map[string]int
+
+								dd := database.NewDomainDatabase(testArchiveDomain, db)
+
+								ok, err := dd.Get("device", testServiceTopic("4"), &level)
+								assert.NoError(t, err)
+								assert.True(t, ok)
+								assert.Equal(t, map[string]int{"level": 3}, level)
+
+								ok, err = dd.Get("device", testServiceTopic("5")+"_1", &level)
+								assert.NoError(t, err)
+								assert.True(t, ok)
+
+								ok, err = dd.Get("device", testServiceTopic(testThingAddressC), &level)
+								assert.NoError(t, err)
+								assert.False(t, ok)
+							},
+						},
+						Command: suite.ObjectMessage(testAdapterCmdTopic, adapter.CmdAdapterImportState, testAdapterName, &adapter.StateArchive{
+							Version: adapter.StateArchiveVersion,
+							Adapter: "test_adapter",
+							Things: []*adapter.ArchivedThing{
+								{ID: "F", Address: "8"},
+								{ID: "G", Address: "8"},
+							},
+						}),
+						Expectations: []*suite.Expectation{
+							suite.ExpectError(testAdapterEvtTopic, testAdapterName),
+						},
+					},
+					{
+						Name: "import state with unsupported domain",
+						Command: suite.ObjectMessage(testAdapterCmdTopic, adapter.CmdAdapterImportState, testAdapterName, &adapter.StateArchive{
+							Version: adapter.StateArchiveVersion,
+							Adapter: "test_adapter",
+							Domains: map[string][]*database.Entry{
+								"unknown_domain": {{Key: "key", Value: json.RawMessage(`1`)}},
+							},
+						}),
+						Expectations: []*suite.Expectation{
+							suite.ExpectError(testAdapterEvtTopic, testAdapterName),
+						},
+					},
+					{
+						Name: "import state of a different adapter",
+						InitCallbacks: []suite.Callback{
+							func(t *testing.T) {
+								t.Helper()
+
+								assert.Len(t, ad.Things(), 4)
+							},
+						},
+						Command: suite.ObjectMessage(testAdapterCmdTopic, adapter.CmdAdapterImportState, testAdapterName, &adapter.StateArchive{
+							Version: adapter.StateArchiveVersion,
+							Adapter: "other_adapter",
+						}),
+						Expectations: []*suite.Expectation{
+							suite.ExpectError(testAdapterEvtTopic, testAdapterName),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	s.Run(t)
+}
+
+func setupStateArchive(ad *adapter.Adapter, db *database.Database) suite.BaseSetup {
+	return func(t *testing.T, mqtt *fimpgo.MqttTransport) ([]*router.Routing, []*task.Task, []suite.Mock) {
+		t.Helper()
+
+		var err error
+
+		*db, err = database.NewDatabase(testDatabaseWorkDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() {
+			_ = (*db).Stop()
+			_ = os.RemoveAll(testDatabaseWorkDir)
+		})
+
+		err = database.NewDomainDatabase(testArchiveDomain, *db).Set("device", testServiceTopic(testThingAddressB), map[string]int{"level": 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		factory := adapterhelper.FactoryHelper(func(_ adapter.Adapter, publisher adapter.Publisher, thingState adapter.ThingState) (adapter.Thing, error) {
+			return adapter.NewThing(publisher, thingState, &adapter.ThingConfig{
+				InclusionReport: &fimptype.ThingInclusionReport{Address: thingState.Address()},
+				Connector:       mockedadapter.NewDefaultConnector(t),
+			}), nil
+		})
+
+		seeds := adapter.ThingSeeds{
+			{ID: "B", CustomAddress: testThingAddressB, Info: map[string]string{"model": "b"}},
+			{ID: "C", CustomAddress: testThingAddressC},
+		}
+
+		*ad = adapterhelper.PrepareSeededAdapter(t, testAdapterWorkDir, mqtt, factory, seeds)
+
+		cfg := &adapter.ArchiveConfig{
+			Database: *db,
+			Domains:  []string{testArchiveDomain},
+		}
+
+		return adapter.RouteStateArchive(*ad, cfg), nil, nil
+	}
+}
+
+func testServiceTopic(address string) string {
+	return "pt:j1/mt:cmd/rt:dev/rn:test_adapter/ad:1/sv:meter_elec/ad:" + address
+}
+
+func TestImportState_ExistingThings(t *testing.T) {
+	t.Parallel()
+
+	db := databasehelper.NewDatabase(t)
+	domain := database.NewDomainDatabase(testArchiveDomain, db)
+
+	factory := adapterhelper.FactoryHelper(func(_ adapter.Adapter, publisher adapter.Publisher, thingState adapter.ThingState) (adapter.Thing, error) {
+		return adapter.NewThing(publisher, thingState, &adapter.ThingConfig{
+			InclusionReport: &fimptype.ThingInclusionReport{Address: thingState.Address()},
+			Connector:       mockedadapter.NewDefaultConnector(t),
+		}), nil
+	})
+
+	// The transport is not connected, as the test does not depend on published messages.
+	mqtt := fimpgo.NewMqttTransport("tcp://127.0.0.1:11883", "archive_test", "", "", true, 1, 1, nil)
+
+	ad := adapterhelper.PrepareSeededAdapter(t, t.TempDir(), mqtt, factory, adapter.ThingSeeds{
+		{ID: "B", CustomAddress: testThingAddressB},
+		{ID: "C", CustomAddress: testThingAddressC},
+	})
+
+	archiver, ok := ad.(adapter.StateArchiver)
+	assert.True(t, ok)
+
+	assert.NoError(t, domain.Set("device", testServiceTopic(testThingAddressB), 1))
+
+	cfg := &adapter.ArchiveConfig{Database: db, Domains: []string{testArchiveDomain}}
+
+	archive, err := archiver.ExportState(cfg)
+	assert.NoError(t, err)
+	assert.Len(t, archive.Domains[testArchiveDomain], 1)
+
+	assert.NoError(t, domain.Set("device", testServiceTopic(testThingAddressB), 2))
+
+	report, err := archiver.ImportState(archive, cfg)
+	assert.NoError(t, err)
+	assert.Empty(t, report.Imported)
+	assert.Len(t, report.Skipped, 2)
+
+	var value int
+
+	ok, err = domain.Get("device", testServiceTopic(testThingAddressB), &value)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 2, value, "data of an existing thing must not be overwritten")
+
+	// Thing C is archived under the current address of thing B, as if the address was reused after thing C moved.
+	archive.Things = []*adapter.ArchivedThing{{ID: "C", Address: testThingAddressB}}
+
+	_, err = archiver.ImportState(archive, cfg)
+	assert.NoError(t, err)
+
+	ok, err = domain.Get("device", testServiceTopic(testThingAddressB), &value)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 2, value, "data of a skipped thing must not overwrite data of the thing now using its address")
+}
@@ -0,0 +1,238 @@
+package adapter
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/futurehomeno/fimpgo"
+	"github.com/futurehomeno/fimpgo/fimptype"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/futurehomeno/cliffhanger/database"
+	"github.com/futurehomeno/cliffhanger/router"
+)
+
+const (
+	// AuditOutcomeSuccess is an outcome of a command which was processed successfully.
+	AuditOutcomeSuccess = "success"
+	// AuditOutcomeError is an outcome of a command which failed to be processed.
+	AuditOutcomeError = "error"
+
+	// defaultAuditRetention is a default period for which audit entries are kept.
+	defaultAuditRetention = 7 * 24 * time.Hour
+	// defaultAuditQueryLimit is a default maximum number of audit entries returned by a single query.
+	defaultAuditQueryLimit = 100
+	// auditBucket is a database bucket in which audit entries are persisted.
+	auditBucket = "audit"
+)
+
+// AuditConfig represents a configuration of the command audit log.
+type AuditConfig struct {
+	// Database is a database in which audit entries are persisted.
+	Database database.Database
+	// Retention is a period after which audit entries expire.
+	Retention time.Duration
+}
+
+// withDefaults sets default values for all options which were not provided.
+func (c *AuditConfig) withDefaults() *AuditConfig {
+	if c.Retention <= 0 {
+		c.Retention = defaultAuditRetention
+	}
+
+	return c
+}
+
+// AuditEntry represents a single command handled by a service routing.
+type AuditEntry struct {
+	Timestamp     time.Time `json:"timestamp"`
+	Topic         string    `json:"topic"`
+	Address       string    `json:"address"`
+	Service       string    `json:"service"`
+	Type          string    `json:"type"`
+	ValueType     string    `json:"value_type"`
+	Value         any       `json:"value"`
+	Source        string    `json:"source,omitempty"`
+	UID           string    `json:"uid,omitempty"`
+	CorrelationID string    `json:"correlation_id,omitempty"`
+	Outcome       string    `json:"outcome"`
+	Response      string    `json:"response,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	Duration      int64     `json:"duration"`
+}
+
+// AuditFilter is the object sent as value of the command querying the audit log. All provided criteria must be met.
+type AuditFilter struct {
+	From    *time.Time `json:"from,omitempty"`
+	To      *time.Time `json:"to,omitempty"`
+	Address string     `json:"address,omitempty"`
+	Service string     `json:"service,omitempty"`
+	Type    string     `json:"type,omitempty"`
+	Source  string     `json:"source,omitempty"`
+	Outcome string     `json:"outcome,omitempty"`
+	// Limit is a maximum number of the most recent matching entries to be returned.
+	Limit int `json:"limit,omitempty"`
+}
+
+// matches returns true if the entry meets all criteria of the filter.
+func (f *AuditFilter) matches(e *AuditEntry) bool {
+	return (f.Address == "" || f.Address == e.Address) &&
+		(f.Service == "" || f.Service == e.Service) &&
+		(f.Type == "" || f.Type == e.Type) &&
+		(f.Source == "" || f.Source == e.Source) &&
+		(f.Outcome == "" || f.Outcome == e.Outcome)
+}
+
+// AuditLog is a service recording commands handled by service routings together with their outcome.
+// It is opt-in and must be registered as a stats callback of the router using router.WithStatsCallback(auditLog.Record).
+// The audit log is chained with any other stats callbacks registered with the router, so it does not displace them.
+type AuditLog interface {
+	// Record records a command processed by the router. Messages other than commands sent to devices are ignored.
+	Record(stats router.Stats)
+	// Query returns audit entries matching the provided filter in chronological order.
+	Query(filter *AuditFilter) ([]*AuditEntry, error)
+}
+
+// NewAuditLog creates new instance of the command audit log.
+func NewAuditLog(cfg *AuditConfig) (AuditLog, error) {
+	if cfg == nil || cfg.Database == nil {
+		return nil, errors.New("audit log: database is required")
+	}
+
+	return &auditLog{
+		cfg: cfg.withDefaults(),
+	}, nil
+}
+
+type auditLog struct {
+	cfg *AuditConfig
+
+	lock     sync.Mutex
+	lastKey  int64
+	sequence int
+}
+
+// Record records a command processed by the router.
+func (l *auditLog) Record(stats router.Stats) {
+	msg := stats.InputMessage
+	if msg == nil || msg.Payload == nil {
+		return
+	}
+
+	addr := msg.Addr
+	if addr == nil {
+		var err error
+
+		addr, err = fimpgo.NewAddressFromString(msg.Topic)
+		if err != nil {
+			log.WithError(err).WithField("topic", msg.Topic).Errorf("audit log: failed to parse topic of the command")
+
+			return
+		}
+	}
+
+	if addr.MsgType != fimptype.MsgTypeCmd || addr.ResourceType != fimptype.ResourceTypeDevice {
+		return
+	}
+
+	entry := &AuditEntry{
+		Timestamp:     time.Now(),
+		Topic:         msg.Topic,
+		Address:       addr.ServiceAddress,
+		Service:       msg.Payload.Service.Str(),
+		Type:          msg.Payload.Interface,
+		ValueType:     msg.Payload.ValueType.Str(),
+		Value:         msg.Payload.Value,
+		Source:        string(msg.Payload.Source),
+		UID:           msg.Payload.UID,
+		CorrelationID: msg.Payload.CorrelationID,
+		Outcome:       AuditOutcomeSuccess,
+		Duration:      stats.ProcessingDuration.Milliseconds(),
+	}
+
+	if response := stats.OutputMessage; response != nil && response.Payload != nil {
+		entry.Response = response.Payload.Interface
+
+		if response.Payload.Interface == router.EvtErrorReport {
+			entry.Outcome = AuditOutcomeError
+			entry.Error = response.Payload.Properties[router.PropertyMsg]
+		}
+	}
+
+	if err := l.cfg.Database.SetWithExpiry(auditBucket, l.key(entry.Timestamp), entry, l.cfg.Retention); err != nil {
+		log.WithError(err).WithField("topic", entry.Topic).Errorf("audit log: failed to record command")
+	}
+}
+
+// Query returns audit entries matching the provided filter in chronological order.
+func (l *auditLog) Query(filter *AuditFilter) ([]*AuditEntry, error) {
+	if filter == nil {
+		filter = &AuditFilter{}
+	}
+
+	from, to := "", "~"
+
+	if filter.From != nil {
+		from = auditKeyPrefix(*filter.From)
+	}
+
+	if filter.To != nil {
+		to = auditKeyPrefix(*filter.To) + "~"
+	}
+
+	keys, err := l.cfg.Database.KeysBetween(auditBucket, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("audit log: failed to get entries: %w", err)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultAuditQueryLimit
+	}
+
+	entries := make([]*AuditEntry, 0)
+
+	for _, key := range slices.Backward(keys) {
+		if len(entries) >= limit {
+			break
+		}
+
+		entry := &AuditEntry{}
+
+		ok, err := l.cfg.Database.Get(auditBucket, key, entry)
+		if err != nil {
+			return nil, fmt.Errorf("audit log: failed to get entry %s: %w", key, err)
+		}
+
+		if ok && filter.matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+
+	slices.Reverse(entries)
+
+	return entries, nil
+}
+
+// key returns a unique and chronologically sortable key of an entry recorded at the provided time.
+func (l *auditLog) key(t time.Time) string {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if t.UnixNano() == l.lastKey {
+		l.sequence++
+	} else {
+		l.lastKey = t.UnixNano()
+		l.sequence = 0
+	}
+
+	return fmt.Sprintf("%s-%06d", auditKeyPrefix(t), l.sequence)
+}
+
+// auditKeyPrefix returns a chronologically sortable prefix of keys of entries recorded at the provided time.
+func auditKeyPrefix(t time.Time) string {
+	return fmt.Sprintf("%020d", t.UnixNano())
+}
@@ -0,0 +1,178 @@
+package adapter_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/futurehomeno/fimpgo"
+	"github.com/futurehomeno/fimpgo/fimptype"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/adapter/service/outbinswitch"
+	"github.com/futurehomeno/cliffhanger/router"
+	"github.com/futurehomeno/cliffhanger/task"
+	adapterhelper "github.com/futurehomeno/cliffhanger/test/helper/adapter"
+	databasehelper "github.com/futurehomeno/cliffhanger/test/helper/database"
+	mockedadapter "github.com/futurehomeno/cliffhanger/test/mocks/adapter"
+	mockedoutbinswitch "github.com/futurehomeno/cliffhanger/test/mocks/adapter/service/outbinswitch"
+	"github.com/futurehomeno/cliffhanger/test/suite"
+)
+
+func TestRouteAuditLog(t *testing.T) { //nolint:paralleltest
+	auditLog, err := adapter.NewAuditLog(&adapter.AuditConfig{Database: databasehelper.NewDatabase(t)})
+	assert.NoError(t, err)
+
+	s := &suite.Suite{
+		Cases: []*suite.Case{
+			(&suite.Case{
+				Name:     "commands handled by service routings are audited",
+				TearDown: adapterhelper.TearDownAdapter(testAdapterWorkDir),
+				Setup:    setupAuditLog(auditLog),
+				Nodes: []*suite.Node{
+					{
+						Name:    "successful command",
+						Command: withSource(suite.BoolMessage(testServiceCmdTopic, outbinswitch.CmdBinarySet, outbinswitch.OutBinSwitch, true), "app"),
+						Expectations: []*suite.Expectation{
+							suite.ExpectBool(testServiceEvtTopic, outbinswitch.EvtBinaryReport, outbinswitch.OutBinSwitch, true),
+						},
+					},
+					{
+						Name:    "failed command",
+						Command: withSource(suite.BoolMessage(testServiceCmdTopic, outbinswitch.CmdBinarySet, outbinswitch.OutBinSwitch, false), "vinculum"),
+						Expectations: []*suite.Expectation{
+							suite.ExpectError(testServiceEvtTopic, outbinswitch.OutBinSwitch),
+						},
+					},
+					{
+						Name:    "query whole audit log",
+						Command: suite.NullMessage(testAdapterCmdTopic, adapter.CmdAuditGetLog, testAdapterName),
+						Expectations: []*suite.Expectation{
+							expectAuditLogReport(func(entries []*adapter.AuditEntry) bool {
+								return len(entries) == 2 &&
+									entries[0].Type == outbinswitch.CmdBinarySet &&
+									entries[0].Value == true &&
+									entries[0].Source == "app" &&
+									entries[0].Address == testThingAddressB &&
+									entries[0].Outcome == adapter.AuditOutcomeSuccess &&
+									entries[1].Source == "vinculum" &&
+									entries[1].Outcome == adapter.AuditOutcomeError &&
+									entries[1].Error != ""
+							}),
+						},
+					},
+					{
+						Name: "query audit log with filter",
+						Command: suite.ObjectMessage(testAdapterCmdTopic, adapter.CmdAuditGetLog, testAdapterName, &adapter.AuditFilter{
+							Outcome: adapter.AuditOutcomeError,
+						}),
+						Expectations: []*suite.Expectation{
+							expectAuditLogReport(func(entries []*adapter.AuditEntry) bool {
+								return len(entries) == 1 && entries[0].Source == "vinculum"
+							}),
+						},
+					},
+				},
+			}).WithRouterOptions(router.WithStatsCallback(auditLog.Record)),
+		},
+	}
+
+	s.Run(t)
+}
+
+func TestNewAuditLog(t *testing.T) {
+	t.Parallel()
+
+	_, err := adapter.NewAuditLog(nil)
+	assert.Error(t, err)
+
+	_, err = adapter.NewAuditLog(&adapter.AuditConfig{})
+	assert.Error(t, err)
+}
+
+func TestAuditLog_Query(t *testing.T) {
+	t.Parallel()
+
+	auditLog, err := adapter.NewAuditLog(&adapter.AuditConfig{Database: databasehelper.NewDatabase(t)})
+	assert.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		auditLog.Record(router.Stats{
+			InputMessage: suite.BoolMessage(testServiceCmdTopic, outbinswitch.CmdBinarySet, outbinswitch.OutBinSwitch, i%2 == 0),
+		})
+	}
+
+	start := time.Now()
+
+	auditLog.Record(router.Stats{
+		InputMessage: suite.NullMessage(testServiceCmdTopic, outbinswitch.CmdBinaryGetReport, outbinswitch.OutBinSwitch),
+	})
+	auditLog.Record(router.Stats{
+		InputMessage: suite.NullMessage(testAdapterCmdTopic, adapter.CmdThingGetInclusionReport, testAdapterName),
+	})
+
+	entries, err := auditLog.Query(nil)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 6, "commands sent to the adapter must not be audited")
+
+	entries, err = auditLog.Query(&adapter.AuditFilter{Type: outbinswitch.CmdBinarySet, Limit: 2})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, true, entries[1].Value, "the most recent entries must be returned")
+
+	entries, err = auditLog.Query(&adapter.AuditFilter{From: &start})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, outbinswitch.CmdBinaryGetReport, entries[0].Type)
+}
+
+func setupAuditLog(auditLog adapter.AuditLog) suite.BaseSetup {
+	return func(t *testing.T, mqtt *fimpgo.MqttTransport) ([]*router.Routing, []*task.Task, []suite.Mock) {
+		t.Helper()
+
+		controller := mockedoutbinswitch.NewController(t).
+			MockedBinarySwitchBinarySet(true, nil, true).
+			MockedBinarySwitchBinaryReport(true, nil, true).
+			MockedBinarySwitchBinarySet(false, errors.New("test"), true)
+
+		factory := adapterhelper.FactoryHelper(func(a adapter.Adapter, publisher adapter.Publisher, thingState adapter.ThingState) (adapter.Thing, error) {
+			return adapter.NewThing(publisher, thingState, &adapter.ThingConfig{
+				InclusionReport: &fimptype.ThingInclusionReport{Address: thingState.Address()},
+				Connector:       mockedadapter.NewDefaultConnector(t),
+			}, outbinswitch.NewService(publisher, &outbinswitch.Config{
+				Specification: outbinswitch.Specification("test_adapter", a.Address(), thingState.Address(), nil),
+				Controller:    controller,
+			})), nil
+		})
+
+		ad := adapterhelper.PrepareSeededAdapter(t, testAdapterWorkDir, mqtt, factory, adapter.ThingSeeds{
+			{ID: "B", CustomAddress: testThingAddressB},
+		})
+
+		return router.Combine(adapter.RouteAuditLog(ad, auditLog), outbinswitch.RouteService(ad)), nil, []suite.Mock{controller}
+	}
+}
+
+func withSource(msg *fimpgo.Message, source fimptype.ResourceNameT) *fimpgo.Message {
+	msg.Payload.Source = source
+
+	return msg
+}
+
+func expectAuditLogReport(check func(entries []*adapter.AuditEntry) bool) *suite.Expectation {
+	return suite.NewExpectation().
+		ExpectTopic(testAdapterEvtTopic).
+		ExpectType(adapter.EvtAuditLogReport).
+		ExpectService(testAdapterName).
+		Expect(router.MessageVoterFn(func(m *fimpgo.Message) bool {
+			var entries []*adapter.AuditEntry
+
+			if err := m.Payload.GetObjectValue(&entries); err != nil {
+				return false
+			}
+
+			return check(entries)
+		})).
+		ExactlyOnce()
+}
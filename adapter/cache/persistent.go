@@ -0,0 +1,141 @@
+package cache
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/futurehomeno/cliffhanger/database"
+)
+
+const (
+	// reportingCacheBucket is a database bucket in which reported values are persisted.
+	reportingCacheBucket = "reporting_cache"
+)
+
+// NewPersistentReportingCache creates new instance of a reporting cache which persists reported values and times of reports in the provided database.
+// Thanks to that reporting strategies are respected across restarts of the application. The namespace must be unique for every cache
+// sharing the same database, therefore it is recommended to use a topic of the service or the thing owning the cache.
+func NewPersistentReportingCache(db database.Database, namespace string) ReportingCache {
+	return &persistentReportingCache{
+		reportingCache: newReportingCache(),
+		db:             db,
+		namespace:      namespace,
+	}
+}
+
+// persistentReportingCache is a private implementation of reporting cache service persisting reported values in a database.
+type persistentReportingCache struct {
+	*reportingCache
+
+	db        database.Database
+	namespace string
+}
+
+// persistedValue is an object holding reported value and time of last report persisted in the database.
+type persistedValue struct {
+	Value    json.RawMessage `json:"value"`
+	Reported time.Time       `json:"reported"`
+}
+
+// ReportRequired returns true if report for a provided key, sub key and value should be sent according to provided strategy.
+func (c *persistentReportingCache) ReportRequired(strategy ReportingStrategy, key, subKey string, val any) bool {
+	c.load(key, subKey, val)
+
+	return c.reportingCache.ReportRequired(strategy, key, subKey, val)
+}
+
+// HasChanged returns true if value for a provided key and sub key changed.
+func (c *persistentReportingCache) HasChanged(key, subKey string, val any) bool {
+	c.load(key, subKey, val)
+
+	return c.reportingCache.HasChanged(key, subKey, val)
+}
+
+// Reported marks value for a provided key and sub key as reported and persists it in the database.
+func (c *persistentReportingCache) Reported(key, subKey string, val any) {
+	c.reportingCache.Reported(key, subKey, val)
+
+	raw, err := json.Marshal(val)
+	if err != nil {
+		log.WithError(err).Errorf("reporting cache: failed to marshal value of %s for persistence", c.dbKey(key, subKey))
+
+		return
+	}
+
+	c.lock.RLock()
+	reported := c.values[key][subKey].reported
+	c.lock.RUnlock()
+
+	err = c.db.Set(reportingCacheBucket, c.dbKey(key, subKey), &persistedValue{Value: raw, Reported: reported})
+	if err != nil {
+		log.WithError(err).Errorf("reporting cache: failed to persist value of %s", c.dbKey(key, subKey))
+	}
+}
+
+// load loads the persisted value for a provided key and sub key into memory if it is not present there yet.
+// The persisted value is decoded into the type of the provided value, so it can be compared with it.
+func (c *persistentReportingCache) load(key, subKey string, val any) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.values[key][subKey] != nil {
+		return
+	}
+
+	persisted := &persistedValue{}
+
+	ok, err := c.db.Get(reportingCacheBucket, c.dbKey(key, subKey), persisted)
+	if err != nil {
+		log.WithError(err).Errorf("reporting cache: failed to load persisted value of %s", c.dbKey(key, subKey))
+
+		return
+	}
+
+	if !ok {
+		return
+	}
+
+	decoded, err := decodeAs(persisted.Value, val)
+	if err != nil {
+		log.WithError(err).Warnf("reporting cache: failed to decode persisted value of %s, ignoring it", c.dbKey(key, subKey))
+
+		return
+	}
+
+	if _, ok := c.values[key]; !ok {
+		c.values[key] = make(map[string]*value)
+	}
+
+	c.values[key][subKey] = &value{
+		reported: persisted.Reported,
+		value:    decoded,
+	}
+}
+
+// dbKey returns a database key for a provided key and sub key.
+func (c *persistentReportingCache) dbKey(key, subKey string) string {
+	return strings.Join([]string{c.namespace, key, subKey}, "/")
+}
+
+// decodeAs decodes a raw JSON value into the type of the provided example value.
+func decodeAs(raw json.RawMessage, example any) (any, error) {
+	if example == nil {
+		var v any
+
+		err := json.Unmarshal(raw, &v)
+
+		return v, err
+	}
+
+	ptr := reflect.New(reflect.TypeOf(example))
+
+	if err := json.Unmarshal(raw, ptr.Interface()); err != nil {
+		return nil, err
+	}
+
+	return ptr.Elem().Interface(), nil
+}
@@ -0,0 +1,44 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/futurehomeno/cliffhanger/adapter/cache"
+	databasehelper "github.com/futurehomeno/cliffhanger/test/helper/database"
+)
+
+type testReport struct {
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
+}
+
+func TestPersistentReportingCache(t *testing.T) {
+	t.Parallel()
+
+	db := databasehelper.NewDatabase(t)
+
+	c := cache.NewPersistentReportingCache(db, "meter_elec")
+	assert.True(t, c.ReportRequired(cache.ReportAtLeastEvery(time.Hour), "evt.meter.report", "kWh", 12.5))
+
+	c.Reported("evt.meter.report", "kWh", 12.5)
+	c.Reported("evt.state.report", "", "on")
+	c.Reported("evt.extended.report", "", &testReport{Value: 230, Unit: "V"})
+	c.Reported("evt.count.report", "", 3)
+
+	restarted := cache.NewPersistentReportingCache(db, "meter_elec")
+
+	assert.False(t, restarted.ReportRequired(cache.ReportAtLeastEvery(time.Hour), "evt.meter.report", "kWh", 12.5))
+	assert.True(t, restarted.ReportRequired(cache.ReportAtLeastEvery(time.Nanosecond), "evt.meter.report", "kWh", 12.5))
+	assert.True(t, restarted.ReportRequired(cache.ReportAtLeastEvery(time.Hour), "evt.meter.report", "kWh", 12.6))
+	assert.False(t, restarted.ReportRequired(cache.ReportOnDeadband(0.5, 0), "evt.meter.report", "kWh", 12.6))
+	assert.False(t, restarted.HasChanged("evt.state.report", "", "on"))
+	assert.False(t, restarted.HasChanged("evt.extended.report", "", &testReport{Value: 230, Unit: "V"}))
+	assert.True(t, restarted.HasChanged("evt.extended.report", "", &testReport{Value: 231, Unit: "V"}))
+	assert.False(t, restarted.HasChanged("evt.count.report", "", 3))
+
+	other := cache.NewPersistentReportingCache(db, "meter_water")
+	assert.True(t, other.ReportRequired(cache.ReportOnChangeOnly(), "evt.meter.report", "kWh", 12.5))
+}
@@ -28,6 +28,18 @@ type Refresher[T any] interface {
 	IsFailing() bool
 }
 
+// AgeAwareRefresher is an optional interface of a refresher reporting the age of the returned data and whether it is stale.
+// Refreshers created by NewRefresher implement it.
+type AgeAwareRefresher[T any] interface {
+	Refresher[T]
+
+	// RefreshWithAge refreshes data if required and returns it together with its age, i.e. time passed since it was successfully refreshed,
+	// and true if the value is stale, i.e. it was served on failure.
+	RefreshWithAge() (T, time.Duration, bool, error)
+	// IsStale returns true if the last invocation of the refresh function failed, so a value served on failure is stale.
+	IsStale() bool
+}
+
 // RefresherOption is an option for refresher service.
 type RefresherOption interface {
 	// apply applies refresher option to the provided refresher.
@@ -81,6 +93,34 @@ func WithIntervalOffset(offset float64) RefresherOption {
 	})
 }
 
+// WithCoalescing makes concurrent calls of the refresher share a single invocation of the refresh function and its result,
+// instead of waiting for each other and invoking the refresh function one after another.
+func WithCoalescing() RefresherOption {
+	return refresherOptionFn(func(r *refresherOptions) {
+		r.coalescing = true
+	})
+}
+
+// WithStaleOnFailure makes the refresher serve the last successfully refreshed value instead of an error
+// if the refresh function fails or backoff is in effect. The value is served only if it is not older than the provided maximum age.
+// Zero maximum age allows to serve the value regardless of its age. The age of the served value and whether it was served because of
+// a failure can be obtained using RefreshWithAge of AgeAwareRefresher.
+func WithStaleOnFailure(maxAge time.Duration) RefresherOption {
+	return refresherOptionFn(func(r *refresherOptions) {
+		r.staleOnFailure = true
+		r.maxStaleAge = maxAge
+	})
+}
+
+// WithRefreshAhead makes the refresher start a background refresh if the cached value is requested when its age exceeds
+// the provided fraction of the interval, e.g. 0.8 starts a refresh after 80% of the interval has passed.
+// The cached value is returned immediately, while the refreshed value is served by the subsequent calls.
+func WithRefreshAhead(fraction float64) RefresherOption {
+	return refresherOptionFn(func(r *refresherOptions) {
+		r.refreshAhead = fraction
+	})
+}
+
 // WithDefaultOptions sets default options for the refresher.
 func WithDefaultOptions() RefresherOption {
 	return refresherOptionFn(func(r *refresherOptions) {
@@ -115,6 +155,7 @@ type refresher[T any] struct {
 	lastRefresh  time.Time
 	lastFailure  time.Time
 	failureCount int
+	inflight     *refreshCall[T]
 
 	refresh Refresh[T]
 }
@@ -126,39 +167,138 @@ type refresherOptions struct {
 	initialBackoff   time.Duration
 	repeatedBackoff  time.Duration
 	finalBackoff     time.Duration
+	coalescing       bool
+	staleOnFailure   bool
+	maxStaleAge      time.Duration
+	refreshAhead     float64
+}
+
+// refreshCall represents an invocation of the refresh function running in the background, which can be awaited by multiple callers.
+type refreshCall[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
 }
 
 // Refresh refreshes data if required and returns it.
 func (r *refresher[T]) Refresh() (T, error) {
+	value, _, _, err := r.RefreshWithAge()
+
+	return value, err
+}
+
+// RefreshWithAge refreshes data if required and returns it together with its age, i.e. time passed since it was successfully refreshed,
+// and true if the value is stale, i.e. it was served on failure.
+func (r *refresher[T]) RefreshWithAge() (T, time.Duration, bool, error) {
+	r.lock.Lock()
+
+	if age := time.Since(r.lastRefresh); !r.lastRefresh.IsZero() && age < r.interval {
+		defer r.lock.Unlock()
+
+		if r.shouldRefreshAhead(age) {
+			r.start()
+		}
+
+		return r.value, age, false, nil
+	}
+
+	if r.inflight == nil && r.shouldBackoff() {
+		defer r.lock.Unlock()
+
+		return r.fallback(fmt.Errorf("refresher: backoff is in effect"))
+	}
+
+	if r.inflight == nil && !r.coalescing {
+		defer r.lock.Unlock()
+
+		val, err := r.refresh()
+		r.complete(val, err)
+
+		if err != nil {
+			return r.fallback(fmt.Errorf("refresher: failed to refresh data: %w", err))
+		}
+
+		return val, 0, false, nil
+	}
+
+	call := r.inflight
+	if call == nil {
+		call = r.start()
+	}
+
+	r.lock.Unlock()
+
+	<-call.done
+
 	r.lock.Lock()
 	defer r.lock.Unlock()
 
-	if !r.lastRefresh.IsZero() && time.Since(r.lastRefresh) < r.interval {
-		return r.value, nil
+	if call.err != nil {
+		return r.fallback(fmt.Errorf("refresher: failed to refresh data: %w", call.err))
 	}
 
-	if r.shouldBackoff() {
-		var def T
+	return call.value, time.Since(r.lastRefresh), false, nil
+}
 
-		return def, fmt.Errorf("refresher: backoff is in effect")
+// start starts the refresh function in the background. It must be used only while holding the lock.
+func (r *refresher[T]) start() *refreshCall[T] {
+	call := &refreshCall[T]{
+		done: make(chan struct{}),
 	}
 
-	val, err := r.refresh()
+	r.inflight = call
+
+	go func() {
+		call.value, call.err = r.refresh()
+
+		r.lock.Lock()
+		r.complete(call.value, call.err)
+		r.inflight = nil
+		r.lock.Unlock()
+
+		close(call.done)
+	}()
+
+	return call
+}
+
+// complete records the result of the refresh function. It must be used only while holding the lock.
+func (r *refresher[T]) complete(value T, err error) {
 	if err != nil {
 		r.lastFailure = time.Now()
 		r.failureCount++
 
-		var def T
-
-		return def, fmt.Errorf("refresher: failed to refresh data: %w", err)
+		return
 	}
 
-	r.value = val
+	r.value = value
 	r.lastRefresh = time.Now()
 	r.failureCount = 0
 	r.lastFailure = time.Time{}
+}
 
-	return r.value, nil
+// fallback returns the last successfully refreshed value if serving stale values on failure is enabled and the value is not too old,
+// otherwise it returns the provided error. It must be used only while holding the lock.
+func (r *refresher[T]) fallback(err error) (T, time.Duration, bool, error) {
+	age := time.Since(r.lastRefresh)
+
+	if r.staleOnFailure && !r.lastRefresh.IsZero() && (r.maxStaleAge == 0 || age <= r.maxStaleAge) {
+		return r.value, age, true, nil
+	}
+
+	var def T
+
+	return def, 0, false, err
+}
+
+// shouldRefreshAhead returns true if a background refresh should be started for a cached value of the provided age.
+// It must be used only while holding the lock.
+func (r *refresher[T]) shouldRefreshAhead(age time.Duration) bool {
+	if r.refreshAhead <= 0 || r.inflight != nil || r.shouldBackoff() {
+		return false
+	}
+
+	return age >= time.Duration(r.refreshAhead*float64(r.interval))
 }
 
 // Reset cache so next invocation will result in execution of provided refresh function.
@@ -179,6 +319,14 @@ func (r *refresher[T]) IsFailing() bool {
 	return r.failureCount > r.failureThreshold
 }
 
+// IsStale returns true if the last invocation of the refresh function failed, so a value served on failure is stale.
+func (r *refresher[T]) IsStale() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.failureCount > 0
+}
+
 // shouldBackoff returns true if the backoff is in effect and false otherwise.
 func (r *refresher[T]) shouldBackoff() bool {
 	if r.backoffThreshold == 0 {
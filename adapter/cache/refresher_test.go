@@ -2,11 +2,13 @@ package cache_test
 
 import (
 	"errors"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/futurehomeno/cliffhanger/adapter/cache"
 )
@@ -135,6 +137,131 @@ func TestRefresher_Refresh(t *testing.T) {
 	}
 }
 
+func TestRefresher_WithCoalescing(t *testing.T) {
+	t.Parallel()
+
+	refreshMock := newRefreshMock()
+	refreshMock.On("refresh").Return("test", nil).After(50 * time.Millisecond).Once()
+
+	refresher := cache.NewRefresher(refreshMock.refresh, time.Millisecond, cache.WithCoalescing())
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			got, err := refresher.Refresh()
+			assert.NoError(t, err)
+			assert.Equal(t, "test", got)
+		}()
+	}
+
+	wg.Wait()
+
+	refreshMock.AssertExpectations(t)
+}
+
+func TestRefresher_WithStaleOnFailure(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name    string
+		maxAge  time.Duration
+		want    any
+		wantErr bool
+	}{
+		{
+			name: "stale value is served regardless of age",
+			want: "test",
+		},
+		{
+			name:   "stale value is served within maximum age",
+			maxAge: time.Second,
+			want:   "test",
+		},
+		{
+			name:    "stale value exceeding maximum age is not served",
+			maxAge:  25 * time.Millisecond,
+			want:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			refreshMock := newRefreshMock().
+				mockRefresh("test", nil, true).
+				mockRefresh(nil, errors.New("test"), true)
+
+			refresher := newAgeAwareRefresher(t, refreshMock.refresh, 20*time.Millisecond, cache.WithStaleOnFailure(tc.maxAge))
+
+			_, err := refresher.Refresh()
+			assert.NoError(t, err)
+
+			time.Sleep(30 * time.Millisecond)
+
+			got, age, stale, err := refresher.RefreshWithAge()
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.GreaterOrEqual(t, age, 30*time.Millisecond)
+			}
+
+			assert.Equal(t, !tc.wantErr, stale)
+			assert.True(t, refresher.IsStale())
+
+			assert.Equal(t, tc.want, got)
+
+			refreshMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRefresher_WithRefreshAhead(t *testing.T) {
+	t.Parallel()
+
+	refreshMock := newRefreshMock().
+		mockRefresh("first", nil, true).
+		mockRefresh("second", nil, true)
+
+	refresher := newAgeAwareRefresher(t, refreshMock.refresh, 100*time.Millisecond, cache.WithRefreshAhead(0.5))
+
+	got, err := refresher.Refresh()
+	assert.NoError(t, err)
+	assert.Equal(t, "first", got)
+
+	time.Sleep(60 * time.Millisecond)
+
+	got, age, _, err := refresher.RefreshWithAge()
+	assert.NoError(t, err)
+	assert.Equal(t, "first", got, "cached value must be served while refreshing in background")
+	assert.GreaterOrEqual(t, age, 60*time.Millisecond)
+
+	time.Sleep(10 * time.Millisecond)
+
+	got, age, _, err = refresher.RefreshWithAge()
+	assert.NoError(t, err)
+	assert.Equal(t, "second", got)
+	assert.Less(t, age, 60*time.Millisecond)
+
+	refreshMock.AssertExpectations(t)
+}
+
+func newAgeAwareRefresher(t *testing.T, refresh cache.Refresh[any], interval time.Duration, options ...cache.RefresherOption) cache.AgeAwareRefresher[any] {
+	t.Helper()
+
+	refresher, ok := cache.NewRefresher(refresh, interval, options...).(cache.AgeAwareRefresher[any])
+	require.True(t, ok)
+
+	return refresher
+}
+
 func newRefreshMock() *refreshMock {
 	return &refreshMock{}
 }
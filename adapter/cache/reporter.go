@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"math"
 	"reflect"
 	"sync"
 	"time"
@@ -12,6 +13,14 @@ type ReportingStrategy interface {
 	ReportRequired(hasChanged bool, lastReported time.Time) bool
 }
 
+// ValueReportingStrategy is an optional interface of a reporting strategy which also takes the previously reported and the new value into account.
+type ValueReportingStrategy interface {
+	ReportingStrategy
+
+	// ReportValueRequired determines if report is required based on the previously reported value, the new value and the time of the last report.
+	ReportValueRequired(previous, current any, lastReported time.Time) bool
+}
+
 // ReportingStrategyFn is a function adapter that allows to use anonymous functions as reporting strategy.
 type ReportingStrategyFn func(hasChanged bool, lastReported time.Time) bool
 
@@ -45,6 +54,218 @@ func ReportAtLeastEvery(interval time.Duration) ReportingStrategy {
 	})
 }
 
+// ReportOnDeadband is a reporting strategy in which report of a numeric value is sent only if it differs from the last reported one by at least delta.
+// If interval is provided, report is also sent if a specific time has passed. Non-numeric values are reported on change.
+func ReportOnDeadband(delta float64, interval time.Duration) ReportingStrategy {
+	return &deadbandStrategy{
+		delta:    delta,
+		interval: interval,
+	}
+}
+
+// deadbandStrategy is a private implementation of the deadband reporting strategy.
+type deadbandStrategy struct {
+	delta    float64
+	interval time.Duration
+}
+
+// ReportRequired determines if report is required based on input information.
+func (s *deadbandStrategy) ReportRequired(hasChanged bool, lastReported time.Time) bool {
+	if hasChanged {
+		return true
+	}
+
+	return s.intervalPassed(lastReported)
+}
+
+// ReportValueRequired determines if report is required based on the previously reported value, the new value and the time of the last report.
+func (s *deadbandStrategy) ReportValueRequired(previous, current any, lastReported time.Time) bool {
+	if s.intervalPassed(lastReported) {
+		return true
+	}
+
+	p, ok1 := toFloat(previous)
+	c, ok2 := toFloat(current)
+
+	if !ok1 || !ok2 {
+		return !reflect.DeepEqual(previous, current)
+	}
+
+	return math.Abs(c-p) >= s.delta
+}
+
+// intervalPassed returns true if interval is configured and has passed since the last report.
+func (s *deadbandStrategy) intervalPassed(lastReported time.Time) bool {
+	return s.interval > 0 && time.Since(lastReported) > s.interval
+}
+
+// ReportOnPercentDeadband is a reporting strategy in which report of a numeric value is sent only if it differs from the last reported one
+// by at least the provided percentage of the last reported value. If interval is provided, report is also sent if a specific time has passed.
+// Non-numeric values are reported on change.
+func ReportOnPercentDeadband(percent float64, interval time.Duration) ReportingStrategy {
+	return &percentDeadbandStrategy{
+		deadbandStrategy: deadbandStrategy{
+			delta:    percent,
+			interval: interval,
+		},
+	}
+}
+
+// percentDeadbandStrategy is a private implementation of the percentage deadband reporting strategy.
+type percentDeadbandStrategy struct {
+	deadbandStrategy
+}
+
+// ReportValueRequired determines if report is required based on the previously reported value, the new value and the time of the last report.
+func (s *percentDeadbandStrategy) ReportValueRequired(previous, current any, lastReported time.Time) bool {
+	if s.intervalPassed(lastReported) {
+		return true
+	}
+
+	p, ok1 := toFloat(previous)
+	c, ok2 := toFloat(current)
+
+	if !ok1 || !ok2 {
+		return !reflect.DeepEqual(previous, current)
+	}
+
+	if p == 0 {
+		return c != 0
+	}
+
+	return math.Abs(c-p)/math.Abs(p)*100 >= s.delta
+}
+
+// ReportOnHysteresis is a reporting strategy in which report of a numeric value is sent only if it crosses the hysteresis band.
+// A value reaching the upper threshold is reported once and is not reported again until it falls to the lower threshold, and vice versa.
+// Values within the band are not reported. Non-numeric values are reported on change.
+func ReportOnHysteresis(lower, upper float64) ReportingStrategy {
+	return &hysteresisStrategy{
+		lower: lower,
+		upper: upper,
+	}
+}
+
+// hysteresisStrategy is a private implementation of the hysteresis reporting strategy.
+type hysteresisStrategy struct {
+	lower float64
+	upper float64
+}
+
+// ReportRequired determines if report is required based on input information.
+func (s *hysteresisStrategy) ReportRequired(hasChanged bool, _ time.Time) bool {
+	return hasChanged
+}
+
+// ReportValueRequired determines if report is required based on the previously reported value, the new value and the time of the last report.
+func (s *hysteresisStrategy) ReportValueRequired(previous, current any, _ time.Time) bool {
+	p, ok1 := toFloat(previous)
+	c, ok2 := toFloat(current)
+
+	if !ok1 || !ok2 {
+		return !reflect.DeepEqual(previous, current)
+	}
+
+	band := s.band(c)
+	if band == 0 {
+		return false
+	}
+
+	return band != s.band(p)
+}
+
+// band returns -1 if the value is at or below the lower threshold, 1 if it is at or above the upper threshold and 0 otherwise.
+func (s *hysteresisStrategy) band(v float64) int {
+	switch {
+	case v <= s.lower:
+		return -1
+	case v >= s.upper:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ReportAtMostEvery is a reporting strategy in which report is sent only if a specific time has passed since the last report.
+// It is meant to be used as a rate limit combined with other strategies using And.
+func ReportAtMostEvery(interval time.Duration) ReportingStrategy {
+	return ReportingStrategyFn(func(_ bool, lastReported time.Time) bool {
+		return time.Since(lastReported) >= interval
+	})
+}
+
+// And is a reporting strategy in which report is sent only if all provided strategies require it.
+func And(strategies ...ReportingStrategy) ReportingStrategy {
+	return &combinedStrategy{
+		strategies: strategies,
+		all:        true,
+	}
+}
+
+// Or is a reporting strategy in which report is sent if any of provided strategies requires it.
+func Or(strategies ...ReportingStrategy) ReportingStrategy {
+	return &combinedStrategy{
+		strategies: strategies,
+		all:        false,
+	}
+}
+
+// combinedStrategy is a private implementation of the And and Or reporting strategies.
+type combinedStrategy struct {
+	strategies []ReportingStrategy
+	all        bool
+}
+
+// ReportRequired determines if report is required based on input information.
+func (s *combinedStrategy) ReportRequired(hasChanged bool, lastReported time.Time) bool {
+	return s.combine(func(strategy ReportingStrategy) bool {
+		return strategy.ReportRequired(hasChanged, lastReported)
+	})
+}
+
+// ReportValueRequired determines if report is required based on the previously reported value, the new value and the time of the last report.
+func (s *combinedStrategy) ReportValueRequired(previous, current any, lastReported time.Time) bool {
+	return s.combine(func(strategy ReportingStrategy) bool {
+		return reportRequired(strategy, previous, current, lastReported)
+	})
+}
+
+// combine evaluates all strategies using the provided function, short-circuiting as soon as the result is known.
+func (s *combinedStrategy) combine(fn func(strategy ReportingStrategy) bool) bool {
+	for _, strategy := range s.strategies {
+		if fn(strategy) != s.all {
+			return !s.all
+		}
+	}
+
+	return s.all
+}
+
+// reportRequired determines if report is required by the provided strategy, passing values to it if it is value-aware.
+func reportRequired(strategy ReportingStrategy, previous, current any, lastReported time.Time) bool {
+	if s, ok := strategy.(ValueReportingStrategy); ok {
+		return s.ReportValueRequired(previous, current, lastReported)
+	}
+
+	return strategy.ReportRequired(!reflect.DeepEqual(previous, current), lastReported)
+}
+
+// toFloat converts a numeric value to float64.
+func toFloat(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() { //nolint:exhaustive
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	default:
+		return 0, false
+	}
+}
+
 // ReportingCache is a service responsible for storing reported values to allow determine if changes occurred.
 type ReportingCache interface {
 	// ReportRequired returns true if report for a provided key, sub key and value should be sent according to provided strategy.
@@ -53,20 +274,33 @@ type ReportingCache interface {
 	HasChanged(key, subKey string, value any) bool
 	// Reported marks value for a provided key and sub key as reported.
 	Reported(key, subKey string, value any)
+	// OverrideStrategy overrides strategies provided to ReportRequired with the provided one. Nil restores provided strategies.
+	OverrideStrategy(strategy ReportingStrategy)
+	// OverrideKeyStrategy overrides strategies provided to ReportRequired for a single key, taking precedence over the override of all keys.
+	// Nil restores the strategy of the key.
+	OverrideKeyStrategy(key string, strategy ReportingStrategy)
 }
 
 // NewReportingCache creates new instance of a reporting cache.
 func NewReportingCache() ReportingCache {
+	return newReportingCache()
+}
+
+// newReportingCache creates new instance of the in-memory reporting cache.
+func newReportingCache() *reportingCache {
 	return &reportingCache{
-		lock:   &sync.RWMutex{},
-		values: make(map[string]map[string]*value),
+		lock:         &sync.RWMutex{},
+		values:       make(map[string]map[string]*value),
+		keyOverrides: make(map[string]ReportingStrategy),
 	}
 }
 
 // reportingCache is a private implementation of reporting cache service.
 type reportingCache struct {
-	lock   *sync.RWMutex
-	values map[string]map[string]*value
+	lock         *sync.RWMutex
+	values       map[string]map[string]*value
+	override     ReportingStrategy
+	keyOverrides map[string]ReportingStrategy
 }
 
 // ReportRequired returns true if report for a provided key, sub key and value should be sent according to provided strategy.
@@ -79,7 +313,13 @@ func (c *reportingCache) ReportRequired(strategy ReportingStrategy, key, subKey
 		return true
 	}
 
-	return strategy.ReportRequired(v.hasChanged(val), v.reported)
+	if override, ok := c.keyOverrides[key]; ok {
+		strategy = override
+	} else if c.override != nil {
+		strategy = c.override
+	}
+
+	return reportRequired(strategy, v.value, val, v.reported)
 }
 
 // HasChanged returns true if value for a provided key and sub key changed.
@@ -115,6 +355,28 @@ func (c *reportingCache) Reported(key, subKey string, val any) {
 	v.value = val
 }
 
+// OverrideStrategy overrides strategies provided to ReportRequired with the provided one. Nil restores provided strategies.
+func (c *reportingCache) OverrideStrategy(strategy ReportingStrategy) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.override = strategy
+}
+
+// OverrideKeyStrategy overrides strategies provided to ReportRequired for a single key. Nil restores the strategy of the key.
+func (c *reportingCache) OverrideKeyStrategy(key string, strategy ReportingStrategy) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if strategy == nil {
+		delete(c.keyOverrides, key)
+
+		return
+	}
+
+	c.keyOverrides[key] = strategy
+}
+
 // value is an object holding reporting value and time of last report.
 type value struct {
 	reported time.Time
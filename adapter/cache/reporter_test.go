@@ -0,0 +1,282 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/futurehomeno/cliffhanger/adapter/cache"
+)
+
+func TestReportingCache_OverrideStrategy(t *testing.T) {
+	t.Parallel()
+
+	c := cache.NewReportingCache()
+	c.Reported("evt.sensor.report", "C", 21.5)
+
+	assert.False(t, c.ReportRequired(cache.ReportOnChangeOnly(), "evt.sensor.report", "C", 21.5))
+
+	c.OverrideStrategy(cache.ReportAlways())
+	assert.True(t, c.ReportRequired(cache.ReportOnChangeOnly(), "evt.sensor.report", "C", 21.5))
+
+	c.OverrideStrategy(nil)
+	assert.False(t, c.ReportRequired(cache.ReportOnChangeOnly(), "evt.sensor.report", "C", 21.5))
+}
+
+func TestReportingCache_OverrideKeyStrategy(t *testing.T) {
+	t.Parallel()
+
+	c := cache.NewReportingCache()
+	c.Reported("evt.state.report", "", "charging")
+	c.Reported("evt.current_session.report", "", 1.5)
+
+	c.OverrideKeyStrategy("evt.state.report", cache.ReportAlways())
+	assert.True(t, c.ReportRequired(cache.ReportOnChangeOnly(), "evt.state.report", "", "charging"))
+	assert.False(t, c.ReportRequired(cache.ReportOnChangeOnly(), "evt.current_session.report", "", 1.5))
+
+	c.OverrideStrategy(cache.ReportAlways())
+	c.OverrideKeyStrategy("evt.state.report", cache.ReportOnChangeOnly())
+	assert.False(t, c.ReportRequired(cache.ReportAlways(), "evt.state.report", "", "charging"))
+	assert.True(t, c.ReportRequired(cache.ReportOnChangeOnly(), "evt.current_session.report", "", 1.5))
+
+	c.OverrideKeyStrategy("evt.state.report", nil)
+	assert.True(t, c.ReportRequired(cache.ReportOnChangeOnly(), "evt.state.report", "", "charging"))
+}
+
+func TestReportOnDeadband(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name     string
+		strategy cache.ReportingStrategy
+		reported any
+		value    any
+		want     bool
+	}{
+		{
+			name:     "change within deadband",
+			strategy: cache.ReportOnDeadband(0.5, 0),
+			reported: 21.5,
+			value:    21.9,
+			want:     false,
+		},
+		{
+			name:     "change exceeding deadband",
+			strategy: cache.ReportOnDeadband(0.5, 0),
+			reported: 21.5,
+			value:    20.9,
+			want:     true,
+		},
+		{
+			name:     "integer values",
+			strategy: cache.ReportOnDeadband(5, 0),
+			reported: 100,
+			value:    int64(105),
+			want:     true,
+		},
+		{
+			name:     "non-numeric change",
+			strategy: cache.ReportOnDeadband(5, 0),
+			reported: "on",
+			value:    "off",
+			want:     true,
+		},
+		{
+			name:     "interval passed",
+			strategy: cache.ReportOnDeadband(0.5, time.Nanosecond),
+			reported: 21.5,
+			value:    21.5,
+			want:     true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := cache.NewReportingCache()
+			c.Reported("evt.sensor.report", "", tc.reported)
+
+			time.Sleep(time.Millisecond)
+
+			assert.Equal(t, tc.want, c.ReportRequired(tc.strategy, "evt.sensor.report", "", tc.value))
+		})
+	}
+}
+
+func TestValueReportingStrategies(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name     string
+		strategy cache.ReportingStrategy
+		reported any
+		value    any
+		want     bool
+	}{
+		{
+			name:     "percent deadband within band",
+			strategy: cache.ReportOnPercentDeadband(10, 0),
+			reported: 200.0,
+			value:    215.0,
+			want:     false,
+		},
+		{
+			name:     "percent deadband exceeding band",
+			strategy: cache.ReportOnPercentDeadband(10, 0),
+			reported: 200.0,
+			value:    179.0,
+			want:     true,
+		},
+		{
+			name:     "percent deadband from zero",
+			strategy: cache.ReportOnPercentDeadband(10, 0),
+			reported: 0.0,
+			value:    0.001,
+			want:     true,
+		},
+		{
+			name:     "hysteresis within band",
+			strategy: cache.ReportOnHysteresis(19, 21),
+			reported: 21.5,
+			value:    20.0,
+			want:     false,
+		},
+		{
+			name:     "hysteresis crossing lower threshold",
+			strategy: cache.ReportOnHysteresis(19, 21),
+			reported: 21.5,
+			value:    18.5,
+			want:     true,
+		},
+		{
+			name:     "hysteresis staying above upper threshold",
+			strategy: cache.ReportOnHysteresis(19, 21),
+			reported: 21.5,
+			value:    23.0,
+			want:     false,
+		},
+		{
+			name:     "hysteresis non-numeric change",
+			strategy: cache.ReportOnHysteresis(19, 21),
+			reported: "heat",
+			value:    "cool",
+			want:     true,
+		},
+		{
+			name:     "at most every before interval",
+			strategy: cache.ReportAtMostEvery(time.Hour),
+			reported: 1.0,
+			value:    2.0,
+			want:     false,
+		},
+		{
+			name:     "and rate limits deadband",
+			strategy: cache.And(cache.ReportOnDeadband(0.001, 0), cache.ReportAtMostEvery(time.Hour)),
+			reported: 10.0,
+			value:    10.5,
+			want:     false,
+		},
+		{
+			name:     "and with all strategies satisfied",
+			strategy: cache.And(cache.ReportOnDeadband(0.1, 0), cache.ReportAtMostEvery(time.Nanosecond)),
+			reported: 10.0,
+			value:    10.5,
+			want:     true,
+		},
+		{
+			name:     "or with value-unaware strategy",
+			strategy: cache.Or(cache.ReportOnDeadband(1, 0), cache.ReportAtLeastEvery(time.Nanosecond)),
+			reported: 10.0,
+			value:    10.5,
+			want:     true,
+		},
+		{
+			name:     "or with no strategy satisfied",
+			strategy: cache.Or(cache.ReportOnDeadband(1, 0), cache.ReportAtMostEvery(time.Hour)),
+			reported: 10.0,
+			value:    10.5,
+			want:     false,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := cache.NewReportingCache()
+			c.Reported("evt.meter.report", "", tc.reported)
+
+			time.Sleep(time.Millisecond)
+
+			assert.Equal(t, tc.want, c.ReportRequired(tc.strategy, "evt.meter.report", "", tc.value))
+		})
+	}
+}
+
+func TestReportingStrategySpec_Strategy(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name        string
+		spec        *cache.ReportingStrategySpec
+		wantDefault bool
+		wantErr     bool
+	}{
+		{name: "default", spec: &cache.ReportingStrategySpec{Type: cache.ReportingStrategyDefault}, wantDefault: true},
+		{name: "empty", spec: &cache.ReportingStrategySpec{}, wantDefault: true},
+		{name: "always", spec: &cache.ReportingStrategySpec{Type: cache.ReportingStrategyAlways}},
+		{name: "on change", spec: &cache.ReportingStrategySpec{Type: cache.ReportingStrategyOnChange}},
+		{name: "at least every", spec: &cache.ReportingStrategySpec{Type: cache.ReportingStrategyAtLeastEvery, Interval: 60}},
+		{name: "at least every without interval", spec: &cache.ReportingStrategySpec{Type: cache.ReportingStrategyAtLeastEvery}, wantErr: true},
+		{name: "deadband", spec: &cache.ReportingStrategySpec{Type: cache.ReportingStrategyDeadband, Delta: 0.5}},
+		{name: "deadband without delta", spec: &cache.ReportingStrategySpec{Type: cache.ReportingStrategyDeadband}, wantErr: true},
+		{name: "percent deadband", spec: &cache.ReportingStrategySpec{Type: cache.ReportingStrategyPercentDeadband, Percent: 5}},
+		{name: "percent deadband without percent", spec: &cache.ReportingStrategySpec{Type: cache.ReportingStrategyPercentDeadband}, wantErr: true},
+		{name: "hysteresis", spec: &cache.ReportingStrategySpec{Type: cache.ReportingStrategyHysteresis, Lower: 19, Upper: 21}},
+		{name: "hysteresis with inverted band", spec: &cache.ReportingStrategySpec{Type: cache.ReportingStrategyHysteresis, Lower: 21, Upper: 19}, wantErr: true},
+		{name: "at most every", spec: &cache.ReportingStrategySpec{Type: cache.ReportingStrategyAtMostEvery, Interval: 10}},
+		{name: "at most every without interval", spec: &cache.ReportingStrategySpec{Type: cache.ReportingStrategyAtMostEvery}, wantErr: true},
+		{
+			name: "and",
+			spec: &cache.ReportingStrategySpec{Type: cache.ReportingStrategyAnd, Strategies: []*cache.ReportingStrategySpec{
+				{Type: cache.ReportingStrategyDeadband, Delta: 0.5},
+				{Type: cache.ReportingStrategyAtMostEvery, Interval: 10},
+			}},
+		},
+		{name: "or without nested strategies", spec: &cache.ReportingStrategySpec{Type: cache.ReportingStrategyOr}, wantErr: true},
+		{
+			name: "or with invalid nested strategy",
+			spec: &cache.ReportingStrategySpec{Type: cache.ReportingStrategyOr, Strategies: []*cache.ReportingStrategySpec{
+				{Type: cache.ReportingStrategyDeadband},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "or with nested default strategy",
+			spec: &cache.ReportingStrategySpec{Type: cache.ReportingStrategyOr, Strategies: []*cache.ReportingStrategySpec{
+				{Type: cache.ReportingStrategyDefault},
+			}},
+			wantErr: true,
+		},
+		{name: "negative interval", spec: &cache.ReportingStrategySpec{Type: cache.ReportingStrategyAlways, Interval: -1}, wantErr: true},
+		{name: "unsupported type", spec: &cache.ReportingStrategySpec{Type: "sometimes"}, wantErr: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			strategy, err := tc.spec.Strategy()
+			if tc.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantDefault, strategy == nil)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ReportingStrategyType represents a type of the reporting strategy which can be configured at runtime.
+type ReportingStrategyType string
+
+const (
+	// ReportingStrategyDefault restores the reporting strategy configured by the application.
+	ReportingStrategyDefault ReportingStrategyType = "default"
+	// ReportingStrategyAlways represents the ReportAlways strategy.
+	ReportingStrategyAlways ReportingStrategyType = "always"
+	// ReportingStrategyOnChange represents the ReportOnChangeOnly strategy.
+	ReportingStrategyOnChange ReportingStrategyType = "on_change"
+	// ReportingStrategyAtLeastEvery represents the ReportAtLeastEvery strategy.
+	ReportingStrategyAtLeastEvery ReportingStrategyType = "at_least_every"
+	// ReportingStrategyDeadband represents the ReportOnDeadband strategy.
+	ReportingStrategyDeadband ReportingStrategyType = "deadband"
+	// ReportingStrategyPercentDeadband represents the ReportOnPercentDeadband strategy.
+	ReportingStrategyPercentDeadband ReportingStrategyType = "percent_deadband"
+	// ReportingStrategyHysteresis represents the ReportOnHysteresis strategy.
+	ReportingStrategyHysteresis ReportingStrategyType = "hysteresis"
+	// ReportingStrategyAtMostEvery represents the ReportAtMostEvery strategy.
+	ReportingStrategyAtMostEvery ReportingStrategyType = "at_most_every"
+	// ReportingStrategyAnd represents the And combinator of nested strategies.
+	ReportingStrategyAnd ReportingStrategyType = "and"
+	// ReportingStrategyOr represents the Or combinator of nested strategies.
+	ReportingStrategyOr ReportingStrategyType = "or"
+)
+
+// ReportingStrategySpec is a serializable specification of a reporting strategy.
+type ReportingStrategySpec struct {
+	// Type is a type of the reporting strategy.
+	Type ReportingStrategyType `json:"type"`
+	// Interval is an interval in seconds, required by the at least every and at most every strategies and optional for the deadband strategies.
+	Interval int `json:"interval,omitempty"`
+	// Delta is a minimal difference between reported numeric values, required by the deadband strategy.
+	Delta float64 `json:"delta,omitempty"`
+	// Percent is a minimal difference between reported numeric values in percents, required by the percentage deadband strategy.
+	Percent float64 `json:"percent,omitempty"`
+	// Lower is a lower threshold of the band, required by the hysteresis strategy.
+	Lower float64 `json:"lower,omitempty"`
+	// Upper is an upper threshold of the band, required by the hysteresis strategy.
+	Upper float64 `json:"upper,omitempty"`
+	// Strategies are nested strategies, required by the and and or combinators.
+	Strategies []*ReportingStrategySpec `json:"strategies,omitempty"`
+	// Event is an optional type of the event the override is limited to. If empty, the override applies to all events of the service.
+	Event string `json:"event,omitempty"`
+}
+
+// IsDefault returns true if the specification restores the reporting strategy configured by the application.
+func (s *ReportingStrategySpec) IsDefault() bool {
+	return s == nil || s.Type == ReportingStrategyDefault || s.Type == ""
+}
+
+// Strategy validates the specification and creates the reporting strategy. Returns nil for the default strategy.
+func (s *ReportingStrategySpec) Strategy() (ReportingStrategy, error) {
+	if s.IsDefault() {
+		return nil, nil
+	}
+
+	if s.Interval < 0 {
+		return nil, fmt.Errorf("reporting strategy: interval must not be negative, got %d", s.Interval)
+	}
+
+	interval := time.Duration(s.Interval) * time.Second
+
+	switch s.Type {
+	case ReportingStrategyAlways:
+		return ReportAlways(), nil
+	case ReportingStrategyOnChange:
+		return ReportOnChangeOnly(), nil
+	case ReportingStrategyAtLeastEvery:
+		if interval == 0 {
+			return nil, errors.New("reporting strategy: interval is required by the at least every strategy")
+		}
+
+		return ReportAtLeastEvery(interval), nil
+	case ReportingStrategyDeadband:
+		if s.Delta <= 0 {
+			return nil, fmt.Errorf("reporting strategy: delta must be positive, got %v", s.Delta)
+		}
+
+		return ReportOnDeadband(s.Delta, interval), nil
+	case ReportingStrategyPercentDeadband:
+		if s.Percent <= 0 {
+			return nil, fmt.Errorf("reporting strategy: percent must be positive, got %v", s.Percent)
+		}
+
+		return ReportOnPercentDeadband(s.Percent, interval), nil
+	case ReportingStrategyHysteresis:
+		if s.Lower >= s.Upper {
+			return nil, fmt.Errorf("reporting strategy: lower threshold %v must be below upper threshold %v", s.Lower, s.Upper)
+		}
+
+		return ReportOnHysteresis(s.Lower, s.Upper), nil
+	case ReportingStrategyAtMostEvery:
+		if interval == 0 {
+			return nil, errors.New("reporting strategy: interval is required by the at most every strategy")
+		}
+
+		return ReportAtMostEvery(interval), nil
+	case ReportingStrategyAnd, ReportingStrategyOr:
+		strategies, err := s.nested()
+		if err != nil {
+			return nil, err
+		}
+
+		if s.Type == ReportingStrategyAnd {
+			return And(strategies...), nil
+		}
+
+		return Or(strategies...), nil
+	default:
+		return nil, fmt.Errorf("reporting strategy: unsupported type %s", s.Type)
+	}
+}
+
+// nested validates and creates nested strategies of a combinator.
+func (s *ReportingStrategySpec) nested() ([]ReportingStrategy, error) {
+	if len(s.Strategies) == 0 {
+		return nil, fmt.Errorf("reporting strategy: nested strategies are required by the %s combinator", s.Type)
+	}
+
+	strategies := make([]ReportingStrategy, 0, len(s.Strategies))
+
+	for _, spec := range s.Strategies {
+		if spec.IsDefault() {
+			return nil, fmt.Errorf("reporting strategy: default strategy cannot be nested in the %s combinator", s.Type)
+		}
+
+		strategy, err := spec.Strategy()
+		if err != nil {
+			return nil, err
+		}
+
+		strategies = append(strategies, strategy)
+	}
+
+	return strategies, nil
+}
@@ -1,6 +1,10 @@
 package adapter
 
-import "github.com/futurehomeno/cliffhanger/event"
+import (
+	"slices"
+
+	"github.com/futurehomeno/cliffhanger/event"
+)
 
 // Connector represents a service responsible for thing connection management.
 type Connector interface {
@@ -74,12 +78,13 @@ const (
 type ConnectivityReports []*ConnectivityReport
 
 type ConnectivityReport struct {
-	Address        string `json:"address"`
-	Hash           string `json:"hash"`
-	Alias          string `json:"alias"`
-	PowerSource    string `json:"power_source"`
-	WakeupInterval string `json:"wakeup_interval"`
-	CommTechnology string `json:"comm_tech"`
+	Address        string            `json:"address"`
+	Hash           string            `json:"hash"`
+	Alias          string            `json:"alias"`
+	PowerSource    string            `json:"power_source"`
+	WakeupInterval string            `json:"wakeup_interval"`
+	CommTechnology string            `json:"comm_tech"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
 
 	*ConnectivityDetails
 }
@@ -149,3 +154,130 @@ func newConnectivityEvent(t Thing, c *ConnectivityDetails) *ConnectivityEvent {
 func WaitForConnectivityEvent() event.Filter {
 	return event.WaitFor[*ConnectivityEvent]()
 }
+
+// PingEvent is published by a thing every time a ping is executed.
+type PingEvent struct {
+	ThingEvent
+
+	Report *PingReport
+}
+
+func newPingEvent(t Thing, r *PingReport) *PingEvent {
+	return &PingEvent{
+		ThingEvent: NewThingEvent(t.Address(), nil),
+		Report:     r,
+	}
+}
+
+func WaitForPingEvent() event.Filter {
+	return event.WaitFor[*PingEvent]()
+}
+
+// ExclusionEvent is published by the adapter every time a thing is destroyed, so services tracking things can forget about it.
+type ExclusionEvent struct {
+	ThingEvent
+}
+
+func newExclusionEvent(address string) *ExclusionEvent {
+	return &ExclusionEvent{
+		ThingEvent: NewThingEvent(address, nil),
+	}
+}
+
+func WaitForExclusionEvent() event.Filter {
+	return event.WaitFor[*ExclusionEvent]()
+}
+
+// connectivityOverride represents a source of connectivity overrides, e.g. the watchdog or the reconciler,
+// used by a connector decorator to report a thing as down regardless of connectivity details provided by the wrapped connector.
+type connectivityOverride struct {
+	// isDown returns true if the thing must be reported as down.
+	isDown func() bool
+	// operationability is an optional operationability appended to connectivity details of a thing reported as down.
+	operationability OperationabilityT
+	// onPingSuccess is an optional hook called after a successful ping.
+	onPingSuccess func()
+	// onDisconnect is an optional hook called before the thing is disconnected.
+	onDisconnect func()
+}
+
+// overrideConnector wraps the provided connector, so its connectivity details reflect the provided override.
+// The returned connector is controllable if the provided one is.
+func overrideConnector(connector Connector, override *connectivityOverride) Connector {
+	oc := &overriddenConnector{
+		connector: connector,
+		override:  override,
+	}
+
+	if controllable, ok := connector.(ControllableConnector); ok {
+		return &overriddenControllableConnector{
+			overriddenConnector: oc,
+			controllable:        controllable,
+		}
+	}
+
+	return oc
+}
+
+// overriddenConnector is a connector decorator reflecting a connectivity override.
+type overriddenConnector struct {
+	connector Connector
+	override  *connectivityOverride
+}
+
+// Connectivity returns a connectivity report for the thing.
+func (c *overriddenConnector) Connectivity() *ConnectivityDetails {
+	details := c.connector.Connectivity()
+
+	if !c.override.isDown() {
+		return details
+	}
+
+	overridden := &ConnectivityDetails{
+		ConnStatus: ConnStatusDown,
+	}
+
+	if details != nil {
+		overridden.ConnQuality = details.ConnQuality
+		overridden.ConnType = details.ConnType
+		overridden.Operationability = slices.Clone(details.Operationability)
+	}
+
+	if c.override.operationability != "" && !slices.Contains(overridden.Operationability, c.override.operationability) {
+		overridden.Operationability = append(overridden.Operationability, c.override.operationability)
+	}
+
+	return overridden
+}
+
+// Ping executes a ping and returns a ping details report for the thing.
+func (c *overriddenConnector) Ping() *PingDetails {
+	details := c.connector.Ping()
+
+	if c.override.onPingSuccess != nil && details != nil && details.Status == PingResultSuccess {
+		c.override.onPingSuccess()
+	}
+
+	return details
+}
+
+// overriddenControllableConnector is a controllable connector decorator reflecting a connectivity override.
+type overriddenControllableConnector struct {
+	*overriddenConnector
+
+	controllable ControllableConnector
+}
+
+// Connect ensures that a thing is connected to the source of its data.
+func (c *overriddenControllableConnector) Connect(t Thing) {
+	c.controllable.Connect(t)
+}
+
+// Disconnect ensures that a thing is disconnected from the source of its data.
+func (c *overriddenControllableConnector) Disconnect(t Thing) {
+	if c.override.onDisconnect != nil {
+		c.override.onDisconnect()
+	}
+
+	c.controllable.Disconnect(t)
+}
@@ -12,6 +12,7 @@ const (
 
 	EventClassAdapterThing        = "thing"
 	EventClassInclusionReportSent = "inclusion_report_sent"
+	EventClassReconciliation      = "reconciliation"
 )
 
 type (
@@ -70,6 +71,13 @@ func NewInclusionReportSentEvent(address string, payload fimptype.ThingInclusion
 	}
 }
 
+func NewReconciliationEvent(address string, payload *ReconciliationDecision) ThingEvent {
+	return &thingEvent{
+		Event:   event.NewWithPayload(EventDomainAdapterThing, EventClassReconciliation, payload),
+		address: address,
+	}
+}
+
 func (e *serviceEvent) ServiceName() fimptype.ServiceNameT {
 	return e.serviceName
 }
@@ -102,6 +110,14 @@ func (e *thingEvent) Address() string {
 	return e.address
 }
 
+func (e *thingEvent) Payload() any {
+	if p, ok := e.Event.(event.EventWithPayload); ok {
+		return p.Payload()
+	}
+
+	return nil
+}
+
 func WaitForServiceEvent() event.Filter {
 	return event.WaitFor[ServiceEvent]()
 }
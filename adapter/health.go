@@ -0,0 +1,440 @@
+package adapter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/futurehomeno/fimpgo"
+	"github.com/futurehomeno/fimpgo/fimptype"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/futurehomeno/cliffhanger/database"
+	"github.com/futurehomeno/cliffhanger/event"
+	"github.com/futurehomeno/cliffhanger/task"
+)
+
+const (
+	// defaultHealthWindowSize is a default number of the most recent pings taken into account by the statistics of a thing.
+	defaultHealthWindowSize = 20
+	// defaultHealthDegradedSuccessRate is a default ping success rate below which a thing is considered degraded.
+	defaultHealthDegradedSuccessRate = 0.8
+	// healthBucket is a database bucket in which ping statistics of things are persisted.
+	healthBucket = "health"
+)
+
+// HealthConfig represents a configuration of the network health monitor.
+type HealthConfig struct {
+	// WindowSize is a number of the most recent pings taken into account by the statistics of a thing.
+	WindowSize int
+	// DegradedSuccessRate is a ping success rate below which a thing is considered degraded.
+	DegradedSuccessRate float64
+	// DegradedDelay is an optional average ping delay above which a thing is considered degraded. Zero disables the check.
+	DegradedDelay time.Duration
+	// Database is an optional database used to persist ping statistics of things across restarts.
+	Database database.Database
+}
+
+// withDefaults sets default values for all options which were not provided.
+func (c *HealthConfig) withDefaults() *HealthConfig {
+	if c.WindowSize <= 0 {
+		c.WindowSize = defaultHealthWindowSize
+	}
+
+	if c.DegradedSuccessRate == 0 {
+		c.DegradedSuccessRate = defaultHealthDegradedSuccessRate
+	}
+
+	return c
+}
+
+// PingStatistics represents rolling ping statistics of a single thing.
+type PingStatistics struct {
+	Address       string     `json:"address"`
+	Samples       int        `json:"samples"`
+	SuccessRate   float64    `json:"success_rate"`
+	AverageDelay  float64    `json:"avg_delay"`
+	LastStatus    PingResult `json:"last_status"`
+	LastDelay     int        `json:"last_delay"`
+	LastPingAt    time.Time  `json:"last_ping_at"`
+	Degraded      bool       `json:"degraded"`
+	DegradedSince *time.Time `json:"degraded_since,omitempty"`
+}
+
+// HealthReport is the object sent as value in the network health report summarizing connectivity of all things of the adapter.
+type HealthReport struct {
+	Things           int                  `json:"things"`
+	Up               int                  `json:"up"`
+	Down             int                  `json:"down"`
+	Quality          map[ConnQualityT]int `json:"conn_quality"`
+	SuccessRate      float64              `json:"success_rate"`
+	AveragePingDelay float64              `json:"avg_ping_delay"`
+	Degraded         []*PingStatistics    `json:"degraded"`
+}
+
+// HealthMonitor is a service collecting rolling ping statistics of things and summarizing connectivity of the adapter.
+// It processes ping events published by things and exclusion events published by the adapter, so it must be registered
+// in an event listener using NewHealthHandler.
+type HealthMonitor interface {
+	event.Processor
+
+	// Statistics returns rolling ping statistics of a thing under the provided address.
+	Statistics(address string) (*PingStatistics, bool)
+	// Report returns an aggregated health report of all things of the provided adapter.
+	Report(adapter Adapter) *HealthReport
+	// Forget removes ping statistics of a thing under the provided address, including the persisted ones.
+	Forget(address string) error
+}
+
+// HealthReporter is an optional interface of the adapter allowing to send network health reports.
+type HealthReporter interface {
+	// SendHealthReport sends an aggregated network health report of all things of the adapter.
+	SendHealthReport(monitor HealthMonitor) error
+}
+
+// SendHealthReport sends an aggregated network health report of all things of the adapter.
+func (a *adapter) SendHealthReport(monitor HealthMonitor) error {
+	msg := fimpgo.NewObjectMessage(
+		EvtNetworkHealthReport,
+		fimptype.ServiceNameT(a.name),
+		monitor.Report(a),
+		nil,
+		nil,
+		nil,
+	)
+
+	return a.publisher.PublishAdapterMessage(msg)
+}
+
+// NewHealthMonitor creates new instance of the network health monitor.
+func NewHealthMonitor(cfg *HealthConfig) HealthMonitor {
+	if cfg == nil {
+		cfg = &HealthConfig{}
+	}
+
+	return &healthMonitor{
+		cfg:       cfg.withDefaults(),
+		histories: make(map[string]*pingHistory),
+	}
+}
+
+// NewHealthHandler creates a new event handler feeding the health monitor with ping and exclusion events.
+func NewHealthHandler(monitor HealthMonitor, bufferSize int) *event.Handler {
+	return event.NewHandler(monitor, "adapter_health_monitor", bufferSize, event.Or(WaitForPingEvent(), WaitForExclusionEvent()))
+}
+
+// pingSample represents a result of a single ping.
+type pingSample struct {
+	Success   bool      `json:"success"`
+	Delay     int       `json:"delay"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// pingHistory represents the most recent ping samples of a thing.
+type pingHistory struct {
+	Samples       []pingSample `json:"samples"`
+	DegradedSince *time.Time   `json:"degraded_since,omitempty"`
+}
+
+type healthMonitor struct {
+	cfg       *HealthConfig
+	lock      sync.Mutex
+	histories map[string]*pingHistory
+}
+
+// Process records a result of a ping carried by a ping event and forgets statistics of a thing carried by an exclusion event.
+func (m *healthMonitor) Process(e event.Event) {
+	if exclusionEvent, ok := e.(*ExclusionEvent); ok {
+		if err := m.Forget(exclusionEvent.Address()); err != nil {
+			log.WithError(err).WithField("address", exclusionEvent.Address()).Errorf("health monitor: failed to forget ping statistics")
+		}
+
+		return
+	}
+
+	pingEvent, ok := e.(*PingEvent)
+	if !ok || pingEvent.Report == nil {
+		log.Warnf("[cliff] Received event type=%T exp=*adapter.PingEvent", e)
+
+		return
+	}
+
+	if err := m.record(pingEvent.Address(), pingEvent.Report, time.Now()); err != nil {
+		log.WithError(err).WithField("address", pingEvent.Address()).Errorf("health monitor: failed to record ping")
+	}
+}
+
+// Statistics returns rolling ping statistics of a thing under the provided address.
+func (m *healthMonitor) Statistics(address string) (*PingStatistics, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	h, err := m.history(address)
+	if err != nil {
+		log.WithError(err).WithField("address", address).Errorf("health monitor: failed to load ping statistics")
+	}
+
+	if h == nil || len(h.Samples) == 0 {
+		return nil, false
+	}
+
+	return m.statistics(address, h), true
+}
+
+// Report returns an aggregated health report of all things of the provided adapter.
+func (m *healthMonitor) Report(adapter Adapter) *HealthReport {
+	report := &HealthReport{
+		Quality:  make(map[ConnQualityT]int),
+		Degraded: make([]*PingStatistics, 0),
+	}
+
+	var samples, successes, delays int
+
+	for _, t := range adapter.Things() {
+		report.Things++
+
+		connectivity := t.ConnectivityReport()
+
+		switch connectivity.ConnStatus {
+		case ConnStatusUp:
+			report.Up++
+		case ConnStatusDown:
+			report.Down++
+		}
+
+		report.Quality[connectivity.ConnQuality]++
+
+		m.lock.Lock()
+
+		h, err := m.history(t.Address())
+		if err != nil {
+			log.WithError(err).WithField("address", t.Address()).Errorf("health monitor: failed to load ping statistics")
+		}
+
+		if h != nil && len(h.Samples) > 0 {
+			for _, s := range h.Samples {
+				samples++
+
+				if s.Success {
+					successes++
+					delays += s.Delay
+				}
+			}
+
+			if stats := m.statistics(t.Address(), h); stats.Degraded {
+				report.Degraded = append(report.Degraded, stats)
+			}
+		}
+
+		m.lock.Unlock()
+	}
+
+	if samples > 0 {
+		report.SuccessRate = float64(successes) / float64(samples)
+	}
+
+	if successes > 0 {
+		report.AveragePingDelay = float64(delays) / float64(successes)
+	}
+
+	slices.SortFunc(report.Degraded, func(a, b *PingStatistics) int {
+		return strings.Compare(a.Address, b.Address)
+	})
+
+	return report
+}
+
+// Forget removes ping statistics of a thing under the provided address, including the persisted ones.
+func (m *healthMonitor) Forget(address string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	delete(m.histories, address)
+
+	if m.cfg.Database == nil {
+		return nil
+	}
+
+	if err := m.cfg.Database.Delete(healthBucket, address); err != nil {
+		return fmt.Errorf("health monitor: failed to delete ping statistics of a thing with address %s: %w", address, err)
+	}
+
+	return nil
+}
+
+// record appends a result of a ping to the history of a thing and persists it if the database is configured.
+func (m *healthMonitor) record(address string, report *PingReport, at time.Time) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	h, err := m.history(address)
+	if err != nil {
+		log.WithError(err).WithField("address", address).Errorf("health monitor: failed to load ping statistics, starting anew")
+	}
+
+	if h == nil {
+		h = &pingHistory{}
+		m.histories[address] = h
+	}
+
+	sample := pingSample{
+		Delay:     report.Delay,
+		Timestamp: at,
+	}
+
+	if report.PingDetails != nil {
+		sample.Success = report.Status == PingResultSuccess
+	}
+
+	h.Samples = append(h.Samples, sample)
+
+	if len(h.Samples) > m.cfg.WindowSize {
+		h.Samples = slices.Clone(h.Samples[len(h.Samples)-m.cfg.WindowSize:])
+	}
+
+	switch degraded := m.isDegraded(h); {
+	case degraded && h.DegradedSince == nil:
+		h.DegradedSince = &at
+	case !degraded:
+		h.DegradedSince = nil
+	}
+
+	if m.cfg.Database == nil {
+		return nil
+	}
+
+	if err := m.cfg.Database.Set(healthBucket, address, h); err != nil {
+		return fmt.Errorf("health monitor: failed to persist ping statistics of a thing with address %s: %w", address, err)
+	}
+
+	return nil
+}
+
+// history returns the ping history of a thing, loading it from the database if needed. It must be used only while holding the lock.
+func (m *healthMonitor) history(address string) (*pingHistory, error) {
+	if h, ok := m.histories[address]; ok {
+		return h, nil
+	}
+
+	if m.cfg.Database == nil {
+		return nil, nil
+	}
+
+	h := &pingHistory{}
+
+	ok, err := m.cfg.Database.Get(healthBucket, address, h)
+	if err != nil {
+		return nil, fmt.Errorf("health monitor: failed to load ping statistics of a thing with address %s: %w", address, err)
+	}
+
+	if !ok {
+		return nil, nil
+	}
+
+	m.histories[address] = h
+
+	return h, nil
+}
+
+// statistics computes rolling ping statistics from the history of a thing. It must be used only while holding the lock.
+func (m *healthMonitor) statistics(address string, h *pingHistory) *PingStatistics {
+	last := h.Samples[len(h.Samples)-1]
+
+	stats := &PingStatistics{
+		Address:    address,
+		Samples:    len(h.Samples),
+		LastStatus: PingResultFailed,
+		LastDelay:  last.Delay,
+		LastPingAt: last.Timestamp,
+		Degraded:   h.DegradedSince != nil,
+	}
+
+	if last.Success {
+		stats.LastStatus = PingResultSuccess
+	}
+
+	if h.DegradedSince != nil {
+		since := *h.DegradedSince
+		stats.DegradedSince = &since
+	}
+
+	stats.SuccessRate, stats.AverageDelay = summarize(h.Samples)
+
+	return stats
+}
+
+// isDegraded returns true if the ping history of a thing indicates degraded connectivity.
+func (m *healthMonitor) isDegraded(h *pingHistory) bool {
+	successRate, averageDelay := summarize(h.Samples)
+
+	if successRate < m.cfg.DegradedSuccessRate {
+		return true
+	}
+
+	return m.cfg.DegradedDelay > 0 && averageDelay > float64(m.cfg.DegradedDelay.Milliseconds())
+}
+
+// summarize returns success rate and average delay in milliseconds of successful pings.
+func summarize(samples []pingSample) (float64, float64) {
+	if len(samples) == 0 {
+		return 0, 0
+	}
+
+	var successes, delays int
+
+	for _, s := range samples {
+		if s.Success {
+			successes++
+			delays += s.Delay
+		}
+	}
+
+	if successes == 0 {
+		return 0, 0
+	}
+
+	return float64(successes) / float64(len(samples)), float64(delays) / float64(successes)
+}
+
+// TaskHealthMonitor creates tasks periodically pinging all things of the adapter and sending the network health report.
+func TaskHealthMonitor(
+	adapter Adapter,
+	monitor HealthMonitor,
+	pingInterval time.Duration,
+	reportingInterval time.Duration,
+	voters ...task.Voter,
+) []*task.Task {
+	voters = append(voters, IsInitialized(adapter))
+
+	return []*task.Task{
+		task.New(handlePing(adapter), pingInterval, voters...),
+		task.New(handleHealthReporting(adapter, monitor), reportingInterval, voters...),
+	}
+}
+
+func handlePing(adapter Adapter) func() {
+	return func() {
+		for _, t := range adapter.Things() {
+			if err := t.SendPingReport(); err != nil {
+				log.WithError(err).WithField("address", t.Address()).Errorf("failed to send ping report")
+			}
+		}
+	}
+}
+
+func handleHealthReporting(adapter Adapter, monitor HealthMonitor) func() {
+	return func() {
+		reporter, ok := adapter.(HealthReporter)
+		if !ok {
+			log.Errorf("adapter does not support health reporting")
+
+			return
+		}
+
+		if err := reporter.SendHealthReport(monitor); err != nil {
+			log.WithError(err).Errorf("failed to send health report")
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package adapter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/futurehomeno/fimpgo"
+	"github.com/futurehomeno/fimpgo/fimptype"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/event"
+	"github.com/futurehomeno/cliffhanger/router"
+	"github.com/futurehomeno/cliffhanger/task"
+	adapterhelper "github.com/futurehomeno/cliffhanger/test/helper/adapter"
+	databasehelper "github.com/futurehomeno/cliffhanger/test/helper/database"
+	mockedadapter "github.com/futurehomeno/cliffhanger/test/mocks/adapter"
+	"github.com/futurehomeno/cliffhanger/test/suite"
+)
+
+func TestTaskHealthMonitor(t *testing.T) { //nolint:paralleltest
+	var monitor adapter.HealthMonitor
+
+	s := &suite.Suite{
+		Cases: []*suite.Case{
+			{
+				Name:     "health monitor pings things and summarizes their connectivity",
+				TearDown: adapterhelper.TearDownAdapter(testAdapterWorkDir),
+				Setup:    setupHealthMonitor(&monitor),
+				Nodes: []*suite.Node{
+					{
+						Name: "things are pinged periodically",
+						Expectations: []*suite.Expectation{
+							expectPingReport(testThingAddressB, adapter.PingResultSuccess).AtLeastOnce(),
+							expectPingReport(testThingAddressC, adapter.PingResultFailed).AtLeastOnce(),
+						},
+					},
+					{
+						Name: "health report is sent periodically",
+						Expectations: []*suite.Expectation{
+							expectHealthReport(func(r *adapter.HealthReport) bool {
+								return r.Things == 2 && r.Up == 2 && r.Down == 0 && r.Quality[adapter.ConnQualityUndefined] == 2
+							}).AtLeastOnce(),
+						},
+					},
+					{
+						Name:    "health report is sent on demand",
+						Command: suite.NullMessage(testAdapterCmdTopic, adapter.CmdNetworkGetHealthReport, testAdapterName),
+						Expectations: []*suite.Expectation{
+							expectHealthReport(func(r *adapter.HealthReport) bool {
+								return len(r.Degraded) == 1 && r.Degraded[0].Address == testThingAddressC && r.SuccessRate > 0 && r.SuccessRate < 1
+							}).AtLeastOnce(),
+						},
+					},
+					{
+						Name: "statistics are collected per thing",
+						InitCallbacks: []suite.Callback{
+							func(t *testing.T) {
+								t.Helper()
+
+								stats, ok := monitor.Statistics(testThingAddressB)
+								assert.True(t, ok)
+								assert.Equal(t, 1.0, stats.SuccessRate)
+								assert.Equal(t, adapter.PingResultSuccess, stats.LastStatus)
+								assert.False(t, stats.Degraded)
+
+								stats, ok = monitor.Statistics(testThingAddressC)
+								assert.True(t, ok)
+								assert.Equal(t, 0.0, stats.SuccessRate)
+								assert.True(t, stats.Degraded)
+								assert.NotNil(t, stats.DegradedSince)
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	s.Run(t)
+}
+
+func TestHealthMonitor_Persistence(t *testing.T) {
+	t.Parallel()
+
+	db := databasehelper.NewDatabase(t)
+
+	monitor := adapter.NewHealthMonitor(&adapter.HealthConfig{
+		WindowSize:    2,
+		DegradedDelay: 100 * time.Millisecond,
+		Database:      db,
+	})
+
+	for _, delay := range []int{500, 10, 20} {
+		monitor.Process(&adapter.PingEvent{
+			ThingEvent: adapter.NewThingEvent("2", nil),
+			Report: &adapter.PingReport{
+				Address:     "2",
+				Delay:       delay,
+				PingDetails: &adapter.PingDetails{Status: adapter.PingResultSuccess},
+			},
+		})
+	}
+
+	stats, ok := adapter.NewHealthMonitor(&adapter.HealthConfig{Database: db}).Statistics("2")
+	assert.True(t, ok)
+	assert.Equal(t, 2, stats.Samples)
+	assert.Equal(t, 15.0, stats.AverageDelay)
+	assert.Equal(t, 20, stats.LastDelay)
+	assert.False(t, stats.Degraded)
+
+	_, ok = monitor.Statistics("3")
+	assert.False(t, ok)
+
+	monitor.Process(&adapter.ExclusionEvent{ThingEvent: adapter.NewThingEvent("2", nil)})
+
+	_, ok = monitor.Statistics("2")
+	assert.False(t, ok, "statistics of an excluded thing must be forgotten")
+
+	_, ok = adapter.NewHealthMonitor(&adapter.HealthConfig{Database: db}).Statistics("2")
+	assert.False(t, ok, "persisted statistics of an excluded thing must be deleted")
+}
+
+func setupHealthMonitor(monitor *adapter.HealthMonitor) suite.BaseSetup {
+	return func(t *testing.T, mqtt *fimpgo.MqttTransport) ([]*router.Routing, []*task.Task, []suite.Mock) {
+		t.Helper()
+
+		*monitor = adapter.NewHealthMonitor(nil)
+
+		eventManager := event.NewManager()
+		listener := event.NewListener(eventManager, adapter.NewHealthHandler(*monitor, 10))
+
+		if err := listener.Start(); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Cleanup(func() {
+			_ = listener.Stop()
+		})
+
+		factory := adapterhelper.FactoryHelper(func(_ adapter.Adapter, publisher adapter.Publisher, thingState adapter.ThingState) (adapter.Thing, error) {
+			status := adapter.PingResultSuccess
+			if thingState.Address() == testThingAddressC {
+				status = adapter.PingResultFailed
+			}
+
+			return adapter.NewThing(publisher, thingState, &adapter.ThingConfig{
+				InclusionReport: &fimptype.ThingInclusionReport{Address: thingState.Address()},
+				Connector:       mockedadapter.NewDefaultConnector(t).MockPing(&adapter.PingDetails{Status: status}, false),
+			}), nil
+		})
+
+		state, err := adapter.NewState(testAdapterWorkDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ad := adapterhelper.SeedAdapter(t, adapter.NewAdapter(mqtt, eventManager, factory, state, "test_adapter", "1"), adapter.ThingSeeds{
+			{ID: "B", CustomAddress: testThingAddressB},
+			{ID: "C", CustomAddress: testThingAddressC},
+		})
+
+		return adapter.RouteHealthMonitor(ad, *monitor), adapter.TaskHealthMonitor(ad, *monitor, reportingInterval, 3*reportingInterval), nil
+	}
+}
+
+func expectPingReport(address string, status adapter.PingResult) *suite.Expectation {
+	return suite.NewExpectation().
+		ExpectTopic("pt:j1/mt:evt/rt:ad/rn:test_adapter/ad:1").
+		ExpectType(adapter.EvtPingReport).
+		Expect(router.MessageVoterFn(func(m *fimpgo.Message) bool {
+			report := &adapter.PingReport{}
+
+			if err := m.Payload.GetObjectValue(report); err != nil {
+				return false
+			}
+
+			return report.Address == address && report.PingDetails != nil && report.Status == status
+		}))
+}
+
+func expectHealthReport(check func(r *adapter.HealthReport) bool) *suite.Expectation {
+	return suite.NewExpectation().
+		ExpectTopic(testAdapterEvtTopic).
+		ExpectType(adapter.EvtNetworkHealthReport).
+		ExpectService(testAdapterName).
+		Expect(router.MessageVoterFn(func(m *fimpgo.Message) bool {
+			report := &adapter.HealthReport{}
+
+			if err := m.Payload.GetObjectValue(report); err != nil {
+				return false
+			}
+
+			return check(report)
+		}))
+}
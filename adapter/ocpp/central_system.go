@@ -0,0 +1,272 @@
+package ocpp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/futurehomeno/cliffhanger/adapter/service/chargepoint"
+)
+
+const (
+	// defaultHeartbeatInterval is a default interval of heartbeats requested from chargers.
+	defaultHeartbeatInterval = 5 * time.Minute
+	// defaultRemoteIDTag is a default ID tag used to start transactions remotely.
+	defaultRemoteIDTag = "central_system"
+	// defaultMaxCurrent is a default charge point wide current limit assumed until it is set.
+	defaultMaxCurrent = 32
+	// readHeaderTimeout is a timeout for reading headers of WebSocket handshakes.
+	readHeaderTimeout = 10 * time.Second
+)
+
+// transactionEpoch is a moment from which transaction IDs are seeded. IDs must fit into a signed 32-bit integer required by many chargers.
+var transactionEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Config represents a configuration of the central system.
+type Config struct {
+	// Address is a TCP address on which the central system listens, e.g. ":8887". Chargers connect to ws://<address>/<path>/<identity>.
+	Address string
+	// HeartbeatInterval is an interval of heartbeats requested from chargers in response to their boot notification.
+	HeartbeatInterval time.Duration
+	// CallTimeout is a period after which a call to a charger fails if it remains unanswered.
+	CallTimeout time.Duration
+	// RemoteIDTag is an ID tag used to start transactions remotely. It is always authorized.
+	RemoteIDTag string
+	// MaxCurrent is a charge point wide current limit reported until it is set.
+	MaxCurrent int
+	// Authorizer is an optional allow-list of ID tags. If not provided, all ID tags are accepted.
+	Authorizer chargepoint.Authorizer
+	// OnBoot is an optional callback invoked asynchronously whenever a charger sends a boot notification, e.g. to create its thing.
+	OnBoot func(charger *Charger)
+}
+
+// withDefaults sets default values for all options which were not provided.
+func (c *Config) withDefaults() *Config {
+	if c.HeartbeatInterval <= 0 {
+		c.HeartbeatInterval = defaultHeartbeatInterval
+	}
+
+	if c.CallTimeout <= 0 {
+		c.CallTimeout = defaultCallTimeout
+	}
+
+	if c.RemoteIDTag == "" {
+		c.RemoteIDTag = defaultRemoteIDTag
+	}
+
+	if c.MaxCurrent <= 0 {
+		c.MaxCurrent = defaultMaxCurrent
+	}
+
+	return c
+}
+
+// CentralSystem is a local OCPP 1.6J central system accepting WebSocket connections of chargers.
+type CentralSystem interface {
+	// Start starts listening for chargers.
+	Start() error
+	// Stop stops listening and disconnects all chargers.
+	Stop() error
+	// Addr returns the address on which the central system listens. It is available only after the start.
+	Addr() string
+	// Charger returns the charger of the provided identity, registering it as disconnected if it is not known yet.
+	Charger(id string) *Charger
+	// Chargers returns all known chargers.
+	Chargers() []*Charger
+}
+
+// NewCentralSystem creates new instance of a central system.
+func NewCentralSystem(cfg *Config) CentralSystem {
+	return &centralSystem{
+		cfg:      cfg.withDefaults(),
+		now:      time.Now,
+		chargers: make(map[string]*Charger),
+		upgrader: websocket.Upgrader{
+			Subprotocols: []string{Subprotocol},
+			CheckOrigin:  func(*http.Request) bool { return true },
+		},
+	}
+}
+
+type centralSystem struct {
+	cfg      *Config
+	now      func() time.Time
+	upgrader websocket.Upgrader
+
+	lock          sync.Mutex
+	server        *http.Server
+	listener      net.Listener
+	chargers      map[string]*Charger
+	transactionID int
+}
+
+// Start starts listening for chargers.
+func (s *centralSystem) Start() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.server != nil {
+		return errors.New("ocpp: central system is already started")
+	}
+
+	listener, err := (&net.ListenConfig{}).Listen(context.Background(), "tcp", s.cfg.Address)
+	if err != nil {
+		return fmt.Errorf("ocpp: failed to listen on %s: %w", s.cfg.Address, err)
+	}
+
+	s.listener = listener
+	s.server = &http.Server{
+		Handler:           http.HandlerFunc(s.serve),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	go func(server *http.Server) {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.WithError(err).Error("ocpp: central system stopped unexpectedly")
+		}
+	}(s.server)
+
+	return nil
+}
+
+// Stop stops listening and disconnects all chargers.
+func (s *centralSystem) Stop() error {
+	s.lock.Lock()
+	server := s.server
+	s.server = nil
+	s.listener = nil
+	chargers := s.chargerList()
+	s.lock.Unlock()
+
+	if server == nil {
+		return nil
+	}
+
+	err := server.Close()
+
+	// Hijacked WebSocket connections are not closed by the HTTP server.
+	for _, c := range chargers {
+		c.lock.RLock()
+		conn := c.conn
+		c.lock.RUnlock()
+
+		if conn != nil {
+			_ = conn.Close()
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("ocpp: failed to stop central system: %w", err)
+	}
+
+	return nil
+}
+
+// Addr returns the address on which the central system listens.
+func (s *centralSystem) Addr() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.listener == nil {
+		return ""
+	}
+
+	return s.listener.Addr().String()
+}
+
+// Charger returns the charger of the provided identity, registering it as disconnected if it is not known yet.
+func (s *centralSystem) Charger(id string) *Charger {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	c, ok := s.chargers[id]
+	if !ok {
+		c = newCharger(id, s)
+		s.chargers[id] = c
+	}
+
+	return c
+}
+
+// Chargers returns all known chargers.
+func (s *centralSystem) Chargers() []*Charger {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.chargerList()
+}
+
+// chargerList returns all known chargers sorted by their identity. Lock must be held by the caller.
+func (s *centralSystem) chargerList() []*Charger {
+	chargers := make([]*Charger, 0, len(s.chargers))
+
+	for _, c := range s.chargers {
+		chargers = append(chargers, c)
+	}
+
+	slices.SortFunc(chargers, func(a, b *Charger) int {
+		return strings.Compare(a.id, b.id)
+	})
+
+	return chargers
+}
+
+// nextTransactionID returns a new transaction ID. IDs are seeded with seconds elapsed since the transaction epoch, so they are not reused
+// after a restart of the central system unless transactions were started more often than once per second on average.
+func (s *centralSystem) nextTransactionID() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.transactionID = max(s.transactionID+1, int(s.now().Sub(transactionEpoch)/time.Second))
+
+	return s.transactionID
+}
+
+// serve upgrades a connection of a charger, which identifies itself by the last segment of the URL path.
+func (s *centralSystem) serve(w http.ResponseWriter, r *http.Request) {
+	id := path.Base(r.URL.Path)
+	if id == "/" || id == "." {
+		http.Error(w, "charge point identity is missing", http.StatusNotFound)
+
+		return
+	}
+
+	if !slices.Contains(websocket.Subprotocols(r), Subprotocol) {
+		http.Error(w, fmt.Sprintf("subprotocol %s is required", Subprotocol), http.StatusBadRequest)
+
+		return
+	}
+
+	ws, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.WithError(err).Errorf("ocpp: failed to upgrade connection of charger %s", id)
+
+		return
+	}
+
+	charger := s.Charger(id)
+	conn := NewConnection(ws, charger, s.cfg.CallTimeout)
+
+	charger.attach(conn)
+	conn.Start()
+
+	log.Infof("ocpp: charger %s connected from %s", id, r.RemoteAddr)
+
+	go func() {
+		<-conn.Done()
+
+		charger.detach(conn)
+
+		log.Infof("ocpp: charger %s disconnected", id)
+	}()
+}
@@ -0,0 +1,297 @@
+package ocpp_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/adapter/ocpp"
+	"github.com/futurehomeno/cliffhanger/adapter/service/chargepoint"
+	"github.com/futurehomeno/cliffhanger/adapter/service/numericmeter"
+	databasehelper "github.com/futurehomeno/cliffhanger/test/helper/database"
+	ocpphelper "github.com/futurehomeno/cliffhanger/test/helper/ocpp"
+)
+
+func newTestCentralSystem(t *testing.T, cfg *ocpp.Config) ocpp.CentralSystem {
+	t.Helper()
+
+	cfg.Address = "127.0.0.1:0"
+	cfg.CallTimeout = 5 * time.Second
+
+	system := ocpp.NewCentralSystem(cfg)
+	require.NoError(t, system.Start())
+
+	t.Cleanup(func() {
+		assert.NoError(t, system.Stop())
+	})
+
+	return system
+}
+
+func TestCentralSystem(t *testing.T) {
+	t.Parallel()
+
+	booted := make(chan *ocpp.Charger, 1)
+	system := newTestCentralSystem(t, &ocpp.Config{
+		HeartbeatInterval: time.Minute,
+		OnBoot:            func(c *ocpp.Charger) { booted <- c },
+	})
+
+	sim := ocpphelper.NewSimulator(t, system.Addr(), "cp-1")
+
+	boot := sim.BootNotification(t, "Acme", "Wallbox")
+	assert.Equal(t, ocpp.RegistrationStatusAccepted, boot.Status)
+	assert.Equal(t, 60, boot.Interval)
+
+	var charger *ocpp.Charger
+
+	select {
+	case charger = <-booted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("boot callback was not invoked")
+	}
+
+	assert.Equal(t, "cp-1", charger.ID())
+	assert.Equal(t, "Acme", charger.BootInfo().ChargePointVendor)
+	assert.Same(t, charger, system.Charger("cp-1"))
+	assert.Equal(t, adapter.ConnStatusUp, charger.Connectivity().ConnStatus)
+
+	var heartbeat ocpp.HeartbeatResponse
+
+	assert.NoError(t, sim.Call(ocpp.ActionHeartbeat, &ocpp.HeartbeatRequest{}, &heartbeat))
+	assert.False(t, heartbeat.CurrentTime.IsZero())
+
+	sim.StatusNotification(t, ocpp.StatusPreparing)
+
+	state, err := charger.ChargepointStateReport()
+	assert.NoError(t, err)
+	assert.Equal(t, chargepoint.StateRequesting, state)
+
+	// Remote start.
+	assert.NoError(t, charger.StartChargepointCharging(&chargepoint.ChargingSettings{User: "John"}))
+
+	remoteStart := &ocpp.RemoteStartTransactionRequest{}
+	sim.LastCall(t, ocpp.ActionRemoteStartTransaction, remoteStart)
+	assert.Equal(t, "central_system", remoteStart.IDTag)
+	assert.Equal(t, ocpp.ConnectorID, *remoteStart.ConnectorID)
+
+	transaction := sim.StartTransaction(t, remoteStart.IDTag, 1000)
+	assert.Equal(t, ocpp.AuthorizationStatusAccepted, transaction.IDTagInfo.Status)
+
+	sim.StatusNotification(t, ocpp.StatusCharging)
+	sim.MeterValues(t, transaction.TransactionID, 3500, 7200, 10, 11, 12)
+
+	state, err = charger.ChargepointStateReport()
+	assert.NoError(t, err)
+	assert.Equal(t, chargepoint.StateCharging, state)
+
+	session, err := charger.ChargepointCurrentSessionReport()
+	assert.NoError(t, err)
+	assert.InDelta(t, 2.5, session.SessionEnergy, 0.001)
+	assert.Equal(t, "John", session.User)
+
+	power, err := charger.MeterReport(numericmeter.UnitW)
+	assert.NoError(t, err)
+	assert.InDelta(t, 7200, power, 0.001)
+
+	energy, err := charger.MeterReport(numericmeter.UnitKWh)
+	assert.NoError(t, err)
+	assert.InDelta(t, 3.5, energy, 0.001)
+
+	_, err = charger.MeterReport(numericmeter.UnitV)
+	assert.Error(t, err)
+
+	extended, err := charger.MeterExtendedReport(numericmeter.Values{numericmeter.ValueCurrentPhase2, numericmeter.ValueEnergyImport})
+	assert.NoError(t, err)
+	assert.Equal(t, numericmeter.ValuesReport{numericmeter.ValueCurrentPhase2: 11, numericmeter.ValueEnergyImport: 3.5}, extended)
+
+	// Current limits.
+	assert.NoError(t, charger.SetChargepointOfferedCurrent(10))
+
+	profile := &ocpp.SetChargingProfileRequest{}
+	sim.LastCall(t, ocpp.ActionSetChargingProfile, profile)
+	assert.Equal(t, ocpp.ConnectorID, profile.ConnectorID)
+	assert.Equal(t, ocpp.ChargingProfilePurposeTxProfile, profile.CsChargingProfiles.ChargingProfilePurpose)
+	assert.Equal(t, transaction.TransactionID, *profile.CsChargingProfiles.TransactionID)
+	assert.InDelta(t, 10, profile.CsChargingProfiles.ChargingSchedule.ChargingSchedulePeriod[0].Limit, 0.001)
+
+	assert.NoError(t, charger.SetChargepointMaxCurrent(16))
+
+	sim.LastCall(t, ocpp.ActionSetChargingProfile, profile)
+	assert.Equal(t, 0, profile.ConnectorID)
+	assert.Equal(t, ocpp.ChargingProfilePurposeChargePointMaxProfile, profile.CsChargingProfiles.ChargingProfilePurpose)
+	assert.Equal(t, ocpp.ChargingRateUnitA, profile.CsChargingProfiles.ChargingSchedule.ChargingRateUnit)
+
+	maxCurrent, err := charger.ChargepointMaxCurrentReport()
+	assert.NoError(t, err)
+	assert.Equal(t, 16, maxCurrent)
+
+	sim.Respond(ocpp.ActionSetChargingProfile, &ocpp.SetChargingProfileResponse{Status: ocpp.ChargingProfileStatusRejected})
+	assert.Error(t, charger.SetChargepointMaxCurrent(20))
+
+	maxCurrent, err = charger.ChargepointMaxCurrentReport()
+	assert.NoError(t, err)
+	assert.Equal(t, 16, maxCurrent)
+
+	// Configuration.
+	assert.NoError(t, charger.ChangeConfiguration("MeterValueSampleInterval", "30"))
+
+	configuration := &ocpp.ChangeConfigurationRequest{}
+	sim.LastCall(t, ocpp.ActionChangeConfiguration, configuration)
+	assert.Equal(t, &ocpp.ChangeConfigurationRequest{Key: "MeterValueSampleInterval", Value: "30"}, configuration)
+
+	sim.Respond(ocpp.ActionChangeConfiguration, &ocpp.ChangeConfigurationResponse{Status: ocpp.ConfigurationStatusNotSupported})
+	assert.Error(t, charger.ChangeConfiguration("Unknown", "1"))
+
+	// Remote stop.
+	assert.NoError(t, charger.StopChargepointCharging())
+
+	remoteStop := &ocpp.RemoteStopTransactionRequest{}
+	sim.LastCall(t, ocpp.ActionRemoteStopTransaction, remoteStop)
+	assert.Equal(t, transaction.TransactionID, remoteStop.TransactionID)
+
+	sim.StopTransaction(t, transaction.TransactionID, 4000, "Remote")
+	sim.StatusNotification(t, ocpp.StatusAvailable)
+
+	state, err = charger.ChargepointStateReport()
+	assert.NoError(t, err)
+	assert.Equal(t, chargepoint.StateDisconnected, state)
+
+	session, err = charger.ChargepointCurrentSessionReport()
+	assert.NoError(t, err)
+	assert.Zero(t, session.SessionEnergy)
+	assert.InDelta(t, 3.0, session.PreviousSessionEnergy, 0.001)
+	assert.False(t, session.FinishedAt.IsZero())
+
+	assert.Error(t, charger.StopChargepointCharging())
+
+	// Without a transaction, offered current applies to the next one.
+	sim.Respond(ocpp.ActionSetChargingProfile, &ocpp.SetChargingProfileResponse{Status: ocpp.ChargingProfileStatusAccepted})
+	assert.NoError(t, charger.SetChargepointOfferedCurrent(8))
+
+	profile = &ocpp.SetChargingProfileRequest{}
+	sim.LastCall(t, ocpp.ActionSetChargingProfile, profile)
+	assert.Equal(t, ocpp.ChargingProfilePurposeTxDefaultProfile, profile.CsChargingProfiles.ChargingProfilePurpose)
+	assert.Nil(t, profile.CsChargingProfiles.TransactionID)
+}
+
+func TestCentralSystem_Authorization(t *testing.T) {
+	t.Parallel()
+
+	db := databasehelper.NewDatabase(t)
+
+	authorizer, err := chargepoint.NewAuthorizer(&chargepoint.AuthorizationConfig{Database: db, Key: "cp-1"})
+	require.NoError(t, err)
+	require.NoError(t, authorizer.SetList(&chargepoint.AuthorizationList{
+		Tags: []*chargepoint.Tag{
+			{ID: "04a1b2", Name: "John"},
+			{ID: "04c3d4", Name: "Guest", ValidTo: time.Now().Add(-time.Hour)},
+		},
+	}))
+
+	system := newTestCentralSystem(t, &ocpp.Config{Authorizer: authorizer})
+	sim := ocpphelper.NewSimulator(t, system.Addr(), "cp-1")
+	charger := system.Charger("cp-1")
+
+	authorization := &ocpp.AuthorizeResponse{}
+	assert.NoError(t, sim.Call(ocpp.ActionAuthorize, &ocpp.AuthorizeRequest{IDTag: "unknown"}, authorization))
+	assert.Equal(t, ocpp.AuthorizationStatusInvalid, authorization.IDTagInfo.Status)
+
+	assert.NoError(t, sim.Call(ocpp.ActionAuthorize, &ocpp.AuthorizeRequest{IDTag: "04C3D4"}, authorization))
+	assert.Equal(t, ocpp.AuthorizationStatusExpired, authorization.IDTagInfo.Status)
+
+	assert.NoError(t, sim.Call(ocpp.ActionAuthorize, &ocpp.AuthorizeRequest{IDTag: "04A1B2"}, authorization))
+	assert.Equal(t, ocpp.AuthorizationStatusAccepted, authorization.IDTagInfo.Status)
+
+	rejected := sim.StartTransaction(t, "unknown", 0)
+	assert.Equal(t, ocpp.AuthorizationStatusInvalid, rejected.IDTagInfo.Status)
+
+	transaction := sim.StartTransaction(t, "04A1B2", 0)
+	assert.Equal(t, ocpp.AuthorizationStatusAccepted, transaction.IDTagInfo.Status)
+	assert.NotEqual(t, rejected.TransactionID, transaction.TransactionID)
+
+	session, err := charger.ChargepointCurrentSessionReport()
+	assert.NoError(t, err)
+	assert.Equal(t, "John", session.User)
+}
+
+func TestCentralSystem_UnknownTransaction(t *testing.T) {
+	t.Parallel()
+
+	system := newTestCentralSystem(t, &ocpp.Config{})
+	sim := ocpphelper.NewSimulator(t, system.Addr(), "cp-1")
+	charger := system.Charger("cp-1")
+
+	// Transactions started before a restart of the central system are unknown to it.
+	sim.StopTransaction(t, 7, 1000, "Local")
+
+	session, err := charger.ChargepointCurrentSessionReport()
+	assert.NoError(t, err)
+	assert.False(t, session.FinishedAt.IsZero(), "stop of an unknown transaction finishes the session")
+
+	sim.StatusNotification(t, ocpp.StatusCharging)
+	sim.MeterValues(t, 42, 2000, 7200)
+	sim.MeterValues(t, 42, 2500, 7200)
+
+	session, err = charger.ChargepointCurrentSessionReport()
+	assert.NoError(t, err)
+	assert.True(t, session.FinishedAt.IsZero())
+	assert.InDelta(t, 0.5, session.SessionEnergy, 0.001)
+
+	assert.NoError(t, charger.StopChargepointCharging())
+
+	remoteStop := &ocpp.RemoteStopTransactionRequest{}
+	sim.LastCall(t, ocpp.ActionRemoteStopTransaction, remoteStop)
+	assert.Equal(t, 42, remoteStop.TransactionID, "ongoing transaction is adopted")
+
+	sim.StopTransaction(t, 42, 3000, "Remote")
+
+	session, err = charger.ChargepointCurrentSessionReport()
+	assert.NoError(t, err)
+	assert.InDelta(t, 1.0, session.PreviousSessionEnergy, 0.001)
+
+	// Transaction IDs are not reused after a restart.
+	transaction := sim.StartTransaction(t, "central_system", 3000)
+	assert.Greater(t, transaction.TransactionID, 1000)
+}
+
+func TestCentralSystem_Errors(t *testing.T) {
+	t.Parallel()
+
+	system := newTestCentralSystem(t, &ocpp.Config{})
+
+	charger := system.Charger("cp-offline")
+	assert.ErrorIs(t, charger.StartChargepointCharging(nil), ocpp.ErrNotConnected)
+	assert.Equal(t, adapter.ConnStatusDown, charger.Connectivity().ConnStatus)
+	assert.Equal(t, adapter.PingResultFailed, charger.Ping().Status)
+
+	state, err := charger.ChargepointStateReport()
+	assert.NoError(t, err)
+	assert.Equal(t, chargepoint.StateUnknown, state)
+
+	sim := ocpphelper.NewSimulator(t, system.Addr(), "cp-1")
+
+	err = sim.Call("DataTransfer", map[string]string{"vendorId": "acme"}, nil)
+
+	callErr := &ocpp.CallError{}
+	assert.True(t, errors.As(err, &callErr))
+	assert.Equal(t, ocpp.ErrorCodeNotImplemented, callErr.Code)
+
+	err = sim.Call(ocpp.ActionStatusNotification, []int{1}, nil)
+	assert.True(t, errors.As(err, &callErr))
+	assert.Equal(t, ocpp.ErrorCodeFormationViolation, callErr.Code)
+
+	sim.Respond(ocpp.ActionRemoteStartTransaction, &ocpp.RemoteStartTransactionResponse{Status: ocpp.RemoteStatusRejected})
+	assert.Error(t, system.Charger("cp-1").StartChargepointCharging(nil))
+
+	assert.Len(t, system.Chargers(), 2)
+
+	assert.NoError(t, system.Stop())
+	assert.Eventually(t, func() bool {
+		return !system.Charger("cp-1").Connected()
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,624 @@
+package ocpp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/adapter/service/chargepoint"
+	"github.com/futurehomeno/cliffhanger/adapter/service/numericmeter"
+)
+
+const (
+	// ConnectorID is an ID of the single connector exposed by each charger.
+	ConnectorID = 1
+
+	// Constants defining IDs of charging profiles set by the central system.
+	maxCurrentProfileID     = 1
+	defaultCurrentProfileID = 2
+	offeredCurrentProfileID = 3
+)
+
+// ErrNotConnected is returned when a command is sent to a charger which is not connected.
+var ErrNotConnected = errors.New("ocpp: charger is not connected")
+
+// Charger represents a charger connected to the central system.
+// It implements controllers of the chargepoint service and reporters of the electricity meter service for its single connector.
+type Charger struct {
+	id     string
+	system *centralSystem
+
+	lock          sync.RWMutex
+	conn          *Connection
+	boot          *BootNotificationRequest
+	lastSeen      time.Time
+	status        ChargePointStatus
+	transactionID *int
+	user          string
+	meterStart    float64
+	energy        float64
+	power         float64
+	currents      [3]float64
+	startedAt     time.Time
+	finishedAt    time.Time
+	lastSession   float64
+	maxCurrent    int
+	offered       int
+}
+
+// newCharger creates new instance of a disconnected charger.
+func newCharger(id string, system *centralSystem) *Charger {
+	return &Charger{
+		id:         id,
+		system:     system,
+		maxCurrent: system.cfg.MaxCurrent,
+	}
+}
+
+// ID returns the charge point identity used by the charger to connect.
+func (c *Charger) ID() string {
+	return c.id
+}
+
+// BootInfo returns the information sent by the charger in its last boot notification or nil if the charger has not booted yet.
+func (c *Charger) BootInfo() *BootNotificationRequest {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.boot == nil {
+		return nil
+	}
+
+	boot := *c.boot
+
+	return &boot
+}
+
+// Connected returns true if the charger is connected.
+func (c *Charger) Connected() bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.conn != nil
+}
+
+// LastSeen returns the time of the last call received from the charger.
+func (c *Charger) LastSeen() time.Time {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.lastSeen
+}
+
+// Status returns the last status of the connector reported by the charger.
+func (c *Charger) Status() ChargePointStatus {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.status
+}
+
+// StartChargepointCharging requests the charger to start a transaction using the remote ID tag.
+func (c *Charger) StartChargepointCharging(settings *chargepoint.ChargingSettings) error {
+	connectorID := ConnectorID
+	request := &RemoteStartTransactionRequest{
+		ConnectorID: &connectorID,
+		IDTag:       c.system.cfg.RemoteIDTag,
+	}
+	response := &RemoteStartTransactionResponse{}
+
+	if err := c.call(ActionRemoteStartTransaction, request, response); err != nil {
+		return err
+	}
+
+	if response.Status != RemoteStatusAccepted {
+		return fmt.Errorf("ocpp: charger %s rejected remote start of transaction", c.id)
+	}
+
+	if settings != nil && settings.User != "" {
+		c.lock.Lock()
+		c.user = settings.User
+		c.lock.Unlock()
+	}
+
+	return nil
+}
+
+// StopChargepointCharging requests the charger to stop the ongoing transaction.
+func (c *Charger) StopChargepointCharging() error {
+	c.lock.RLock()
+	transactionID := c.transactionID
+	c.lock.RUnlock()
+
+	if transactionID == nil {
+		return fmt.Errorf("ocpp: charger %s has no ongoing transaction", c.id)
+	}
+
+	request := &RemoteStopTransactionRequest{TransactionID: *transactionID}
+	response := &RemoteStopTransactionResponse{}
+
+	if err := c.call(ActionRemoteStopTransaction, request, response); err != nil {
+		return err
+	}
+
+	if response.Status != RemoteStatusAccepted {
+		return fmt.Errorf("ocpp: charger %s rejected remote stop of transaction %d", c.id, *transactionID)
+	}
+
+	return nil
+}
+
+// ChargepointCurrentSessionReport returns energy charged during the ongoing or the last transaction.
+func (c *Charger) ChargepointCurrentSessionReport() (*chargepoint.SessionReport, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	report := &chargepoint.SessionReport{
+		PreviousSessionEnergy: c.lastSession,
+		StartedAt:             c.startedAt,
+		FinishedAt:            c.finishedAt,
+		OfferedCurrent:        c.offered,
+		User:                  c.user,
+	}
+
+	if c.transactionID != nil {
+		report.SessionEnergy = max(c.energy-c.meterStart, 0) / 1000
+	}
+
+	return report, nil
+}
+
+// ChargepointStateReport returns the state of the chargepoint mapped from the last status of the connector.
+func (c *Charger) ChargepointStateReport() (chargepoint.State, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	switch c.status {
+	case StatusAvailable:
+		return chargepoint.StateDisconnected, nil
+	case StatusPreparing:
+		return chargepoint.StateRequesting, nil
+	case StatusCharging:
+		return chargepoint.StateCharging, nil
+	case StatusSuspendedEVSE:
+		return chargepoint.StateSuspendedByEVSE, nil
+	case StatusSuspendedEV:
+		return chargepoint.StateSuspendedByEV, nil
+	case StatusFinishing:
+		return chargepoint.StateFinished, nil
+	case StatusReserved:
+		return chargepoint.StateReserved, nil
+	case StatusUnavailable:
+		return chargepoint.StateUnavailable, nil
+	case StatusFaulted:
+		return chargepoint.StateError, nil
+	default:
+		return chargepoint.StateUnknown, nil
+	}
+}
+
+// SetChargepointMaxCurrent sets a charge point wide current limit using a charge point max profile.
+func (c *Charger) SetChargepointMaxCurrent(current int) error {
+	profile := c.profile(maxCurrentProfileID, ChargingProfilePurposeChargePointMaxProfile, current)
+
+	if err := c.setChargingProfile(0, profile); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	c.maxCurrent = current
+	c.lock.Unlock()
+
+	return nil
+}
+
+// ChargepointMaxCurrentReport returns the last charge point wide current limit.
+func (c *Charger) ChargepointMaxCurrentReport() (int, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.maxCurrent, nil
+}
+
+// SetChargepointOfferedCurrent limits current of the ongoing transaction or, if there is none, of the next one.
+func (c *Charger) SetChargepointOfferedCurrent(current int) error {
+	c.lock.RLock()
+	transactionID := c.transactionID
+	c.lock.RUnlock()
+
+	profile := c.profile(defaultCurrentProfileID, ChargingProfilePurposeTxDefaultProfile, current)
+
+	if transactionID != nil {
+		profile = c.profile(offeredCurrentProfileID, ChargingProfilePurposeTxProfile, current)
+		profile.TransactionID = transactionID
+	}
+
+	if err := c.setChargingProfile(ConnectorID, profile); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	c.offered = current
+	c.lock.Unlock()
+
+	return nil
+}
+
+// ChangeConfiguration changes a configuration key of the charger.
+func (c *Charger) ChangeConfiguration(key, value string) error {
+	request := &ChangeConfigurationRequest{Key: key, Value: value}
+	response := &ChangeConfigurationResponse{}
+
+	if err := c.call(ActionChangeConfiguration, request, response); err != nil {
+		return err
+	}
+
+	switch response.Status {
+	case ConfigurationStatusAccepted, ConfigurationStatusRebootRequired:
+		return nil
+	default:
+		return fmt.Errorf("ocpp: charger %s did not accept configuration %s: %s", c.id, key, response.Status)
+	}
+}
+
+// MeterReport returns the last active power or energy register reported by the charger.
+func (c *Charger) MeterReport(unit numericmeter.Unit) (float64, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	switch unit {
+	case numericmeter.UnitW:
+		return c.power, nil
+	case numericmeter.UnitKWh:
+		return c.energy / 1000, nil
+	default:
+		return 0, fmt.Errorf("ocpp: unsupported unit: %s", unit)
+	}
+}
+
+// MeterExtendedReport returns the last phase currents, active power and energy register reported by the charger.
+func (c *Charger) MeterExtendedReport(values numericmeter.Values) (numericmeter.ValuesReport, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	report := make(numericmeter.ValuesReport)
+
+	for _, value := range values {
+		switch value {
+		case numericmeter.ValueCurrentPhase1:
+			report[value] = c.currents[0]
+		case numericmeter.ValueCurrentPhase2:
+			report[value] = c.currents[1]
+		case numericmeter.ValueCurrentPhase3:
+			report[value] = c.currents[2]
+		case numericmeter.ValuePowerImport:
+			report[value] = c.power
+		case numericmeter.ValueEnergyImport:
+			report[value] = c.energy / 1000
+		}
+	}
+
+	return report, nil
+}
+
+// Connectivity returns connectivity details of the charger.
+func (c *Charger) Connectivity() *adapter.ConnectivityDetails {
+	if !c.Connected() {
+		return &adapter.ConnectivityDetails{
+			ConnStatus:       adapter.ConnStatusDown,
+			Operationability: []adapter.OperationabilityT{adapter.OperationabilityNotReady},
+			ConnQuality:      adapter.ConnQualityUndefined,
+			ConnType:         adapter.ConnTypeDirect,
+		}
+	}
+
+	return &adapter.ConnectivityDetails{
+		ConnStatus:       adapter.ConnStatusUp,
+		Operationability: []adapter.OperationabilityT{adapter.OperationabilityReady},
+		ConnQuality:      adapter.ConnQualityUndefined,
+		ConnType:         adapter.ConnTypeDirect,
+	}
+}
+
+// Ping returns result of a ping, which succeeds if the charger is connected.
+func (c *Charger) Ping() *adapter.PingDetails {
+	if !c.Connected() {
+		return &adapter.PingDetails{Status: adapter.PingResultFailed}
+	}
+
+	return &adapter.PingDetails{Status: adapter.PingResultSuccess}
+}
+
+// HandleCall handles a call received from the charger.
+func (c *Charger) HandleCall(action string, payload json.RawMessage) (any, error) {
+	c.lock.Lock()
+	c.lastSeen = c.system.now()
+	c.lock.Unlock()
+
+	switch action {
+	case ActionBootNotification:
+		return handle(payload, c.handleBootNotification)
+	case ActionHeartbeat:
+		return handle(payload, c.handleHeartbeat)
+	case ActionAuthorize:
+		return handle(payload, c.handleAuthorize)
+	case ActionStatusNotification:
+		return handle(payload, c.handleStatusNotification)
+	case ActionMeterValues:
+		return handle(payload, c.handleMeterValues)
+	case ActionStartTransaction:
+		return handle(payload, c.handleStartTransaction)
+	case ActionStopTransaction:
+		return handle(payload, c.handleStopTransaction)
+	default:
+		return nil, NewCallError(ErrorCodeNotImplemented, "action %s is not supported", action)
+	}
+}
+
+// handle decodes the payload of a call and passes it to the handler.
+func handle[Req any, Resp any](payload json.RawMessage, handler func(request *Req) (*Resp, error)) (any, error) {
+	request := new(Req)
+
+	if err := json.Unmarshal(payload, request); err != nil {
+		return nil, NewCallError(ErrorCodeFormationViolation, "failed to unmarshal payload: %s", err)
+	}
+
+	return handler(request)
+}
+
+func (c *Charger) handleBootNotification(request *BootNotificationRequest) (*BootNotificationResponse, error) {
+	c.lock.Lock()
+	c.boot = request
+	c.lock.Unlock()
+
+	if c.system.cfg.OnBoot != nil {
+		go c.system.cfg.OnBoot(c)
+	}
+
+	return &BootNotificationResponse{
+		Status:      RegistrationStatusAccepted,
+		CurrentTime: c.system.now().UTC(),
+		Interval:    int(c.system.cfg.HeartbeatInterval.Seconds()),
+	}, nil
+}
+
+func (c *Charger) handleHeartbeat(_ *HeartbeatRequest) (*HeartbeatResponse, error) {
+	return &HeartbeatResponse{CurrentTime: c.system.now().UTC()}, nil
+}
+
+func (c *Charger) handleAuthorize(request *AuthorizeRequest) (*AuthorizeResponse, error) {
+	info, _ := c.authorize(request.IDTag)
+
+	return &AuthorizeResponse{IDTagInfo: info}, nil
+}
+
+func (c *Charger) handleStatusNotification(request *StatusNotificationRequest) (*StatusNotificationResponse, error) {
+	// Status of the whole charge point is reported for connector 0, which does not affect the state of the single connector.
+	if request.ConnectorID != ConnectorID {
+		return &StatusNotificationResponse{}, nil
+	}
+
+	c.lock.Lock()
+	c.status = request.Status
+	c.lock.Unlock()
+
+	return &StatusNotificationResponse{}, nil
+}
+
+func (c *Charger) handleMeterValues(request *MeterValuesRequest) (*MeterValuesResponse, error) {
+	if request.ConnectorID != ConnectorID {
+		return &MeterValuesResponse{}, nil
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, value := range request.MeterValue {
+		c.recordMeterValue(value)
+	}
+
+	// Transaction started before a restart of the central system is adopted, so it can be stopped remotely.
+	// Its session energy is counted from the first meter value received after the restart.
+	if request.TransactionID != nil && c.transactionID == nil {
+		log.Warnf("ocpp: charger %s reported meter values of unknown transaction %d, adopting it", c.id, *request.TransactionID)
+
+		transactionID := *request.TransactionID
+		c.transactionID = &transactionID
+		c.meterStart = c.energy
+		c.finishedAt = time.Time{}
+	}
+
+	return &MeterValuesResponse{}, nil
+}
+
+func (c *Charger) handleStartTransaction(request *StartTransactionRequest) (*StartTransactionResponse, error) {
+	info, user := c.authorize(request.IDTag)
+	transactionID := c.system.nextTransactionID()
+
+	if info.Status != AuthorizationStatusAccepted {
+		return &StartTransactionResponse{IDTagInfo: info, TransactionID: transactionID}, nil
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.transactionID = &transactionID
+	c.meterStart = float64(request.MeterStart)
+	c.energy = max(c.energy, c.meterStart)
+	c.startedAt = request.Timestamp
+	c.finishedAt = time.Time{}
+
+	if user != "" {
+		c.user = user
+	}
+
+	return &StartTransactionResponse{IDTagInfo: info, TransactionID: transactionID}, nil
+}
+
+func (c *Charger) handleStopTransaction(request *StopTransactionRequest) (*StopTransactionResponse, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, value := range request.TransactionData {
+		c.recordMeterValue(value)
+	}
+
+	response := &StopTransactionResponse{IDTagInfo: &IDTagInfo{Status: AuthorizationStatusAccepted}}
+
+	switch {
+	case c.transactionID == nil:
+		// Transaction was started before a restart of the central system and its start is unknown, so its energy is not accounted.
+		log.Warnf("ocpp: charger %s stopped unknown transaction %d", c.id, request.TransactionID)
+	case *c.transactionID != request.TransactionID:
+		log.Warnf("ocpp: charger %s stopped transaction %d while transaction %d is ongoing", c.id, request.TransactionID, *c.transactionID)
+
+		return response, nil
+	default:
+		c.lastSession = max(float64(request.MeterStop)-c.meterStart, 0) / 1000
+	}
+
+	c.energy = max(c.energy, float64(request.MeterStop))
+	c.finishedAt = request.Timestamp
+	c.transactionID = nil
+	c.user = ""
+	c.power = 0
+	c.currents = [3]float64{}
+
+	return response, nil
+}
+
+// authorize authorizes the ID tag and returns the name of its user, if known.
+// The remote ID tag is always accepted, as are all tags if no authorizer is configured.
+func (c *Charger) authorize(idTag string) (IDTagInfo, string) {
+	if c.system.cfg.Authorizer == nil || idTag == c.system.cfg.RemoteIDTag {
+		return IDTagInfo{Status: AuthorizationStatusAccepted}, ""
+	}
+
+	tag, ok := c.system.cfg.Authorizer.Authorize(idTag, c.system.now())
+	if !ok {
+		if tag != nil {
+			return IDTagInfo{Status: AuthorizationStatusExpired}, ""
+		}
+
+		return IDTagInfo{Status: AuthorizationStatusInvalid}, ""
+	}
+
+	return IDTagInfo{Status: AuthorizationStatusAccepted}, tag.Name
+}
+
+// recordMeterValue records supported measurands of the meter value. Lock must be held by the caller.
+func (c *Charger) recordMeterValue(value *MeterValue) {
+	for _, sample := range value.SampledValue {
+		v, err := strconv.ParseFloat(sample.Value, 64)
+		if err != nil {
+			continue
+		}
+
+		switch sample.Measurand {
+		case "", MeasurandEnergyActiveImportRegister:
+			if sample.Unit == UnitKWh {
+				v *= 1000
+			}
+
+			c.energy = v
+		case MeasurandPowerActiveImport:
+			if sample.Unit == UnitKW {
+				v *= 1000
+			}
+
+			c.power = v
+		case MeasurandCurrentImport:
+			switch {
+			case strings.HasPrefix(sample.Phase, PhaseL2):
+				c.currents[1] = v
+			case strings.HasPrefix(sample.Phase, PhaseL3):
+				c.currents[2] = v
+			default:
+				c.currents[0] = v
+			}
+		case MeasurandCurrentOffered:
+			c.offered = int(v)
+		}
+	}
+}
+
+// profile creates an absolute charging profile limiting current to the provided value.
+func (c *Charger) profile(id int, purpose string, current int) *ChargingProfile {
+	start := c.system.now().UTC()
+
+	return &ChargingProfile{
+		ChargingProfileID:      id,
+		ChargingProfilePurpose: purpose,
+		ChargingProfileKind:    ChargingProfileKindAbsolute,
+		ChargingSchedule: ChargingSchedule{
+			StartSchedule:    &start,
+			ChargingRateUnit: ChargingRateUnitA,
+			ChargingSchedulePeriod: []*ChargingSchedulePeriod{
+				{StartPeriod: 0, Limit: float64(current)},
+			},
+		},
+	}
+}
+
+// setChargingProfile sets the charging profile of the connector.
+func (c *Charger) setChargingProfile(connectorID int, profile *ChargingProfile) error {
+	request := &SetChargingProfileRequest{ConnectorID: connectorID, CsChargingProfiles: profile}
+	response := &SetChargingProfileResponse{}
+
+	if err := c.call(ActionSetChargingProfile, request, response); err != nil {
+		return err
+	}
+
+	if response.Status != ChargingProfileStatusAccepted {
+		return fmt.Errorf("ocpp: charger %s did not accept %s: %s", c.id, profile.ChargingProfilePurpose, response.Status)
+	}
+
+	return nil
+}
+
+// call sends a call to the charger.
+func (c *Charger) call(action string, request, response any) error {
+	c.lock.RLock()
+	conn := c.conn
+	c.lock.RUnlock()
+
+	if conn == nil {
+		return ErrNotConnected
+	}
+
+	if err := conn.Call(action, request, response); err != nil {
+		return fmt.Errorf("ocpp: failed to call %s on charger %s: %w", action, c.id, err)
+	}
+
+	return nil
+}
+
+// attach replaces the connection of the charger, closing the previous one.
+func (c *Charger) attach(conn *Connection) {
+	c.lock.Lock()
+	previous := c.conn
+	c.conn = conn
+	c.lastSeen = c.system.now()
+	c.lock.Unlock()
+
+	if previous != nil {
+		_ = previous.Close()
+	}
+}
+
+// detach removes the connection of the charger, unless it was already replaced.
+func (c *Charger) detach(conn *Connection) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.conn == conn {
+		c.conn = nil
+	}
+}
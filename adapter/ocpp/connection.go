@@ -0,0 +1,222 @@
+package ocpp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Subprotocol is a WebSocket subprotocol of OCPP 1.6J.
+	Subprotocol = "ocpp1.6"
+
+	// defaultCallTimeout is a default period after which an unanswered call fails.
+	defaultCallTimeout = 30 * time.Second
+)
+
+// ErrConnectionClosed is returned when a call is made over or interrupted by a closed connection.
+var ErrConnectionClosed = errors.New("ocpp: connection is closed")
+
+// Handler is an interface representing a handler of calls received over a connection.
+type Handler interface {
+	// HandleCall handles the call of the provided action and returns the payload of its result.
+	// Returned CallError determines the error code sent back, other errors are sent as internal errors.
+	HandleCall(action string, payload json.RawMessage) (any, error)
+}
+
+// HandlerFn is an adapter allowing usage of anonymous function as a service meeting handler interface.
+type HandlerFn func(action string, payload json.RawMessage) (any, error)
+
+// HandleCall handles the call of the provided action and returns the payload of its result.
+func (f HandlerFn) HandleCall(action string, payload json.RawMessage) (any, error) {
+	return f(action, payload)
+}
+
+// Connection is a bidirectional OCPP-J RPC connection over WebSocket, used by both the central system and charge points.
+type Connection struct {
+	conn    *websocket.Conn
+	handler Handler
+	timeout time.Duration
+
+	writeLock sync.Mutex
+	lock      sync.Mutex
+	pending   map[string]chan *Message
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewConnection creates new instance of a connection. Incoming messages are not processed until the connection is started.
+func NewConnection(conn *websocket.Conn, handler Handler, timeout time.Duration) *Connection {
+	if timeout <= 0 {
+		timeout = defaultCallTimeout
+	}
+
+	c := &Connection{
+		conn:    conn,
+		handler: handler,
+		timeout: timeout,
+		pending: make(map[string]chan *Message),
+		done:    make(chan struct{}),
+	}
+
+	return c
+}
+
+// Start starts processing of incoming messages until the connection is closed.
+// Incoming calls are handled concurrently, as each side awaits a result before sending another call.
+func (c *Connection) Start() {
+	go c.read()
+}
+
+// Call sends a call of the provided action and decodes its result into the response.
+func (c *Connection) Call(action string, request, response any) error {
+	msg, err := NewCall(action, request)
+	if err != nil {
+		return err
+	}
+
+	result := make(chan *Message, 1)
+
+	c.lock.Lock()
+	c.pending[msg.ID] = result
+	c.lock.Unlock()
+
+	defer func() {
+		c.lock.Lock()
+		delete(c.pending, msg.ID)
+		c.lock.Unlock()
+	}()
+
+	if err = c.write(msg); err != nil {
+		return fmt.Errorf("ocpp: failed to send %s call: %w", action, err)
+	}
+
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+
+	select {
+	case reply := <-result:
+		if reply.Type == MessageTypeCallError {
+			return reply.Error
+		}
+
+		if response == nil {
+			return nil
+		}
+
+		if err = json.Unmarshal(reply.Payload, response); err != nil {
+			return fmt.Errorf("ocpp: failed to unmarshal result of %s call: %w", action, err)
+		}
+
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("ocpp: %s call timed out after %s", action, c.timeout)
+	case <-c.done:
+		return ErrConnectionClosed
+	}
+}
+
+// Done returns a channel closed when the connection is closed.
+func (c *Connection) Done() <-chan struct{} {
+	return c.done
+}
+
+// Close closes the connection and interrupts all pending calls.
+func (c *Connection) Close() error {
+	var err error
+
+	c.closeOnce.Do(func() {
+		close(c.done)
+
+		err = c.conn.Close()
+	})
+
+	return err
+}
+
+// read processes incoming messages until the connection is closed.
+func (c *Connection) read() {
+	defer func() { _ = c.Close() }()
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			select {
+			case <-c.done:
+			default:
+				log.WithError(err).Debug("ocpp: connection closed")
+			}
+
+			return
+		}
+
+		msg := &Message{}
+
+		if err = json.Unmarshal(data, msg); err != nil {
+			log.WithError(err).Warn("ocpp: received malformed message")
+
+			continue
+		}
+
+		switch msg.Type {
+		case MessageTypeCall:
+			go c.handle(msg)
+		case MessageTypeCallResult, MessageTypeCallError:
+			c.lock.Lock()
+			result, ok := c.pending[msg.ID]
+			c.lock.Unlock()
+
+			if !ok {
+				log.Warnf("ocpp: received result of unknown call %s", msg.ID)
+
+				continue
+			}
+
+			select {
+			case result <- msg:
+			default:
+				log.Warnf("ocpp: received duplicated result of call %s", msg.ID)
+			}
+		}
+	}
+}
+
+// handle handles the incoming call and sends back its result.
+func (c *Connection) handle(msg *Message) {
+	reply, err := c.result(msg)
+	if err != nil {
+		reply = NewCallErrorResult(msg.ID, err)
+	}
+
+	if err = c.write(reply); err != nil {
+		log.WithError(err).Errorf("ocpp: failed to send result of %s call", msg.Action)
+	}
+}
+
+// result handles the incoming call and creates a message with its result.
+func (c *Connection) result(msg *Message) (*Message, error) {
+	payload, err := c.handler.HandleCall(msg.Action, msg.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCallResult(msg.ID, payload)
+}
+
+// write sends the message, serializing concurrent writers.
+func (c *Connection) write(msg *Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+
+	return c.conn.WriteMessage(websocket.TextMessage, data)
+}
@@ -0,0 +1,279 @@
+package ocpp
+
+import (
+	"time"
+)
+
+// Constants defining supported OCPP 1.6 actions.
+const (
+	ActionAuthorize              = "Authorize"
+	ActionBootNotification       = "BootNotification"
+	ActionHeartbeat              = "Heartbeat"
+	ActionStatusNotification     = "StatusNotification"
+	ActionMeterValues            = "MeterValues"
+	ActionStartTransaction       = "StartTransaction"
+	ActionStopTransaction        = "StopTransaction"
+	ActionRemoteStartTransaction = "RemoteStartTransaction"
+	ActionRemoteStopTransaction  = "RemoteStopTransaction"
+	ActionSetChargingProfile     = "SetChargingProfile"
+	ActionChangeConfiguration    = "ChangeConfiguration"
+)
+
+// Constants defining statuses of a connector.
+const (
+	StatusAvailable     ChargePointStatus = "Available"
+	StatusPreparing     ChargePointStatus = "Preparing"
+	StatusCharging      ChargePointStatus = "Charging"
+	StatusSuspendedEVSE ChargePointStatus = "SuspendedEVSE"
+	StatusSuspendedEV   ChargePointStatus = "SuspendedEV"
+	StatusFinishing     ChargePointStatus = "Finishing"
+	StatusReserved      ChargePointStatus = "Reserved"
+	StatusUnavailable   ChargePointStatus = "Unavailable"
+	StatusFaulted       ChargePointStatus = "Faulted"
+)
+
+// Constants defining statuses used in responses.
+const (
+	RegistrationStatusAccepted = "Accepted"
+	RegistrationStatusPending  = "Pending"
+	RegistrationStatusRejected = "Rejected"
+
+	AuthorizationStatusAccepted     = "Accepted"
+	AuthorizationStatusBlocked      = "Blocked"
+	AuthorizationStatusExpired      = "Expired"
+	AuthorizationStatusInvalid      = "Invalid"
+	AuthorizationStatusConcurrentTx = "ConcurrentTx"
+
+	RemoteStatusAccepted = "Accepted"
+	RemoteStatusRejected = "Rejected"
+
+	ChargingProfileStatusAccepted     = "Accepted"
+	ChargingProfileStatusRejected     = "Rejected"
+	ChargingProfileStatusNotSupported = "NotSupported"
+
+	ConfigurationStatusAccepted       = "Accepted"
+	ConfigurationStatusRejected       = "Rejected"
+	ConfigurationStatusRebootRequired = "RebootRequired"
+	ConfigurationStatusNotSupported   = "NotSupported"
+)
+
+// Constants defining measurands, phases and units of sampled values.
+const (
+	MeasurandEnergyActiveImportRegister = "Energy.Active.Import.Register"
+	MeasurandPowerActiveImport          = "Power.Active.Import"
+	MeasurandCurrentImport              = "Current.Import"
+	MeasurandCurrentOffered             = "Current.Offered"
+
+	PhaseL1 = "L1"
+	PhaseL2 = "L2"
+	PhaseL3 = "L3"
+
+	UnitWh  = "Wh"
+	UnitKWh = "kWh"
+	UnitW   = "W"
+	UnitKW  = "kW"
+	UnitA   = "A"
+)
+
+// Constants defining charging profiles.
+const (
+	ChargingProfilePurposeChargePointMaxProfile = "ChargePointMaxProfile"
+	ChargingProfilePurposeTxDefaultProfile      = "TxDefaultProfile"
+	ChargingProfilePurposeTxProfile             = "TxProfile"
+
+	ChargingProfileKindAbsolute  = "Absolute"
+	ChargingProfileKindRecurring = "Recurring"
+	ChargingProfileKindRelative  = "Relative"
+
+	ChargingRateUnitA = "A"
+	ChargingRateUnitW = "W"
+)
+
+// ChargePointStatus represents a status of a connector reported in a status notification.
+type ChargePointStatus string
+
+// IDTagInfo represents a result of the authorization of an ID tag.
+type IDTagInfo struct {
+	Status      string     `json:"status"`
+	ExpiryDate  *time.Time `json:"expiryDate,omitempty"`
+	ParentIDTag string     `json:"parentIdTag,omitempty"`
+}
+
+// AuthorizeRequest represents a payload of the Authorize call.
+type AuthorizeRequest struct {
+	IDTag string `json:"idTag"`
+}
+
+// AuthorizeResponse represents a payload of the Authorize call result.
+type AuthorizeResponse struct {
+	IDTagInfo IDTagInfo `json:"idTagInfo"`
+}
+
+// BootNotificationRequest represents a payload of the BootNotification call.
+type BootNotificationRequest struct {
+	ChargePointVendor       string `json:"chargePointVendor"`
+	ChargePointModel        string `json:"chargePointModel"`
+	ChargePointSerialNumber string `json:"chargePointSerialNumber,omitempty"`
+	ChargeBoxSerialNumber   string `json:"chargeBoxSerialNumber,omitempty"`
+	FirmwareVersion         string `json:"firmwareVersion,omitempty"`
+	Iccid                   string `json:"iccid,omitempty"`
+	Imsi                    string `json:"imsi,omitempty"`
+	MeterType               string `json:"meterType,omitempty"`
+	MeterSerialNumber       string `json:"meterSerialNumber,omitempty"`
+}
+
+// BootNotificationResponse represents a payload of the BootNotification call result.
+type BootNotificationResponse struct {
+	Status      string    `json:"status"`
+	CurrentTime time.Time `json:"currentTime"`
+	Interval    int       `json:"interval"`
+}
+
+// HeartbeatRequest represents a payload of the Heartbeat call.
+type HeartbeatRequest struct{}
+
+// HeartbeatResponse represents a payload of the Heartbeat call result.
+type HeartbeatResponse struct {
+	CurrentTime time.Time `json:"currentTime"`
+}
+
+// StatusNotificationRequest represents a payload of the StatusNotification call.
+type StatusNotificationRequest struct {
+	ConnectorID     int               `json:"connectorId"`
+	ErrorCode       string            `json:"errorCode"`
+	Status          ChargePointStatus `json:"status"`
+	Info            string            `json:"info,omitempty"`
+	Timestamp       *time.Time        `json:"timestamp,omitempty"`
+	VendorID        string            `json:"vendorId,omitempty"`
+	VendorErrorCode string            `json:"vendorErrorCode,omitempty"`
+}
+
+// StatusNotificationResponse represents a payload of the StatusNotification call result.
+type StatusNotificationResponse struct{}
+
+// SampledValue represents a single measurement.
+type SampledValue struct {
+	Value     string `json:"value"`
+	Context   string `json:"context,omitempty"`
+	Format    string `json:"format,omitempty"`
+	Measurand string `json:"measurand,omitempty"`
+	Phase     string `json:"phase,omitempty"`
+	Location  string `json:"location,omitempty"`
+	Unit      string `json:"unit,omitempty"`
+}
+
+// MeterValue represents a collection of measurements taken at the same time.
+type MeterValue struct {
+	Timestamp    time.Time       `json:"timestamp"`
+	SampledValue []*SampledValue `json:"sampledValue"`
+}
+
+// MeterValuesRequest represents a payload of the MeterValues call.
+type MeterValuesRequest struct {
+	ConnectorID   int           `json:"connectorId"`
+	TransactionID *int          `json:"transactionId,omitempty"`
+	MeterValue    []*MeterValue `json:"meterValue"`
+}
+
+// MeterValuesResponse represents a payload of the MeterValues call result.
+type MeterValuesResponse struct{}
+
+// StartTransactionRequest represents a payload of the StartTransaction call.
+type StartTransactionRequest struct {
+	ConnectorID   int       `json:"connectorId"`
+	IDTag         string    `json:"idTag"`
+	MeterStart    int       `json:"meterStart"`
+	ReservationID *int      `json:"reservationId,omitempty"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// StartTransactionResponse represents a payload of the StartTransaction call result.
+type StartTransactionResponse struct {
+	IDTagInfo     IDTagInfo `json:"idTagInfo"`
+	TransactionID int       `json:"transactionId"`
+}
+
+// StopTransactionRequest represents a payload of the StopTransaction call.
+type StopTransactionRequest struct {
+	IDTag           string        `json:"idTag,omitempty"`
+	MeterStop       int           `json:"meterStop"`
+	Timestamp       time.Time     `json:"timestamp"`
+	TransactionID   int           `json:"transactionId"`
+	Reason          string        `json:"reason,omitempty"`
+	TransactionData []*MeterValue `json:"transactionData,omitempty"`
+}
+
+// StopTransactionResponse represents a payload of the StopTransaction call result.
+type StopTransactionResponse struct {
+	IDTagInfo *IDTagInfo `json:"idTagInfo,omitempty"`
+}
+
+// RemoteStartTransactionRequest represents a payload of the RemoteStartTransaction call.
+type RemoteStartTransactionRequest struct {
+	ConnectorID     *int             `json:"connectorId,omitempty"`
+	IDTag           string           `json:"idTag"`
+	ChargingProfile *ChargingProfile `json:"chargingProfile,omitempty"`
+}
+
+// RemoteStartTransactionResponse represents a payload of the RemoteStartTransaction call result.
+type RemoteStartTransactionResponse struct {
+	Status string `json:"status"`
+}
+
+// RemoteStopTransactionRequest represents a payload of the RemoteStopTransaction call.
+type RemoteStopTransactionRequest struct {
+	TransactionID int `json:"transactionId"`
+}
+
+// RemoteStopTransactionResponse represents a payload of the RemoteStopTransaction call result.
+type RemoteStopTransactionResponse struct {
+	Status string `json:"status"`
+}
+
+// ChargingSchedulePeriod represents a single period of a charging schedule.
+type ChargingSchedulePeriod struct {
+	StartPeriod  int     `json:"startPeriod"`
+	Limit        float64 `json:"limit"`
+	NumberPhases *int    `json:"numberPhases,omitempty"`
+}
+
+// ChargingSchedule represents a charging schedule of a charging profile.
+type ChargingSchedule struct {
+	Duration               *int                      `json:"duration,omitempty"`
+	StartSchedule          *time.Time                `json:"startSchedule,omitempty"`
+	ChargingRateUnit       string                    `json:"chargingRateUnit"`
+	ChargingSchedulePeriod []*ChargingSchedulePeriod `json:"chargingSchedulePeriod"`
+	MinChargingRate        *float64                  `json:"minChargingRate,omitempty"`
+}
+
+// ChargingProfile represents a charging profile limiting the charging current.
+type ChargingProfile struct {
+	ChargingProfileID      int              `json:"chargingProfileId"`
+	TransactionID          *int             `json:"transactionId,omitempty"`
+	StackLevel             int              `json:"stackLevel"`
+	ChargingProfilePurpose string           `json:"chargingProfilePurpose"`
+	ChargingProfileKind    string           `json:"chargingProfileKind"`
+	ChargingSchedule       ChargingSchedule `json:"chargingSchedule"`
+}
+
+// SetChargingProfileRequest represents a payload of the SetChargingProfile call.
+type SetChargingProfileRequest struct {
+	ConnectorID        int              `json:"connectorId"`
+	CsChargingProfiles *ChargingProfile `json:"csChargingProfiles"`
+}
+
+// SetChargingProfileResponse represents a payload of the SetChargingProfile call result.
+type SetChargingProfileResponse struct {
+	Status string `json:"status"`
+}
+
+// ChangeConfigurationRequest represents a payload of the ChangeConfiguration call.
+type ChangeConfigurationRequest struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// ChangeConfigurationResponse represents a payload of the ChangeConfiguration call result.
+type ChangeConfigurationResponse struct {
+	Status string `json:"status"`
+}
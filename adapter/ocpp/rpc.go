@@ -0,0 +1,181 @@
+package ocpp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Constants defining OCPP-J message types.
+const (
+	MessageTypeCall       MessageType = 2
+	MessageTypeCallResult MessageType = 3
+	MessageTypeCallError  MessageType = 4
+)
+
+// Constants defining OCPP-J error codes.
+const (
+	ErrorCodeNotImplemented     ErrorCode = "NotImplemented"
+	ErrorCodeNotSupported       ErrorCode = "NotSupported"
+	ErrorCodeInternalError      ErrorCode = "InternalError"
+	ErrorCodeProtocolError      ErrorCode = "ProtocolError"
+	ErrorCodeFormationViolation ErrorCode = "FormationViolation"
+	ErrorCodeGenericError       ErrorCode = "GenericError"
+)
+
+// MessageType represents a type of OCPP-J message.
+type MessageType int
+
+// ErrorCode represents an error code of OCPP-J call error.
+type ErrorCode string
+
+// CallError represents an error returned by the other side of the connection in response to a call.
+// Handlers may return it to control the error code sent back.
+type CallError struct {
+	Code        ErrorCode
+	Description string
+}
+
+// Error returns a string representation of the error.
+func (e *CallError) Error() string {
+	return fmt.Sprintf("ocpp: call error %s: %s", e.Code, e.Description)
+}
+
+// NewCallError creates new instance of a call error.
+func NewCallError(code ErrorCode, format string, args ...any) *CallError {
+	return &CallError{
+		Code:        code,
+		Description: fmt.Sprintf(format, args...),
+	}
+}
+
+// Message represents a single OCPP-J frame.
+type Message struct {
+	Type    MessageType
+	ID      string
+	Action  string
+	Payload json.RawMessage
+	Error   *CallError
+}
+
+// NewCall creates a new call message with a random ID.
+func NewCall(action string, payload any) (*Message, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("ocpp: failed to marshal payload of %s call: %w", action, err)
+	}
+
+	return &Message{
+		Type:    MessageTypeCall,
+		ID:      uuid.NewString(),
+		Action:  action,
+		Payload: raw,
+	}, nil
+}
+
+// NewCallResult creates a new message with a result of the call of the provided ID.
+func NewCallResult(id string, payload any) (*Message, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("ocpp: failed to marshal payload of call result: %w", err)
+	}
+
+	return &Message{
+		Type:    MessageTypeCallResult,
+		ID:      id,
+		Payload: raw,
+	}, nil
+}
+
+// NewCallErrorResult creates a new message with an error of the call of the provided ID.
+func NewCallErrorResult(id string, err error) *Message {
+	callErr := &CallError{}
+	if !errors.As(err, &callErr) {
+		callErr = NewCallError(ErrorCodeInternalError, "%s", err.Error())
+	}
+
+	return &Message{
+		Type:  MessageTypeCallError,
+		ID:    id,
+		Error: callErr,
+	}
+}
+
+// MarshalJSON encodes the message as an OCPP-J array.
+func (m *Message) MarshalJSON() ([]byte, error) {
+	payload := m.Payload
+	if payload == nil {
+		payload = json.RawMessage("{}")
+	}
+
+	switch m.Type {
+	case MessageTypeCall:
+		return json.Marshal([]any{m.Type, m.ID, m.Action, payload})
+	case MessageTypeCallResult:
+		return json.Marshal([]any{m.Type, m.ID, payload})
+	case MessageTypeCallError:
+		return json.Marshal([]any{m.Type, m.ID, m.Error.Code, m.Error.Description, payload})
+	default:
+		return nil, fmt.Errorf("ocpp: unsupported message type: %d", m.Type)
+	}
+}
+
+// UnmarshalJSON decodes the message from an OCPP-J array.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var fields []json.RawMessage
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("ocpp: message is not an array: %w", err)
+	}
+
+	if len(fields) < 3 {
+		return fmt.Errorf("ocpp: message has too few elements: %d", len(fields))
+	}
+
+	if err := json.Unmarshal(fields[0], &m.Type); err != nil {
+		return fmt.Errorf("ocpp: invalid message type: %w", err)
+	}
+
+	if err := json.Unmarshal(fields[1], &m.ID); err != nil {
+		return fmt.Errorf("ocpp: invalid message ID: %w", err)
+	}
+
+	switch m.Type {
+	case MessageTypeCall:
+		if len(fields) != 4 {
+			return fmt.Errorf("ocpp: call must have 4 elements, got %d", len(fields))
+		}
+
+		if err := json.Unmarshal(fields[2], &m.Action); err != nil {
+			return fmt.Errorf("ocpp: invalid call action: %w", err)
+		}
+
+		m.Payload = fields[3]
+	case MessageTypeCallResult:
+		m.Payload = fields[2]
+	case MessageTypeCallError:
+		if len(fields) < 4 {
+			return fmt.Errorf("ocpp: call error must have at least 4 elements, got %d", len(fields))
+		}
+
+		m.Error = &CallError{}
+
+		if err := json.Unmarshal(fields[2], &m.Error.Code); err != nil {
+			return fmt.Errorf("ocpp: invalid error code: %w", err)
+		}
+
+		if err := json.Unmarshal(fields[3], &m.Error.Description); err != nil {
+			return fmt.Errorf("ocpp: invalid error description: %w", err)
+		}
+
+		if len(fields) > 4 {
+			m.Payload = fields[4]
+		}
+	default:
+		return fmt.Errorf("ocpp: unsupported message type: %d", m.Type)
+	}
+
+	return nil
+}
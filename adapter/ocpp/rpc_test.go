@@ -0,0 +1,101 @@
+package ocpp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/futurehomeno/cliffhanger/adapter/ocpp"
+)
+
+func TestMessage(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name    string
+		raw     string
+		want    *ocpp.Message
+		wantErr bool
+	}{
+		{
+			name: "call",
+			raw:  `[2,"19223201","BootNotification",{"chargePointVendor":"Acme","chargePointModel":"Wallbox"}]`,
+			want: &ocpp.Message{
+				Type:    ocpp.MessageTypeCall,
+				ID:      "19223201",
+				Action:  ocpp.ActionBootNotification,
+				Payload: json.RawMessage(`{"chargePointVendor":"Acme","chargePointModel":"Wallbox"}`),
+			},
+		},
+		{
+			name: "call result",
+			raw:  `[3,"19223201",{"status":"Accepted"}]`,
+			want: &ocpp.Message{
+				Type:    ocpp.MessageTypeCallResult,
+				ID:      "19223201",
+				Payload: json.RawMessage(`{"status":"Accepted"}`),
+			},
+		},
+		{
+			name: "call error",
+			raw:  `[4,"19223201","NotImplemented","action is not supported",{}]`,
+			want: &ocpp.Message{
+				Type:    ocpp.MessageTypeCallError,
+				ID:      "19223201",
+				Payload: json.RawMessage(`{}`),
+				Error:   &ocpp.CallError{Code: ocpp.ErrorCodeNotImplemented, Description: "action is not supported"},
+			},
+		},
+		{
+			name:    "not an array",
+			raw:     `{"type":2}`,
+			wantErr: true,
+		},
+		{
+			name:    "call without payload",
+			raw:     `[2,"19223201","Heartbeat"]`,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported type",
+			raw:     `[5,"19223201",{}]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			msg := &ocpp.Message{}
+
+			err := json.Unmarshal([]byte(tc.raw), msg)
+			if tc.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, msg)
+
+			data, err := json.Marshal(msg)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tc.raw, string(data))
+		})
+	}
+}
+
+func TestNewCallErrorResult(t *testing.T) {
+	t.Parallel()
+
+	msg := ocpp.NewCallErrorResult("1", ocpp.NewCallError(ocpp.ErrorCodeNotSupported, "profile %d", 3))
+	assert.Equal(t, &ocpp.CallError{Code: ocpp.ErrorCodeNotSupported, Description: "profile 3"}, msg.Error)
+
+	msg = ocpp.NewCallErrorResult("1", errors.New("failure"))
+	assert.Equal(t, &ocpp.CallError{Code: ocpp.ErrorCodeInternalError, Description: "failure"}, msg.Error)
+}
@@ -0,0 +1,151 @@
+package ocpp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/futurehomeno/fimpgo/fimptype"
+
+	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/adapter/service/chargepoint"
+	"github.com/futurehomeno/cliffhanger/adapter/service/numericmeter"
+	"github.com/futurehomeno/cliffhanger/adapter/thing"
+)
+
+// thingGroup is a group of all services of a charger thing.
+const thingGroup = "ch_0"
+
+// Info is a model of thing information stored in the seed of a charger thing, so the thing can be restored before the charger reconnects.
+type Info struct {
+	Vendor          string `json:"vendor"`
+	Model           string `json:"model"`
+	SerialNumber    string `json:"serial_number,omitempty"`
+	FirmwareVersion string `json:"firmware_version,omitempty"`
+}
+
+// NewInfo creates a thing information from the boot notification of the charger.
+func NewInfo(charger *Charger) *Info {
+	boot := charger.BootInfo()
+	if boot == nil {
+		return &Info{}
+	}
+
+	serialNumber := boot.ChargePointSerialNumber
+	if serialNumber == "" {
+		serialNumber = boot.ChargeBoxSerialNumber
+	}
+
+	return &Info{
+		Vendor:          boot.ChargePointVendor,
+		Model:           boot.ChargePointModel,
+		SerialNumber:    serialNumber,
+		FirmwareVersion: boot.FirmwareVersion,
+	}
+}
+
+// NewThingFactory creates a factory of car charger things backed by chargers of the central system.
+// Things are identified by the charge point identity of the charger.
+func NewThingFactory(system CentralSystem) adapter.ThingFactory {
+	return &thingFactory{
+		system: system,
+	}
+}
+
+type thingFactory struct {
+	system CentralSystem
+}
+
+// Create creates a car charger thing of the charger identified by the thing ID.
+func (f *thingFactory) Create(a adapter.Adapter, publisher adapter.Publisher, thingState adapter.ThingState) (adapter.Thing, error) {
+	info := &Info{}
+
+	if err := thingState.Info(info); err != nil {
+		return nil, fmt.Errorf("ocpp: failed to retrieve info of charger %s: %w", thingState.ID(), err)
+	}
+
+	charger := f.system.Charger(thingState.ID())
+
+	return thing.NewCarCharger(publisher, thingState, CarChargerConfig(a, thingState, charger, info)), nil
+}
+
+// CarChargerConfig creates a configuration of a car charger thing exposing the chargepoint and the electricity meter of the charger.
+func CarChargerConfig(a adapter.Adapter, thingState adapter.ThingState, charger *Charger, info *Info) *thing.CarChargerConfig {
+	groups := []string{thingGroup}
+
+	maxCurrent, _ := charger.ChargepointMaxCurrentReport()
+
+	return &thing.CarChargerConfig{
+		ThingConfig: &adapter.ThingConfig{
+			Connector: charger,
+			InclusionReport: &fimptype.ThingInclusionReport{
+				Address:        thingState.Address(),
+				Groups:         groups,
+				ProductName:    strings.TrimSpace(info.Vendor + " " + info.Model),
+				ProductHash:    strings.Join([]string{string(a.Name()), info.Vendor, info.Model}, "_"),
+				ProductId:      info.Model,
+				ManufacturerId: info.Vendor,
+				DeviceId:       info.SerialNumber,
+				SwVersion:      info.FirmwareVersion,
+				CommTechnology: "local_network",
+				PowerSource:    "ac",
+			},
+		},
+		ChargepointConfig: &chargepoint.Config{
+			Specification: chargepoint.Specification(
+				string(a.Name()),
+				a.Address(),
+				thingState.Address(),
+				groups,
+				[]chargepoint.State{
+					chargepoint.StateDisconnected,
+					chargepoint.StateRequesting,
+					chargepoint.StateCharging,
+					chargepoint.StateSuspendedByEVSE,
+					chargepoint.StateSuspendedByEV,
+					chargepoint.StateFinished,
+					chargepoint.StateReserved,
+					chargepoint.StateUnavailable,
+					chargepoint.StateError,
+					chargepoint.StateUnknown,
+				},
+				chargepoint.WithSupportedMaxCurrent(maxCurrent),
+			),
+			Controller: charger,
+		},
+		MeterElecConfig: &numericmeter.Config{
+			Specification: numericmeter.Specification(
+				numericmeter.MeterElec,
+				a.Name(),
+				a.Address(),
+				thingState.Address(),
+				groups,
+				numericmeter.Units{numericmeter.UnitW, numericmeter.UnitKWh},
+				numericmeter.WithExtendedValues(
+					numericmeter.ValueCurrentPhase1,
+					numericmeter.ValueCurrentPhase2,
+					numericmeter.ValueCurrentPhase3,
+					numericmeter.ValuePowerImport,
+					numericmeter.ValueEnergyImport,
+				),
+			),
+			Reporter: charger,
+		},
+	}
+}
+
+// EnsureThing creates a car charger thing of the charger unless it already exists. It is meant to be called from the boot callback.
+func EnsureThing(a adapter.Adapter, charger *Charger) error {
+	if a.ThingByID(charger.ID()) != nil {
+		return nil
+	}
+
+	err := a.CreateThing(&adapter.ThingSeed{
+		ID:   charger.ID(),
+		Info: NewInfo(charger),
+	})
+	if err != nil {
+		return fmt.Errorf("ocpp: failed to create thing of charger %s: %w", charger.ID(), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package ocpp_test
+
+import (
+	"testing"
+
+	"github.com/futurehomeno/fimpgo/fimptype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/futurehomeno/cliffhanger/adapter/ocpp"
+	"github.com/futurehomeno/cliffhanger/adapter/service/chargepoint"
+	"github.com/futurehomeno/cliffhanger/adapter/service/numericmeter"
+	mockedadapter "github.com/futurehomeno/cliffhanger/test/mocks/adapter"
+)
+
+func TestThingFactory(t *testing.T) {
+	t.Parallel()
+
+	system := newTestCentralSystem(t, &ocpp.Config{MaxCurrent: 16})
+
+	a := mockedadapter.NewAdapter(t)
+	a.On("Name").Return(fimptype.ResourceNameT("ocpp"))
+	a.On("Address").Return("1")
+
+	ts := mockedadapter.NewThingState(t)
+	ts.On("ID").Return("cp-1")
+	ts.On("Address").Return("cp-1")
+	ts.On("Info", mock.Anything).Run(func(args mock.Arguments) {
+		info := args.Get(0).(*ocpp.Info) //nolint:forcetypeassert
+		info.Vendor = "Acme"
+		info.Model = "Wallbox"
+		info.SerialNumber = "SN-1"
+	}).Return(nil)
+
+	thing, err := ocpp.NewThingFactory(system).Create(a, nil, ts)
+	assert.NoError(t, err)
+
+	report := thing.InclusionReport()
+	assert.Equal(t, "Acme Wallbox", report.ProductName)
+	assert.Equal(t, "ocpp_Acme_Wallbox", report.ProductHash)
+	assert.Equal(t, "SN-1", report.DeviceId)
+	assert.Len(t, report.Services, 2)
+
+	chargepoints := thing.Services(chargepoint.Chargepoint)
+	assert.Len(t, chargepoints, 1)
+	assert.Equal(t, "/rt:dev/rn:ocpp/ad:1/sv:chargepoint/ad:cp-1", chargepoints[0].Specification().Address)
+
+	maxCurrent, ok := chargepoints[0].Specification().PropertyInteger(chargepoint.PropertySupportedMaxCurrent)
+	assert.True(t, ok)
+	assert.Equal(t, 16, maxCurrent)
+
+	meters := thing.Services(numericmeter.MeterElec)
+	assert.Len(t, meters, 1)
+	assert.Equal(t, []string{"ch_0"}, meters[0].Specification().Groups)
+}
@@ -0,0 +1,168 @@
+package adapter
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// defaultPollingOffset is a fraction of the polling interval by which the window of a single polling cycle is shortened,
+// so a source fetched in the previous cycle is not considered fresh because of a small delay of the next task run.
+const defaultPollingOffset = 0.05
+
+// DataSource represents a shared source of data, e.g. a single Modbus or cloud API request returning data for many services.
+// Fetched data should be kept by the implementation and served to the dependent reporters and controllers.
+type DataSource interface {
+	// Fetch fetches data from the source.
+	Fetch() error
+}
+
+// DataSourceFn is a function adapter that allows to use anonymous functions as data source.
+type DataSourceFn func() error
+
+// Fetch fetches data from the source.
+func (f DataSourceFn) Fetch() error {
+	return f()
+}
+
+// DataSourceDependent is an optional interface of a reporter or controller declaring shared data sources it depends on.
+// Services configured with a polling coordinator ensure that the declared sources are fetched before calling the reporter or controller.
+type DataSourceDependent interface {
+	// DataSources returns identifiers of data sources the reporter or controller depends on.
+	DataSources() []string
+}
+
+// PollingCoordinator is a service coordinating fetches of shared data sources, so data of a source is fetched once per polling cycle
+// regardless of the number of dependent services.
+type PollingCoordinator interface {
+	// Register registers a data source under the provided identifier. The source is fetched at most once per polling cycle
+	// or once per the provided minimal interval if it is longer than the cycle.
+	Register(id string, source DataSource, minInterval time.Duration)
+	// Poll fetches the provided data sources unless they were already fetched within their window.
+	// If a fetch failed, the error is returned to all dependents polling within the same window.
+	Poll(ids ...string) error
+	// Reset forces the next poll to fetch the provided data sources regardless of their windows, e.g. after a change made by a controller.
+	// All data sources are reset if none are provided.
+	Reset(ids ...string)
+}
+
+// NewPollingCoordinator creates new instance of a polling coordinator. The cycle should be equal to the frequency of reporting tasks.
+func NewPollingCoordinator(cycle time.Duration) PollingCoordinator {
+	return &pollingCoordinator{
+		cycle:   time.Duration((1 - defaultPollingOffset) * float64(cycle)),
+		sources: make(map[string]*polledSource),
+	}
+}
+
+// PollDataSources polls data sources declared by the dependent if it implements DataSourceDependent. Nil coordinator is ignored.
+func PollDataSources(coordinator PollingCoordinator, dependent any) error {
+	if coordinator == nil {
+		return nil
+	}
+
+	d, ok := dependent.(DataSourceDependent)
+	if !ok {
+		return nil
+	}
+
+	return coordinator.Poll(d.DataSources()...)
+}
+
+// ResetDataSources resets data sources declared by the dependent if it implements DataSourceDependent, so data changed by a command
+// is fetched again before the confirmation report. Nil coordinator is ignored.
+func ResetDataSources(coordinator PollingCoordinator, dependent any) {
+	if coordinator == nil {
+		return
+	}
+
+	d, ok := dependent.(DataSourceDependent)
+	if !ok {
+		return
+	}
+
+	if ids := d.DataSources(); len(ids) > 0 {
+		coordinator.Reset(ids...)
+	}
+}
+
+type pollingCoordinator struct {
+	lock    sync.Mutex
+	cycle   time.Duration
+	sources map[string]*polledSource
+}
+
+// polledSource represents a registered data source and the outcome of its last fetch.
+type polledSource struct {
+	lock        sync.Mutex
+	source      DataSource
+	minInterval time.Duration
+	lastFetch   time.Time
+	lastErr     error
+}
+
+// Register registers a data source under the provided identifier.
+func (c *pollingCoordinator) Register(id string, source DataSource, minInterval time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.sources[id] = &polledSource{
+		source:      source,
+		minInterval: max(minInterval, c.cycle),
+	}
+}
+
+// Poll fetches the provided data sources unless they were already fetched within their window.
+func (c *pollingCoordinator) Poll(ids ...string) error {
+	var errs []error
+
+	for _, id := range ids {
+		c.lock.Lock()
+		s, ok := c.sources[id]
+		c.lock.Unlock()
+
+		if !ok {
+			errs = append(errs, fmt.Errorf("polling coordinator: data source %s is not registered", id))
+
+			continue
+		}
+
+		if err := s.poll(); err != nil {
+			errs = append(errs, fmt.Errorf("polling coordinator: failed to fetch data source %s: %w", id, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Reset forces the next poll to fetch the provided data sources regardless of their windows. All data sources are reset if none are provided.
+func (c *pollingCoordinator) Reset(ids ...string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for id, s := range c.sources {
+		if len(ids) > 0 && !slices.Contains(ids, id) {
+			continue
+		}
+
+		s.lock.Lock()
+		s.lastFetch = time.Time{}
+		s.lock.Unlock()
+	}
+}
+
+// poll fetches the source unless it was already fetched within its window. Concurrent polls wait for the ongoing fetch.
+func (s *polledSource) poll() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.lastFetch.IsZero() && time.Since(s.lastFetch) < s.minInterval {
+		return s.lastErr
+	}
+
+	s.lastErr = s.source.Fetch()
+	s.lastFetch = time.Now()
+
+	return s.lastErr
+}
@@ -0,0 +1,103 @@
+package adapter_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/futurehomeno/cliffhanger/adapter"
+)
+
+func TestPollingCoordinator_Poll(t *testing.T) {
+	t.Parallel()
+
+	var fetches, failures int
+
+	coordinator := adapter.NewPollingCoordinator(time.Hour)
+	coordinator.Register("modbus", adapter.DataSourceFn(func() error {
+		fetches++
+
+		return nil
+	}), 0)
+	coordinator.Register("cloud", adapter.DataSourceFn(func() error {
+		failures++
+
+		return errors.New("test")
+	}), 0)
+
+	assert.NoError(t, coordinator.Poll("modbus"))
+	assert.NoError(t, coordinator.Poll("modbus"))
+	assert.Equal(t, 1, fetches, "source must be fetched once per cycle")
+
+	assert.Error(t, coordinator.Poll("modbus", "cloud"))
+	assert.Error(t, coordinator.Poll("cloud"))
+	assert.Equal(t, 1, failures, "failed fetch must not be repeated within the cycle")
+
+	coordinator.Reset()
+
+	assert.NoError(t, coordinator.Poll("modbus"))
+	assert.Equal(t, 2, fetches)
+
+	assert.Error(t, coordinator.Poll("unknown"))
+}
+
+func TestPollingCoordinator_MinInterval(t *testing.T) {
+	t.Parallel()
+
+	var fetches int
+
+	coordinator := adapter.NewPollingCoordinator(10 * time.Millisecond)
+	coordinator.Register("limited", adapter.DataSourceFn(func() error {
+		fetches++
+
+		return nil
+	}), time.Hour)
+
+	assert.NoError(t, coordinator.Poll("limited"))
+
+	time.Sleep(20 * time.Millisecond)
+
+	assert.NoError(t, coordinator.Poll("limited"))
+	assert.Equal(t, 1, fetches, "source must not be fetched more often than its minimal interval")
+}
+
+func TestPollDataSources(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, adapter.PollDataSources(nil, testDependent{"unknown"}))
+	assert.NoError(t, adapter.PollDataSources(adapter.NewPollingCoordinator(time.Hour), struct{}{}))
+	assert.Error(t, adapter.PollDataSources(adapter.NewPollingCoordinator(time.Hour), testDependent{"unknown"}))
+}
+
+func TestResetDataSources(t *testing.T) {
+	t.Parallel()
+
+	fetches := make(map[string]int)
+
+	coordinator := adapter.NewPollingCoordinator(time.Hour)
+
+	for _, id := range []string{"modbus", "cloud"} {
+		coordinator.Register(id, adapter.DataSourceFn(func() error {
+			fetches[id]++
+
+			return nil
+		}), 0)
+	}
+
+	assert.NoError(t, coordinator.Poll("modbus", "cloud"))
+
+	adapter.ResetDataSources(nil, testDependent{"modbus"})
+	adapter.ResetDataSources(coordinator, struct{}{})
+	adapter.ResetDataSources(coordinator, testDependent{"modbus"})
+
+	assert.NoError(t, coordinator.Poll("modbus", "cloud"))
+	assert.Equal(t, map[string]int{"modbus": 2, "cloud": 1}, fetches, "only sources of the dependent must be fetched again")
+}
+
+type testDependent []string
+
+func (d testDependent) DataSources() []string {
+	return d
+}
@@ -0,0 +1,351 @@
+package profile
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/futurehomeno/fimpgo/fimptype"
+
+	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/router"
+	"github.com/futurehomeno/cliffhanger/task"
+)
+
+// ControllerFactory is an interface representing a binding, which creates a controller, reporter or connector for a particular thing.
+type ControllerFactory interface {
+	// Create creates a controller for the thing represented by the provided state.
+	// Definition of the service is nil if the controller is created as a thing connector.
+	Create(thingState adapter.ThingState, definition *Service) (any, error)
+}
+
+// ControllerFactoryFn is an adapter allowing usage of anonymous function as a service meeting controller factory interface.
+type ControllerFactoryFn func(thingState adapter.ThingState, definition *Service) (any, error)
+
+// Create creates a controller for the thing represented by the provided state.
+func (f ControllerFactoryFn) Create(thingState adapter.ThingState, definition *Service) (any, error) {
+	return f(thingState, definition)
+}
+
+// Resolver is an interface representing a service, which determines the name of the profile of a thing.
+type Resolver interface {
+	// Resolve returns the name of the profile of the thing represented by the provided state.
+	Resolve(thingState adapter.ThingState) (string, error)
+}
+
+// ResolverFn is an adapter allowing usage of anonymous function as a service meeting resolver interface.
+type ResolverFn func(thingState adapter.ThingState) (string, error)
+
+// Resolve returns the name of the profile of the thing represented by the provided state.
+func (f ResolverFn) Resolve(thingState adapter.ThingState) (string, error) {
+	return f(thingState)
+}
+
+// Info is a model of thing information expected by the default resolver.
+type Info struct {
+	Profile string `json:"profile"`
+}
+
+// ResolveFromInfo returns a resolver, which reads the profile name from the thing information provided in its seed.
+func ResolveFromInfo() Resolver {
+	return ResolverFn(func(thingState adapter.ThingState) (string, error) {
+		info := &Info{}
+
+		if err := thingState.Info(info); err != nil {
+			return "", fmt.Errorf("failed to retrieve thing info: %w", err)
+		}
+
+		if info.Profile == "" {
+			return "", fmt.Errorf("thing info does not contain a profile name")
+		}
+
+		return info.Profile, nil
+	})
+}
+
+// FactoryConfig represents a configuration of the profile thing factory.
+type FactoryConfig struct {
+	// Profiles is a list of all known profiles.
+	Profiles []*Profile
+	// Controllers is a map of controller bindings by their name, referenced by services and connectors in profiles.
+	Controllers map[string]ControllerFactory
+	// Connector is a name of the default connector binding used by profiles which do not define their own.
+	Connector string
+	// Resolver determines the profile of a thing. Defaults to a resolver reading the profile name from the thing info.
+	Resolver Resolver
+	// Kinds is a map of additional or overridden service kinds. Default kinds are always available.
+	Kinds Kinds
+}
+
+// ThingFactory is an interface representing a thing factory building things from profiles.
+type ThingFactory interface {
+	adapter.ThingFactory
+
+	// Profile returns a profile by its name.
+	Profile(name string) (*Profile, bool)
+	// Route creates routing for all services used by known profiles.
+	Route(serviceRegistry adapter.ServiceRegistry) []*router.Routing
+	// Tasks creates reporting tasks for all services used by known profiles.
+	Tasks(serviceRegistry adapter.ServiceRegistry, reportingInterval time.Duration, reportingVoters ...task.Voter) []*task.Task
+}
+
+// NewThingFactory creates new instance of a thing factory building things from profiles.
+// Returns an error if any profile is invalid, duplicated or uses a service without a known kind.
+func NewThingFactory(cfg *FactoryConfig) (ThingFactory, error) {
+	kinds := DefaultKinds()
+	maps.Copy(kinds, cfg.Kinds)
+
+	resolver := cfg.Resolver
+	if resolver == nil {
+		resolver = ResolveFromInfo()
+	}
+
+	f := &thingFactory{
+		profiles:    make(map[string]*Profile),
+		controllers: cfg.Controllers,
+		connector:   cfg.Connector,
+		resolver:    resolver,
+		kinds:       kinds,
+	}
+
+	for _, p := range cfg.Profiles {
+		if err := p.Validate(); err != nil {
+			return nil, err
+		}
+
+		if _, ok := f.profiles[p.Name]; ok {
+			return nil, fmt.Errorf("profile %s: profile is defined more than once", p.Name)
+		}
+
+		for _, s := range p.Services {
+			if _, ok := kinds.Find(s.Name); !ok {
+				return nil, fmt.Errorf("profile %s: service %s is not supported", p.Name, s.Name)
+			}
+		}
+
+		f.profiles[p.Name] = p
+	}
+
+	return f, nil
+}
+
+// thingFactory is a private implementation of the profile thing factory.
+type thingFactory struct {
+	profiles    map[string]*Profile
+	controllers map[string]ControllerFactory
+	connector   string
+	resolver    Resolver
+	kinds       Kinds
+}
+
+// Profile returns a profile by its name.
+func (f *thingFactory) Profile(name string) (*Profile, bool) {
+	p, ok := f.profiles[name]
+
+	return p, ok
+}
+
+// Create creates an instance of a thing using provided state.
+func (f *thingFactory) Create(a adapter.Adapter, publisher adapter.Publisher, thingState adapter.ThingState) (adapter.Thing, error) {
+	name, err := f.resolver.Resolve(thingState)
+	if err != nil {
+		return nil, fmt.Errorf("profile: failed to resolve profile of thing %s: %w", thingState.ID(), err)
+	}
+
+	p, ok := f.profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %s: profile of thing %s is unknown", name, thingState.ID())
+	}
+
+	connector, err := f.createConnector(p, thingState)
+	if err != nil {
+		return nil, err
+	}
+
+	services := make([]adapter.Service, 0, len(p.Services))
+
+	for _, definition := range p.Services {
+		s, err := f.createService(a, publisher, thingState, p, definition)
+		if err != nil {
+			return nil, err
+		}
+
+		services = append(services, s)
+	}
+
+	cfg := &adapter.ThingConfig{
+		InclusionReport: f.inclusionReport(p, thingState),
+		Connector:       connector,
+	}
+
+	if p.ConnectivityReporting != nil {
+		cfg.ConnectivityReportingStrategy, err = p.ConnectivityReporting.Strategy()
+		if err != nil {
+			return nil, fmt.Errorf("profile %s: invalid connectivity reporting: %w", p.Name, err)
+		}
+	}
+
+	return adapter.NewThing(publisher, thingState, cfg, services...), nil
+}
+
+// Route creates routing for all services used by known profiles.
+func (f *thingFactory) Route(serviceRegistry adapter.ServiceRegistry) []*router.Routing {
+	var routing []*router.Routing
+
+	for _, kind := range f.usedKinds() {
+		if kind.Route != nil {
+			routing = append(routing, kind.Route(serviceRegistry)...)
+		}
+	}
+
+	return routing
+}
+
+// Tasks creates reporting tasks for all services used by known profiles.
+func (f *thingFactory) Tasks(serviceRegistry adapter.ServiceRegistry, reportingInterval time.Duration, reportingVoters ...task.Voter) []*task.Task {
+	var tasks []*task.Task
+
+	for _, kind := range f.usedKinds() {
+		if kind.Task != nil {
+			tasks = append(tasks, kind.Task(serviceRegistry, reportingInterval, reportingVoters...))
+		}
+	}
+
+	return tasks
+}
+
+// usedKinds returns a deduplicated list of kinds used by known profiles.
+func (f *thingFactory) usedKinds() []*Kind {
+	var kinds []*Kind
+
+	seen := make(map[*Kind]bool)
+
+	for _, name := range slices.Sorted(maps.Keys(f.profiles)) {
+		for _, s := range f.profiles[name].Services {
+			kind, ok := f.kinds.Find(s.Name)
+			if !ok || seen[kind] {
+				continue
+			}
+
+			seen[kind] = true
+
+			kinds = append(kinds, kind)
+		}
+	}
+
+	return kinds
+}
+
+// createConnector creates a connector for the thing using the binding referenced by the profile.
+func (f *thingFactory) createConnector(p *Profile, thingState adapter.ThingState) (adapter.Connector, error) {
+	name := p.Connector
+	if name == "" {
+		name = f.connector
+	}
+
+	c, err := f.createController(name, thingState, nil)
+	if err != nil {
+		return nil, fmt.Errorf("profile %s: failed to create connector: %w", p.Name, err)
+	}
+
+	connector, ok := c.(adapter.Connector)
+	if !ok {
+		return nil, fmt.Errorf("profile %s: binding %s does not provide a connector", p.Name, name)
+	}
+
+	return connector, nil
+}
+
+// createService creates a service according to its definition using a controller referenced by the definition.
+func (f *thingFactory) createService(
+	a adapter.Adapter,
+	publisher adapter.Publisher,
+	thingState adapter.ThingState,
+	p *Profile,
+	definition *Service,
+) (adapter.Service, error) {
+	kind, ok := f.kinds.Find(definition.Name)
+	if !ok {
+		return nil, fmt.Errorf("profile %s: service %s is not supported", p.Name, definition.Name)
+	}
+
+	controller, err := f.createController(definition.Controller, thingState, definition)
+	if err != nil {
+		return nil, fmt.Errorf("profile %s: failed to create controller of service %s: %w", p.Name, definition.Name, err)
+	}
+
+	s, err := kind.Build(publisher, specification(a, thingState, kind, definition), controller, definition)
+	if err != nil {
+		return nil, fmt.Errorf("profile %s: failed to build service %s: %w", p.Name, definition.Name, err)
+	}
+
+	return s, nil
+}
+
+// createController creates a controller using a binding of the provided name.
+func (f *thingFactory) createController(name string, thingState adapter.ThingState, definition *Service) (any, error) {
+	binding, ok := f.controllers[name]
+	if !ok || binding == nil {
+		return nil, fmt.Errorf("binding %s is not registered", name)
+	}
+
+	return binding.Create(thingState, definition)
+}
+
+// inclusionReport creates an inclusion report of the thing according to the profile.
+func (f *thingFactory) inclusionReport(p *Profile, thingState adapter.ThingState) *fimptype.ThingInclusionReport {
+	return &fimptype.ThingInclusionReport{
+		Address:           thingState.Address(),
+		Groups:            p.Groups,
+		ProductName:       p.ProductName,
+		ProductHash:       p.ProductHash,
+		ProductId:         p.ProductID,
+		ManufacturerId:    p.ManufacturerID,
+		DeviceId:          thingState.ID(),
+		HwVersion:         p.HwVersion,
+		SwVersion:         p.SwVersion,
+		CommTechnology:    p.CommTechnology,
+		PowerSource:       p.PowerSource,
+		WakeUpInterval:    p.WakeUpInterval,
+		Security:          p.Security,
+		TechSpecificProps: p.TechProps,
+	}
+}
+
+// specification creates a specification of the service according to its definition.
+func specification(a adapter.Adapter, thingState adapter.ThingState, kind *Kind, definition *Service) *fimptype.Service {
+	address := thingState.Address()
+	if definition.Address != "" {
+		address += "_" + definition.Address
+	}
+
+	props := make(map[string]any, len(definition.Props)+2)
+	maps.Copy(props, definition.Props)
+
+	if len(definition.Units) > 0 {
+		props[propertyOrDefault(kind.UnitsProperty, PropertySupportedUnits)] = definition.Units
+	}
+
+	if len(definition.Modes) > 0 {
+		props[propertyOrDefault(kind.ModesProperty, PropertySupportedModes)] = definition.Modes
+	}
+
+	return &fimptype.Service{
+		Address:    fmt.Sprintf("/rt:dev/rn:%s/ad:%s/sv:%s/ad:%s", a.Name(), a.Address(), definition.Name, address),
+		Name:       definition.Name,
+		Groups:     definition.Groups,
+		Enabled:    true,
+		Props:      props,
+		Interfaces: append([]fimptype.Interface(nil), definition.Interfaces...),
+	}
+}
+
+// propertyOrDefault returns the property name or the default one if the name is empty.
+func propertyOrDefault(name, defaultName string) string {
+	if name == "" {
+		return defaultName
+	}
+
+	return name
+}
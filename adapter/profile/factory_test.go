@@ -0,0 +1,326 @@
+package profile_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/futurehomeno/fimpgo"
+	"github.com/futurehomeno/fimpgo/fimptype"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/adapter/profile"
+	"github.com/futurehomeno/cliffhanger/adapter/service/numericsensor"
+	"github.com/futurehomeno/cliffhanger/adapter/service/parameters"
+	"github.com/futurehomeno/cliffhanger/router"
+	"github.com/futurehomeno/cliffhanger/task"
+	adapterhelper "github.com/futurehomeno/cliffhanger/test/helper/adapter"
+	mockedadapter "github.com/futurehomeno/cliffhanger/test/mocks/adapter"
+	mockednumericsensor "github.com/futurehomeno/cliffhanger/test/mocks/adapter/service/numericsensor"
+	mockedoutbinswitch "github.com/futurehomeno/cliffhanger/test/mocks/adapter/service/outbinswitch"
+	mockedparameters "github.com/futurehomeno/cliffhanger/test/mocks/adapter/service/parameters"
+	"github.com/futurehomeno/cliffhanger/test/suite"
+)
+
+const (
+	testAdapterWorkDir = "../../testdata/adapter/test_adapter"
+	reportingInterval  = 100 * time.Millisecond
+)
+
+func TestThingFactory(t *testing.T) { //nolint:paralleltest
+	s := &suite.Suite{
+		Cases: []*suite.Case{
+			{
+				Name:     "thing built from a profile",
+				TearDown: adapterhelper.TearDownAdapter(testAdapterWorkDir),
+				Setup: setupThingFactory(
+					mockedoutbinswitch.NewController(t).
+						MockedBinarySwitchBinarySet(true, nil, true).
+						MockedBinarySwitchBinaryReport(true, nil, true),
+					mockednumericsensor.NewReporter(t).
+						MockNumericSensorReport("C", 21.5, nil, true),
+					mockedparameters.NewController(t),
+				),
+				Nodes: []*suite.Node{
+					{
+						Name: "set binary switch",
+						Command: suite.NewMessageBuilder().
+							BoolMessage("pt:j1/mt:cmd/rt:dev/rn:test_adapter/ad:1/sv:out_bin_switch/ad:2", "cmd.binary.set", "out_bin_switch", true).
+							Build(),
+						Expectations: []*suite.Expectation{
+							suite.ExpectBool("pt:j1/mt:evt/rt:dev/rn:test_adapter/ad:1/sv:out_bin_switch/ad:2", "evt.binary.report", "out_bin_switch", true),
+						},
+					},
+					{
+						Name: "get sensor report",
+						Command: suite.NewMessageBuilder().
+							StringMessage("pt:j1/mt:cmd/rt:dev/rn:test_adapter/ad:1/sv:sensor_temp/ad:2", "cmd.sensor.get_report", "sensor_temp", "C").
+							Build(),
+						Expectations: []*suite.Expectation{
+							suite.ExpectFloat("pt:j1/mt:evt/rt:dev/rn:test_adapter/ad:1/sv:sensor_temp/ad:2", "evt.sensor.report", "sensor_temp", 21.5),
+						},
+					},
+					{
+						Name: "get supported parameters defined by the profile",
+						Command: suite.NewMessageBuilder().
+							NullMessage("pt:j1/mt:cmd/rt:dev/rn:test_adapter/ad:1/sv:parameters/ad:2", "cmd.sup_params.get_report", "parameters").
+							Build(),
+						Expectations: []*suite.Expectation{
+							suite.ExpectObject("pt:j1/mt:evt/rt:dev/rn:test_adapter/ad:1/sv:parameters/ad:2", "evt.sup_params.report", "parameters", []*parameters.ParameterSpecification{
+								(&parameters.ParameterSpecification{
+									ID:           "led",
+									Name:         "LED brightness",
+									ValueType:    parameters.ValueTypeInt,
+									WidgetType:   parameters.WidgetTypeInput,
+									DefaultValue: 50,
+								}).WithMin(0).WithMax(100),
+							}),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	s.Run(t)
+}
+
+func TestThingFactory_Create(t *testing.T) {
+	t.Parallel()
+
+	p, err := profile.Load("./testdata/smart_plug.json")
+	assert.NoError(t, err)
+
+	f, err := profile.NewThingFactory(&profile.FactoryConfig{
+		Profiles:    []*profile.Profile{p},
+		Controllers: testControllers(t, mockedoutbinswitch.NewController(t), mockednumericsensor.NewReporter(t), mockedparameters.NewController(t)),
+		Resolver: profile.ResolverFn(func(_ adapter.ThingState) (string, error) {
+			return "smart_plug", nil
+		}),
+	})
+	assert.NoError(t, err)
+
+	ad := mockedadapter.NewAdapter(t)
+	ad.On("Name").Return(fimptype.ResourceNameT("test_adapter"))
+	ad.On("Address").Return("1")
+
+	ts := mockedadapter.NewThingState(t)
+	ts.On("ID").Return("B")
+	ts.On("Address").Return("2")
+
+	th, err := f.Create(ad, nil, ts)
+	assert.NoError(t, err)
+
+	report := th.InclusionReport()
+
+	assert.Equal(t, "2", report.Address)
+	assert.Equal(t, "B", report.DeviceId)
+	assert.Equal(t, "Smart Plug", report.ProductName)
+	assert.Equal(t, "acme", report.ManufacturerId)
+	assert.Len(t, report.Services, 3)
+
+	sensor := th.ServiceByTopic("pt:j1/mt:cmd/rt:dev/rn:test_adapter/ad:1/sv:sensor_temp/ad:2")
+	assert.NotNil(t, sensor)
+	assert.Equal(t, []string{"C"}, sensor.Specification().Props[numericsensor.PropertySupportedUnits])
+	assert.Equal(t, []string{"ch_0"}, sensor.Specification().Groups)
+
+	assert.Len(t, f.Route(ad), 6)
+	assert.Len(t, f.Tasks(ad, reportingInterval), 2)
+}
+
+func TestThingFactory_Create_Errors(t *testing.T) {
+	t.Parallel()
+
+	p, err := profile.Load("./testdata/smart_plug.json")
+	assert.NoError(t, err)
+
+	tcs := []struct {
+		name        string
+		controllers map[string]profile.ControllerFactory
+		resolver    profile.Resolver
+	}{
+		{
+			name:        "unknown profile",
+			controllers: testControllers(t, mockedoutbinswitch.NewController(t), mockednumericsensor.NewReporter(t), mockedparameters.NewController(t)),
+			resolver: profile.ResolverFn(func(_ adapter.ThingState) (string, error) {
+				return "unknown", nil
+			}),
+		},
+		{
+			name:        "resolver error",
+			controllers: testControllers(t, mockedoutbinswitch.NewController(t), mockednumericsensor.NewReporter(t), mockedparameters.NewController(t)),
+			resolver: profile.ResolverFn(func(_ adapter.ThingState) (string, error) {
+				return "", errors.New("test")
+			}),
+		},
+		{
+			name: "missing binding",
+			controllers: map[string]profile.ControllerFactory{
+				"connector": staticController(mockedadapter.NewConnector(t)),
+			},
+		},
+		{
+			name: "controller not implementing the service interface",
+			controllers: map[string]profile.ControllerFactory{
+				"connector":   staticController(mockedadapter.NewConnector(t)),
+				"relay":       staticController(mockednumericsensor.NewReporter(t)),
+				"temperature": staticController(mockednumericsensor.NewReporter(t)),
+				"settings":    staticController(mockedparameters.NewController(t)),
+			},
+		},
+		{
+			name: "binding not providing a connector",
+			controllers: map[string]profile.ControllerFactory{
+				"connector": staticController(mockednumericsensor.NewReporter(t)),
+			},
+		},
+		{
+			name: "binding error",
+			controllers: map[string]profile.ControllerFactory{
+				"connector": profile.ControllerFactoryFn(func(_ adapter.ThingState, _ *profile.Service) (any, error) {
+					return nil, errors.New("test")
+				}),
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			resolver := tc.resolver
+			if resolver == nil {
+				resolver = profile.ResolverFn(func(_ adapter.ThingState) (string, error) {
+					return "smart_plug", nil
+				})
+			}
+
+			f, err := profile.NewThingFactory(&profile.FactoryConfig{
+				Profiles:    []*profile.Profile{p},
+				Controllers: tc.controllers,
+				Resolver:    resolver,
+			})
+			assert.NoError(t, err)
+
+			ad := mockedadapter.NewAdapter(t)
+			ad.On("Name").Return(fimptype.ResourceNameT("test_adapter")).Maybe()
+			ad.On("Address").Return("1").Maybe()
+
+			ts := mockedadapter.NewThingState(t)
+			ts.On("ID").Return("B").Maybe()
+			ts.On("Address").Return("2").Maybe()
+
+			_, err = f.Create(ad, nil, ts)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestNewThingFactory_Errors(t *testing.T) {
+	t.Parallel()
+
+	valid := &profile.Profile{
+		Name:     "test",
+		Services: []*profile.Service{{Name: "out_bin_switch", Controller: "relay"}},
+	}
+
+	tcs := []struct {
+		name     string
+		profiles []*profile.Profile
+	}{
+		{
+			name:     "invalid profile",
+			profiles: []*profile.Profile{{Name: "test"}},
+		},
+		{
+			name:     "duplicated profile",
+			profiles: []*profile.Profile{valid, valid},
+		},
+		{
+			name: "unsupported service",
+			profiles: []*profile.Profile{{
+				Name:     "test",
+				Services: []*profile.Service{{Name: "teleporter", Controller: "relay"}},
+			}},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := profile.NewThingFactory(&profile.FactoryConfig{Profiles: tc.profiles})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestKinds_Find(t *testing.T) {
+	t.Parallel()
+
+	kinds := profile.DefaultKinds()
+
+	presence, ok := kinds.Find("sensor_presence")
+	assert.True(t, ok)
+	assert.Same(t, kinds["sensor_presence"], presence)
+
+	sensor, ok := kinds.Find("sensor_temp")
+	assert.True(t, ok)
+	assert.Same(t, kinds["sensor_*"], sensor)
+
+	meter, ok := kinds.Find("meter_elec")
+	assert.True(t, ok)
+	assert.Same(t, kinds["meter_*"], meter)
+
+	_, ok = kinds.Find("teleporter")
+	assert.False(t, ok)
+}
+
+func setupThingFactory(
+	relay *mockedoutbinswitch.Controller,
+	temperature *mockednumericsensor.Reporter,
+	settings *mockedparameters.Controller,
+) suite.BaseSetup {
+	return func(t *testing.T, mqtt *fimpgo.MqttTransport) ([]*router.Routing, []*task.Task, []suite.Mock) {
+		t.Helper()
+
+		profiles, err := profile.LoadDir("./testdata")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		f, err := profile.NewThingFactory(&profile.FactoryConfig{
+			Profiles:    profiles[:1],
+			Controllers: testControllers(t, relay, temperature, settings),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		seeds := adapter.ThingSeeds{
+			{ID: "B", CustomAddress: "2", Info: &profile.Info{Profile: "smart_plug"}},
+		}
+
+		ad := adapterhelper.PrepareSeededAdapter(t, testAdapterWorkDir, mqtt, f.Create, seeds)
+
+		return f.Route(ad), nil, []suite.Mock{relay, temperature, settings}
+	}
+}
+
+func testControllers(t *testing.T, relay, temperature, settings any) map[string]profile.ControllerFactory {
+	t.Helper()
+
+	return map[string]profile.ControllerFactory{
+		"connector":   staticController(mockedadapter.NewDefaultConnector(t)),
+		"relay":       staticController(relay),
+		"temperature": staticController(temperature),
+		"settings":    staticController(settings),
+	}
+}
+
+func staticController(controller any) profile.ControllerFactory {
+	return profile.ControllerFactoryFn(func(_ adapter.ThingState, _ *profile.Service) (any, error) {
+		return controller, nil
+	})
+}
@@ -0,0 +1,414 @@
+package profile
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/futurehomeno/fimpgo/fimptype"
+
+	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/adapter/cache"
+	"github.com/futurehomeno/cliffhanger/adapter/service/battery"
+	"github.com/futurehomeno/cliffhanger/adapter/service/chargepoint"
+	"github.com/futurehomeno/cliffhanger/adapter/service/colorctrl"
+	"github.com/futurehomeno/cliffhanger/adapter/service/devsys"
+	"github.com/futurehomeno/cliffhanger/adapter/service/diagnostic"
+	"github.com/futurehomeno/cliffhanger/adapter/service/fanctrl"
+	"github.com/futurehomeno/cliffhanger/adapter/service/mediaplayer"
+	"github.com/futurehomeno/cliffhanger/adapter/service/numericmeter"
+	"github.com/futurehomeno/cliffhanger/adapter/service/numericsensor"
+	"github.com/futurehomeno/cliffhanger/adapter/service/ota"
+	"github.com/futurehomeno/cliffhanger/adapter/service/outbinswitch"
+	"github.com/futurehomeno/cliffhanger/adapter/service/outlvlswitch"
+	"github.com/futurehomeno/cliffhanger/adapter/service/parameters"
+	"github.com/futurehomeno/cliffhanger/adapter/service/presence"
+	"github.com/futurehomeno/cliffhanger/adapter/service/scenectrl"
+	"github.com/futurehomeno/cliffhanger/adapter/service/thermostat"
+	"github.com/futurehomeno/cliffhanger/adapter/service/waterheater"
+	"github.com/futurehomeno/cliffhanger/router"
+	"github.com/futurehomeno/cliffhanger/task"
+)
+
+// Constants defining default properties under which supported units and modes are published.
+const (
+	PropertySupportedUnits = "sup_units"
+	PropertySupportedModes = "sup_modes"
+)
+
+// ServiceBuilder creates a service from the provided specification and the controller bound to it.
+type ServiceBuilder func(publisher adapter.ServicePublisher, specification *fimptype.Service, controller any, definition *Service) (adapter.Service, error)
+
+// Kind defines how services of a particular name are built from a profile and how they are routed.
+type Kind struct {
+	// Build creates the service.
+	Build ServiceBuilder
+	// Route creates routing for all services of the kind. Optional.
+	Route func(serviceRegistry adapter.ServiceRegistry) []*router.Routing
+	// Task creates a reporting task for all services of the kind. Optional.
+	Task func(serviceRegistry adapter.ServiceRegistry, frequency time.Duration, voters ...task.Voter) *task.Task
+	// UnitsProperty is a name of the property under which supported units are published. Defaults to sup_units.
+	UnitsProperty string
+	// ModesProperty is a name of the property under which supported modes are published. Defaults to sup_modes.
+	ModesProperty string
+}
+
+// Kinds is a map of service kinds by service name.
+// A name ending with an asterisk matches all services starting with the same prefix, e.g. sensor_* matches sensor_temp.
+type Kinds map[fimptype.ServiceNameT]*Kind
+
+// Find returns a kind for the provided service name. Exact matches take precedence over the longest matching prefix.
+func (k Kinds) Find(name fimptype.ServiceNameT) (*Kind, bool) {
+	if kind, ok := k[name]; ok {
+		return kind, true
+	}
+
+	var (
+		found  *Kind
+		length int
+	)
+
+	for pattern, kind := range k {
+		prefix, ok := strings.CutSuffix(string(pattern), "*")
+		if !ok || !strings.HasPrefix(string(name), prefix) || len(prefix) < length {
+			continue
+		}
+
+		found, length = kind, len(prefix)
+	}
+
+	return found, found != nil
+}
+
+// DefaultKinds returns kinds of all services provided by the library, which can be built without additional dependencies.
+func DefaultKinds() Kinds {
+	return Kinds{
+		battery.Battery: {
+			Build: func(publisher adapter.ServicePublisher, spec *fimptype.Service, controller any, definition *Service) (adapter.Service, error) {
+				reporter, err := bind[battery.Reporter](spec, controller)
+				if err != nil {
+					return nil, err
+				}
+
+				return battery.NewService(publisher, &battery.Config{
+					Specification:     spec,
+					Reporter:          reporter,
+					ReportingStrategy: definition.strategy(),
+				}), nil
+			},
+			Route: battery.RouteService,
+			Task:  battery.TaskReporting,
+		},
+		chargepoint.Chargepoint: {
+			Build: func(publisher adapter.ServicePublisher, spec *fimptype.Service, controller any, definition *Service) (adapter.Service, error) {
+				c, err := bind[chargepoint.Controller](spec, controller)
+				if err != nil {
+					return nil, err
+				}
+
+				return chargepoint.NewService(publisher, &chargepoint.Config{
+					Specification:          spec,
+					Controller:             c,
+					StateReportingStrategy: definition.strategy(),
+				}), nil
+			},
+			Route:         chargepoint.RouteService,
+			Task:          chargepoint.TaskReporting,
+			ModesProperty: chargepoint.PropertySupportedChargingModes,
+		},
+		colorctrl.ColorCtrl: {
+			Build: func(publisher adapter.ServicePublisher, spec *fimptype.Service, controller any, definition *Service) (adapter.Service, error) {
+				c, err := bind[colorctrl.Controller](spec, controller)
+				if err != nil {
+					return nil, err
+				}
+
+				return colorctrl.NewService(publisher, &colorctrl.Config{
+					Specification:     spec,
+					Controller:        c,
+					ReportingStrategy: definition.strategy(),
+				}), nil
+			},
+			Route: colorctrl.RouteService,
+			Task:  colorctrl.TaskReporting,
+		},
+		devsys.DevSys: {
+			Build: func(publisher adapter.ServicePublisher, spec *fimptype.Service, controller any, _ *Service) (adapter.Service, error) {
+				c, err := bind[devsys.Controller](spec, controller)
+				if err != nil {
+					return nil, err
+				}
+
+				return devsys.NewService(publisher, &devsys.Config{
+					Specification: spec,
+					Controller:    c,
+				}), nil
+			},
+			Route: devsys.RouteService,
+		},
+		diagnostic.Diagnostic: {
+			Build: func(publisher adapter.ServicePublisher, spec *fimptype.Service, controller any, _ *Service) (adapter.Service, error) {
+				c, err := bind[diagnostic.Controller](spec, controller)
+				if err != nil {
+					return nil, err
+				}
+
+				return diagnostic.NewService(publisher, &diagnostic.Config{
+					Specification: spec,
+					Controller:    c,
+				}), nil
+			},
+			Route: diagnostic.RouteService,
+		},
+		fanctrl.FanCtrl: {
+			Build: func(publisher adapter.ServicePublisher, spec *fimptype.Service, controller any, definition *Service) (adapter.Service, error) {
+				c, err := bind[fanctrl.Controller](spec, controller)
+				if err != nil {
+					return nil, err
+				}
+
+				return fanctrl.NewService(publisher, &fanctrl.Config{
+					Specification:     spec,
+					Controller:        c,
+					ReportingStrategy: definition.strategy(),
+				}), nil
+			},
+			Route: fanctrl.RouteService,
+			Task:  fanctrl.TaskReporting,
+		},
+		mediaplayer.MediaPlayer: {
+			Build: func(publisher adapter.ServicePublisher, spec *fimptype.Service, controller any, definition *Service) (adapter.Service, error) {
+				c, err := bind[mediaplayer.Controller](spec, controller)
+				if err != nil {
+					return nil, err
+				}
+
+				return mediaplayer.NewService(publisher, &mediaplayer.Config{
+					Specification:     spec,
+					Controller:        c,
+					ReportingStrategy: definition.strategy(),
+				}), nil
+			},
+			Route: mediaplayer.RouteService,
+			Task:  mediaplayer.TaskReporting,
+		},
+		"meter_*": {
+			Build: func(publisher adapter.ServicePublisher, spec *fimptype.Service, controller any, definition *Service) (adapter.Service, error) {
+				reporter, err := bind[numericmeter.Reporter](spec, controller)
+				if err != nil {
+					return nil, err
+				}
+
+				return numericmeter.NewService(publisher, &numericmeter.Config{
+					Specification:     spec,
+					Reporter:          reporter,
+					ReportingStrategy: definition.strategy(),
+				}), nil
+			},
+			Route: numericmeter.RouteService,
+			Task:  numericmeter.TaskReporting,
+		},
+		"sensor_*": {
+			Build: func(publisher adapter.ServicePublisher, spec *fimptype.Service, controller any, definition *Service) (adapter.Service, error) {
+				reporter, err := bind[numericsensor.Reporter](spec, controller)
+				if err != nil {
+					return nil, err
+				}
+
+				return numericsensor.NewService(publisher, &numericsensor.Config{
+					Specification:     spec,
+					Reporter:          reporter,
+					ReportingStrategy: definition.strategy(),
+				}), nil
+			},
+			Route: numericsensor.RouteService,
+			Task:  numericsensor.TaskReporting,
+		},
+		ota.OTA: {
+			Build: func(publisher adapter.ServicePublisher, spec *fimptype.Service, controller any, _ *Service) (adapter.Service, error) {
+				c, err := bind[ota.Controller](spec, controller)
+				if err != nil {
+					return nil, err
+				}
+
+				return ota.NewService(publisher, &ota.Config{
+					Specification: spec,
+					Controller:    c,
+				}), nil
+			},
+			Route: ota.RouteService,
+		},
+		outbinswitch.OutBinSwitch: {
+			Build: func(publisher adapter.ServicePublisher, spec *fimptype.Service, controller any, definition *Service) (adapter.Service, error) {
+				c, err := bind[outbinswitch.Controller](spec, controller)
+				if err != nil {
+					return nil, err
+				}
+
+				return outbinswitch.NewService(publisher, &outbinswitch.Config{
+					Specification:     spec,
+					Controller:        c,
+					ReportingStrategy: definition.strategy(),
+				}), nil
+			},
+			Route: outbinswitch.RouteService,
+			Task:  outbinswitch.TaskReporting,
+		},
+		outlvlswitch.OutLvlSwitch: {
+			Build: func(publisher adapter.ServicePublisher, spec *fimptype.Service, controller any, definition *Service) (adapter.Service, error) {
+				c, err := bind[outlvlswitch.Controller](spec, controller)
+				if err != nil {
+					return nil, err
+				}
+
+				return outlvlswitch.NewService(publisher, &outlvlswitch.Config{
+					Specification:     spec,
+					Controller:        c,
+					ReportingStrategy: definition.strategy(),
+				}), nil
+			},
+			Route: outlvlswitch.RouteService,
+			Task:  outlvlswitch.TaskReporting,
+		},
+		parameters.Parameters: {
+			Build: func(publisher adapter.ServicePublisher, spec *fimptype.Service, controller any, definition *Service) (adapter.Service, error) {
+				c, err := bindParameters(spec, controller, definition)
+				if err != nil {
+					return nil, err
+				}
+
+				return parameters.NewService(publisher, &parameters.Config{
+					Specification: spec,
+					Controller:    c,
+				}), nil
+			},
+			Route: parameters.RouteService,
+		},
+		presence.SensorPresence: {
+			Build: func(publisher adapter.ServicePublisher, spec *fimptype.Service, controller any, definition *Service) (adapter.Service, error) {
+				c, err := bind[presence.Controller](spec, controller)
+				if err != nil {
+					return nil, err
+				}
+
+				return presence.NewService(publisher, &presence.Config{
+					Specification:     spec,
+					Controller:        c,
+					ReportingStrategy: definition.strategy(),
+				}), nil
+			},
+			Route: presence.RouteService,
+			Task:  presence.TaskReporting,
+		},
+		scenectrl.SceneCtrl: {
+			Build: func(publisher adapter.ServicePublisher, spec *fimptype.Service, controller any, definition *Service) (adapter.Service, error) {
+				c, err := bind[scenectrl.Controller](spec, controller)
+				if err != nil {
+					return nil, err
+				}
+
+				return scenectrl.NewService(publisher, &scenectrl.Config{
+					Specification:     spec,
+					Controller:        c,
+					ReportingStrategy: definition.strategy(),
+				}), nil
+			},
+			Route: scenectrl.RouteService,
+			Task:  scenectrl.TaskReporting,
+		},
+		thermostat.Thermostat: {
+			Build: func(publisher adapter.ServicePublisher, spec *fimptype.Service, controller any, definition *Service) (adapter.Service, error) {
+				c, err := bind[thermostat.Controller](spec, controller)
+				if err != nil {
+					return nil, err
+				}
+
+				return thermostat.NewService(publisher, &thermostat.Config{
+					Specification:     spec,
+					Controller:        c,
+					ReportingStrategy: definition.strategy(),
+				}), nil
+			},
+			Route: thermostat.RouteService,
+			Task:  thermostat.TaskReporting,
+		},
+		waterheater.WaterHeater: {
+			Build: func(publisher adapter.ServicePublisher, spec *fimptype.Service, controller any, definition *Service) (adapter.Service, error) {
+				c, err := bind[waterheater.Controller](spec, controller)
+				if err != nil {
+					return nil, err
+				}
+
+				return waterheater.NewService(publisher, &waterheater.Config{
+					Specification:     spec,
+					Controller:        c,
+					ReportingStrategy: definition.strategy(),
+				}), nil
+			},
+			Route: waterheater.RouteService,
+			Task:  waterheater.TaskReporting,
+		},
+	}
+}
+
+// bind asserts that the controller bound to a service implements the interface required by the service.
+func bind[T any](spec *fimptype.Service, controller any) (T, error) {
+	c, ok := controller.(T)
+	if !ok {
+		return c, fmt.Errorf("profile: controller bound to service %s does not implement %T", spec.Name, (*T)(nil))
+	}
+
+	return c, nil
+}
+
+// bindParameters binds the parameters controller. If the profile defines parameter specifications, they take precedence over the controller ones.
+// In such case the controller is only required to get and set parameters.
+func bindParameters(spec *fimptype.Service, controller any, definition *Service) (parameters.Controller, error) {
+	if len(definition.Parameters) == 0 {
+		return bind[parameters.Controller](spec, controller)
+	}
+
+	c, err := bind[parameterAccessor](spec, controller)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parametersController{
+		parameterAccessor: c,
+		specifications:    definition.Parameters,
+	}, nil
+}
+
+// parameterAccessor is a subset of the parameters controller required when parameter specifications are provided by a profile.
+type parameterAccessor interface {
+	// SetParameter sets a parameter.
+	SetParameter(p *parameters.Parameter) error
+	// GetParameter returns a parameter by ID.
+	GetParameter(id string) (*parameters.Parameter, error)
+}
+
+// parametersController is a parameters controller returning parameter specifications defined by a profile.
+type parametersController struct {
+	parameterAccessor
+
+	specifications []*parameters.ParameterSpecification
+}
+
+// GetParameterSpecifications returns a list of all parameter specifications/definitions.
+func (c *parametersController) GetParameterSpecifications() ([]*parameters.ParameterSpecification, error) {
+	return c.specifications, nil
+}
+
+// strategy returns the reporting strategy of the service or nil if the default one should be used.
+func (s *Service) strategy() cache.ReportingStrategy {
+	if s.Reporting == nil {
+		return nil
+	}
+
+	strategy, err := s.Reporting.Strategy()
+	if err != nil {
+		return nil
+	}
+
+	return strategy
+}
@@ -0,0 +1,266 @@
+package profile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/futurehomeno/fimpgo/fimptype"
+	"gopkg.in/yaml.v3"
+
+	"github.com/futurehomeno/cliffhanger/adapter/cache"
+	"github.com/futurehomeno/cliffhanger/adapter/service/parameters"
+)
+
+// Constants defining supported reporting strategies.
+const (
+	StrategyAlways       = "always"
+	StrategyOnChange     = "on_change"
+	StrategyAtLeastEvery = "at_least_every"
+)
+
+// Profile is a declarative definition of a device model, listing its services and their capabilities.
+type Profile struct {
+	// Name is a unique name of the profile used to assign it to things.
+	Name string `json:"name"`
+	// Connector is a name of the binding providing a connector for the thing. If empty, default connector of the factory is used.
+	Connector string `json:"connector,omitempty"`
+
+	ProductName    string            `json:"product_name,omitempty"`
+	ProductHash    string            `json:"product_hash,omitempty"`
+	ProductID      string            `json:"product_id,omitempty"`
+	ManufacturerID string            `json:"manufacturer_id,omitempty"`
+	HwVersion      string            `json:"hw_ver,omitempty"`
+	SwVersion      string            `json:"sw_ver,omitempty"`
+	CommTechnology string            `json:"comm_tech,omitempty"`
+	PowerSource    string            `json:"power_source,omitempty"`
+	WakeUpInterval string            `json:"wakeup_interval,omitempty"`
+	Security       string            `json:"security,omitempty"`
+	Groups         []string          `json:"groups,omitempty"`
+	TechProps      map[string]string `json:"tech_specific_props,omitempty"`
+
+	// ConnectivityReporting is an optional reporting strategy of the thing connectivity.
+	ConnectivityReporting *Reporting `json:"connectivity_reporting,omitempty"`
+	// Services is a list of services provided by the device.
+	Services []*Service `json:"services"`
+}
+
+// Validate checks if the profile is complete and consistent.
+func (p *Profile) Validate() error {
+	if p.Name == "" {
+		return errors.New("profile: name is missing")
+	}
+
+	if len(p.Services) == 0 {
+		return fmt.Errorf("profile %s: no services are defined", p.Name)
+	}
+
+	topics := make(map[string]bool)
+
+	for i, s := range p.Services {
+		if s == nil || s.Name == "" {
+			return fmt.Errorf("profile %s: name of service %d is missing", p.Name, i)
+		}
+
+		if s.Controller == "" {
+			return fmt.Errorf("profile %s: controller of service %s is missing", p.Name, s.Name)
+		}
+
+		key := string(s.Name) + "/" + s.Address
+		if topics[key] {
+			return fmt.Errorf("profile %s: service %s is defined more than once under the same address", p.Name, s.Name)
+		}
+
+		topics[key] = true
+
+		if s.Reporting != nil {
+			if _, err := s.Reporting.Strategy(); err != nil {
+				return fmt.Errorf("profile %s: invalid reporting of service %s: %w", p.Name, s.Name, err)
+			}
+		}
+	}
+
+	if p.ConnectivityReporting != nil {
+		if _, err := p.ConnectivityReporting.Strategy(); err != nil {
+			return fmt.Errorf("profile %s: invalid connectivity reporting: %w", p.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Service is a declarative definition of a single service of a device.
+type Service struct {
+	// Name is a FIMP name of the service, e.g. out_bin_switch or sensor_temp.
+	Name fimptype.ServiceNameT `json:"name"`
+	// Address is an optional suffix appended to the thing address, allowing a thing to provide multiple services of the same name.
+	Address string `json:"address,omitempty"`
+	// Controller is a name of the binding providing the controller or reporter implementation for the service.
+	Controller string   `json:"controller"`
+	Groups     []string `json:"groups,omitempty"`
+	// Units is a list of supported units, published under sup_units property or its service specific equivalent.
+	Units []string `json:"units,omitempty"`
+	// Modes is a list of supported modes, published under sup_modes property or its service specific equivalent.
+	Modes []string `json:"modes,omitempty"`
+	// Props is a map of arbitrary additional properties of the service.
+	Props map[string]any `json:"props,omitempty"`
+	// Interfaces is a list of optional interfaces of the service. Required interfaces are always added automatically.
+	Interfaces []fimptype.Interface `json:"interfaces,omitempty"`
+	// Parameters is a list of parameter specifications, applicable only to the parameters service.
+	Parameters []*parameters.ParameterSpecification `json:"parameters,omitempty"`
+	// Reporting is an optional reporting strategy of the service. If empty, the default strategy of the service is used.
+	Reporting *Reporting `json:"reporting,omitempty"`
+}
+
+// Reporting is a declarative definition of a reporting strategy.
+type Reporting struct {
+	// Type is a type of the strategy, one of always, on_change and at_least_every.
+	Type string `json:"strategy"`
+	// Interval is a maximum interval between reports, applicable only to the at_least_every strategy.
+	Interval Duration `json:"interval,omitempty"`
+}
+
+// Strategy returns the reporting strategy defined by the configuration.
+func (r *Reporting) Strategy() (cache.ReportingStrategy, error) {
+	switch r.Type {
+	case StrategyAlways:
+		return cache.ReportAlways(), nil
+	case StrategyOnChange:
+		return cache.ReportOnChangeOnly(), nil
+	case StrategyAtLeastEvery:
+		if r.Interval <= 0 {
+			return nil, fmt.Errorf("interval is required for %s strategy", StrategyAtLeastEvery)
+		}
+
+		return cache.ReportAtLeastEvery(time.Duration(r.Interval)), nil
+	default:
+		return nil, fmt.Errorf("unsupported reporting strategy: %s", r.Type)
+	}
+}
+
+// Duration is a duration which can be unmarshalled either from a string, e.g. 1h30m, or from a number of seconds.
+type Duration time.Duration
+
+// MarshalJSON marshals the duration into a string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON unmarshals the duration from a string or a number of seconds.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var seconds float64
+
+	if err := json.Unmarshal(data, &seconds); err == nil {
+		*d = Duration(seconds * float64(time.Second))
+
+		return nil
+	}
+
+	var s string
+
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid duration: %s", string(data))
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration: %w", err)
+	}
+
+	*d = Duration(parsed)
+
+	return nil
+}
+
+// ParseJSON parses and validates a profile from its JSON definition.
+func ParseJSON(data []byte) (*Profile, error) {
+	p := &Profile{}
+
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("profile: failed to unmarshal JSON definition: %w", err)
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// ParseYAML parses and validates a profile from its YAML definition.
+// YAML definition follows exactly the same structure and naming as the JSON one.
+func ParseYAML(data []byte) (*Profile, error) {
+	var raw any
+
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("profile: failed to unmarshal YAML definition: %w", err)
+	}
+
+	// Converting the document to JSON ensures both formats share the same field names and unmarshalling rules.
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("profile: failed to convert YAML definition: %w", err)
+	}
+
+	return ParseJSON(data)
+}
+
+// Load loads a profile from a file. Format of the file is determined by its extension.
+func Load(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("profile: failed to read file %s: %w", path, err)
+	}
+
+	var p *Profile
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		p, err = ParseJSON(data)
+	case ".yaml", ".yml":
+		p, err = ParseYAML(data)
+	default:
+		return nil, fmt.Errorf("profile: unsupported format of file %s", path)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("profile: failed to parse file %s: %w", path, err)
+	}
+
+	return p, nil
+}
+
+// LoadDir loads all profiles from JSON and YAML files found in the provided directory.
+func LoadDir(dir string) ([]*Profile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("profile: failed to read directory %s: %w", dir, err)
+	}
+
+	var profiles []*Profile
+
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+
+		switch strings.ToLower(filepath.Ext(e.Name())) {
+		case ".json", ".yaml", ".yml":
+		default:
+			continue
+		}
+
+		p, err := Load(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		profiles = append(profiles, p)
+	}
+
+	return profiles, nil
+}
@@ -0,0 +1,114 @@
+package profile_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/futurehomeno/cliffhanger/adapter/profile"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	fromJSON, err := profile.Load("./testdata/smart_plug.json")
+	assert.NoError(t, err)
+
+	fromYAML, err := profile.Load("./testdata/smart_plug.yaml")
+	assert.NoError(t, err)
+
+	assert.Equal(t, fromJSON, fromYAML)
+
+	assert.Equal(t, "smart_plug", fromJSON.Name)
+	assert.Len(t, fromJSON.Services, 3)
+	assert.Equal(t, []string{"C"}, fromJSON.Services[1].Units)
+	assert.Equal(t, profile.Duration(15*time.Minute), fromJSON.Services[1].Reporting.Interval)
+	assert.Equal(t, profile.Duration(30*time.Minute), fromJSON.ConnectivityReporting.Interval)
+	assert.Equal(t, "led", fromJSON.Services[2].Parameters[0].ID)
+}
+
+func TestLoadDir(t *testing.T) {
+	t.Parallel()
+
+	profiles, err := profile.LoadDir("./testdata")
+	assert.NoError(t, err)
+	assert.Len(t, profiles, 2)
+
+	_, err = profile.LoadDir("./testdata/missing")
+	assert.Error(t, err)
+}
+
+func TestParse_Errors(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name string
+		json string
+	}{
+		{
+			name: "malformed definition",
+			json: `{"name":`,
+		},
+		{
+			name: "missing name",
+			json: `{"services":[{"name":"out_bin_switch","controller":"relay"}]}`,
+		},
+		{
+			name: "missing services",
+			json: `{"name":"test"}`,
+		},
+		{
+			name: "missing controller",
+			json: `{"name":"test","services":[{"name":"out_bin_switch"}]}`,
+		},
+		{
+			name: "duplicated service",
+			json: `{"name":"test","services":[{"name":"out_bin_switch","controller":"relay"},{"name":"out_bin_switch","controller":"relay"}]}`,
+		},
+		{
+			name: "unsupported reporting strategy",
+			json: `{"name":"test","services":[{"name":"out_bin_switch","controller":"relay","reporting":{"strategy":"sometimes"}}]}`,
+		},
+		{
+			name: "missing reporting interval",
+			json: `{"name":"test","services":[{"name":"out_bin_switch","controller":"relay","reporting":{"strategy":"at_least_every"}}]}`,
+		},
+		{
+			name: "invalid reporting interval",
+			json: `{"name":"test","services":[{"name":"out_bin_switch","controller":"relay","reporting":{"strategy":"at_least_every","interval":"often"}}]}`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := profile.ParseJSON([]byte(tc.json))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseYAML_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := profile.ParseYAML([]byte("name: [test"))
+	assert.Error(t, err)
+
+	_, err = profile.ParseYAML([]byte("name: test"))
+	assert.Error(t, err)
+}
+
+func TestLoad_UnsupportedFormat(t *testing.T) {
+	t.Parallel()
+
+	path := t.TempDir() + "/profile.xml"
+
+	err := os.WriteFile(path, []byte("<profile/>"), 0o600)
+	assert.NoError(t, err)
+
+	_, err = profile.Load(path)
+	assert.Error(t, err)
+}
@@ -0,0 +1,277 @@
+package adapter
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/futurehomeno/cliffhanger/event"
+	"github.com/futurehomeno/cliffhanger/task"
+)
+
+// defaultReconcilerGracePeriod is a default period after which a thing vanished from the source is excluded.
+const defaultReconcilerGracePeriod = 24 * time.Hour
+
+// ReconciliationDecisionT represents a decision taken by the reconciler about a single thing.
+type ReconciliationDecisionT string
+
+const (
+	// ReconciliationAdded means that a new thing was found in the source and created.
+	ReconciliationAdded ReconciliationDecisionT = "added"
+	// ReconciliationLeft means that a thing vanished from the source and was marked as left.
+	ReconciliationLeft ReconciliationDecisionT = "left"
+	// ReconciliationReturned means that a thing marked as left reappeared in the source before its grace period ended.
+	ReconciliationReturned ReconciliationDecisionT = "returned"
+	// ReconciliationRemoved means that a thing marked as left was excluded after its grace period ended.
+	ReconciliationRemoved ReconciliationDecisionT = "removed"
+)
+
+// ReconciliationDecision is a payload of an event emitted by the reconciler for every decision taken about a thing.
+type ReconciliationDecision struct {
+	ID       string
+	Address  string
+	Decision ReconciliationDecisionT
+}
+
+// SeedSource is an interface representing a source of things, e.g. a remote device list of a cloud integration.
+type SeedSource interface {
+	// Seeds returns seeds of all things which are currently available in the source.
+	Seeds() (ThingSeeds, error)
+}
+
+// SeedSourceFn is an adapter allowing usage of anonymous function as a seed source.
+type SeedSourceFn func() (ThingSeeds, error)
+
+// Seeds returns seeds of all things which are currently available in the source.
+func (f SeedSourceFn) Seeds() (ThingSeeds, error) {
+	return f()
+}
+
+// ReconcilerConfig represents a configuration of the thing reconciler.
+type ReconcilerConfig struct {
+	// Source is a source of things to be reconciled with the adapter.
+	Source SeedSource
+	// GracePeriod is a period after which a thing vanished from the source is excluded from the adapter.
+	GracePeriod time.Duration
+	// EventManager is an optional event manager used to emit events for every decision taken by the reconciler.
+	EventManager event.Manager
+}
+
+// withDefaults sets default values for all options which were not provided.
+func (c *ReconcilerConfig) withDefaults() *ReconcilerConfig {
+	if c.GracePeriod == 0 {
+		c.GracePeriod = defaultReconcilerGracePeriod
+	}
+
+	return c
+}
+
+// Reconciler is a service periodically reconciling things of the adapter with a seed source.
+// New things are created, while vanished ones are marked as left and set down, until they are excluded after a grace period.
+// Information about left things is kept in memory only, so the grace period starts anew after restart of the application.
+type Reconciler interface {
+	// IsLeft returns true if the thing with the provided ID vanished from the source and awaits exclusion.
+	IsLeft(id string) bool
+	// Connector wraps the provided connector of a thing, so its connectivity details reflect whether it vanished from the source.
+	Connector(id string, connector Connector) Connector
+
+	// reconcile fetches seeds from the source and reconciles them with things of the adapter.
+	reconcile(adapter Adapter) error
+}
+
+// NewReconciler creates new instance of a thing reconciler.
+func NewReconciler(cfg *ReconcilerConfig) Reconciler {
+	if cfg == nil {
+		cfg = &ReconcilerConfig{}
+	}
+
+	return &reconciler{
+		cfg:  cfg.withDefaults(),
+		left: make(map[string]time.Time),
+	}
+}
+
+// reconciler is a private implementation of the reconciler service.
+type reconciler struct {
+	cfg  *ReconcilerConfig
+	lock sync.RWMutex
+	left map[string]time.Time
+}
+
+// IsLeft returns true if the thing with the provided ID vanished from the source and awaits exclusion.
+func (r *reconciler) IsLeft(id string) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	_, ok := r.left[id]
+
+	return ok
+}
+
+// Connector wraps the provided connector of a thing, so its connectivity details reflect whether it vanished from the source.
+func (r *reconciler) Connector(id string, connector Connector) Connector {
+	return overrideConnector(connector, &connectivityOverride{
+		isDown:           func() bool { return r.IsLeft(id) },
+		operationability: OperationabilityLeft,
+	})
+}
+
+// reconcile fetches seeds from the source and reconciles them with things of the adapter.
+func (r *reconciler) reconcile(adapter Adapter) error {
+	if r.cfg.Source == nil {
+		return errors.New("reconciler: seed source is not configured")
+	}
+
+	seeds, err := r.cfg.Source.Seeds()
+	if err != nil {
+		return fmt.Errorf("reconciler: failed to get seeds from the source: %w", err)
+	}
+
+	var errs []error
+
+	for _, seed := range seeds {
+		if _, ok := adapter.ExchangeID(seed.ID); ok {
+			r.restore(adapter, seed.ID)
+
+			continue
+		}
+
+		if err := adapter.CreateThing(seed); err != nil {
+			errs = append(errs, fmt.Errorf("reconciler: failed to create thing with ID %s: %w", seed.ID, err))
+
+			continue
+		}
+
+		address, _ := adapter.ExchangeID(seed.ID)
+
+		r.publish(seed.ID, address, ReconciliationAdded)
+	}
+
+	existing := make(map[string]bool)
+
+	for _, t := range adapter.Things() {
+		id, ok := adapter.ExchangeAddress(t.Address())
+		if !ok {
+			continue
+		}
+
+		existing[id] = true
+
+		if seeds.Contains(id) {
+			continue
+		}
+
+		if err := r.vanish(adapter, t, id); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for id := range r.left {
+		if !existing[id] {
+			delete(r.left, id)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// restore unmarks a thing which reappeared in the source and reports its actual connectivity.
+func (r *reconciler) restore(adapter Adapter, id string) {
+	r.lock.Lock()
+	_, ok := r.left[id]
+	delete(r.left, id)
+	r.lock.Unlock()
+
+	if !ok {
+		return
+	}
+
+	address, _ := adapter.ExchangeID(id)
+
+	if t := adapter.ThingByAddress(address); t != nil {
+		if _, err := t.SendConnectivityReport(true); err != nil {
+			log.WithError(err).WithField("address", address).Errorf("reconciler: failed to send connectivity report")
+		}
+	}
+
+	r.publish(id, address, ReconciliationReturned)
+}
+
+// vanish marks a thing missing in the source as left, or excludes it if its grace period has ended.
+func (r *reconciler) vanish(adapter Adapter, t Thing, id string) error {
+	since, ok := r.markLeft(id)
+	if !ok {
+		if _, err := t.SendConnectivityReport(true); err != nil {
+			log.WithError(err).WithField("address", t.Address()).Errorf("reconciler: failed to send connectivity report")
+		}
+
+		r.publish(id, t.Address(), ReconciliationLeft)
+
+		return nil
+	}
+
+	if time.Since(since) < r.cfg.GracePeriod {
+		return nil
+	}
+
+	if err := adapter.DestroyThingByID(id); err != nil {
+		return fmt.Errorf("reconciler: failed to destroy thing with ID %s: %w", id, err)
+	}
+
+	r.lock.Lock()
+	delete(r.left, id)
+	r.lock.Unlock()
+
+	r.publish(id, t.Address(), ReconciliationRemoved)
+
+	return nil
+}
+
+// markLeft marks a thing as left if it was not marked yet. Returns the time since which the thing is left and true if it was already marked.
+func (r *reconciler) markLeft(id string) (time.Time, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	since, ok := r.left[id]
+	if !ok {
+		r.left[id] = time.Now()
+	}
+
+	return since, ok
+}
+
+// publish emits an event about the decision taken by the reconciler, if the event manager is configured.
+func (r *reconciler) publish(id, address string, decision ReconciliationDecisionT) {
+	log.WithField("id", id).WithField("address", address).Infof("reconciler: thing %s", decision)
+
+	if r.cfg.EventManager == nil {
+		return
+	}
+
+	r.cfg.EventManager.Publish(NewReconciliationEvent(address, &ReconciliationDecision{
+		ID:       id,
+		Address:  address,
+		Decision: decision,
+	}))
+}
+
+// TaskReconciler creates a task periodically reconciling things of the adapter with the seed source of the reconciler.
+func TaskReconciler(adapter Adapter, reconciler Reconciler, interval time.Duration, voters ...task.Voter) *task.Task {
+	voters = append(voters, IsInitialized(adapter))
+
+	return task.New(handleReconciler(adapter, reconciler), interval, voters...)
+}
+
+func handleReconciler(adapter Adapter, reconciler Reconciler) func() {
+	return func() {
+		if err := reconciler.reconcile(adapter); err != nil {
+			log.WithError(err).Errorf("failed to reconcile things")
+		}
+	}
+}
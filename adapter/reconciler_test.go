@@ -0,0 +1,211 @@
+package adapter_test
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/futurehomeno/fimpgo"
+	"github.com/futurehomeno/fimpgo/fimptype"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/event"
+	"github.com/futurehomeno/cliffhanger/router"
+	"github.com/futurehomeno/cliffhanger/task"
+	adapterhelper "github.com/futurehomeno/cliffhanger/test/helper/adapter"
+	mockedadapter "github.com/futurehomeno/cliffhanger/test/mocks/adapter"
+	"github.com/futurehomeno/cliffhanger/test/suite"
+)
+
+func TestTaskReconciler(t *testing.T) { //nolint:paralleltest
+	source := &testSeedSource{}
+
+	var events chan event.Event
+
+	s := &suite.Suite{
+		Cases: []*suite.Case{
+			{
+				Name:     "reconciler adds new things and excludes vanished ones after grace period",
+				TearDown: adapterhelper.TearDownAdapter(testAdapterWorkDir),
+				Setup:    setupReconciler(source, time.Second, &events),
+				Nodes: []*suite.Node{
+					{
+						Name:          "new thing is added",
+						InitCallbacks: []suite.Callback{source.set("B", "C", "D")},
+						Expectations: []*suite.Expectation{
+							expectInclusionReport("1"),
+						},
+					},
+					{
+						Name:          "vanished thing is marked as left and set down",
+						InitCallbacks: []suite.Callback{source.set("B", "D")},
+						Timeout:       500 * time.Millisecond,
+						Expectations: []*suite.Expectation{
+							expectLeftNodeReport(testThingAddressC).ExactlyOnce(),
+							suite.NewExpectation().
+								ExpectTopic(testAdapterEvtTopic).
+								ExpectType(adapter.EvtThingExclusionReport).
+								ExpectService(testAdapterName).
+								Never(),
+						},
+					},
+					{
+						Name:    "vanished thing is excluded after grace period",
+						Timeout: 2 * time.Second,
+						Expectations: []*suite.Expectation{
+							suite.ExpectObject(testAdapterEvtTopic, adapter.EvtThingExclusionReport, testAdapterName, fimptype.ThingExclusionReport{Address: testThingAddressC}),
+						},
+					},
+					{
+						Name: "events are emitted for every decision",
+						InitCallbacks: []suite.Callback{
+							expectDecisions(&events,
+								adapter.ReconciliationDecision{ID: "D", Address: "1", Decision: adapter.ReconciliationAdded},
+								adapter.ReconciliationDecision{ID: "C", Address: testThingAddressC, Decision: adapter.ReconciliationLeft},
+								adapter.ReconciliationDecision{ID: "C", Address: testThingAddressC, Decision: adapter.ReconciliationRemoved},
+							),
+						},
+					},
+				},
+			},
+			{
+				Name:     "reconciler restores thing reappearing within grace period",
+				TearDown: adapterhelper.TearDownAdapter(testAdapterWorkDir),
+				Setup:    setupReconciler(source, time.Hour, &events),
+				Nodes: []*suite.Node{
+					{
+						Name:          "vanished thing is marked as left",
+						InitCallbacks: []suite.Callback{source.set("B")},
+						Expectations: []*suite.Expectation{
+							expectLeftNodeReport(testThingAddressC).ExactlyOnce(),
+						},
+					},
+					{
+						Name:          "reappeared thing is reported as up",
+						InitCallbacks: []suite.Callback{source.set("B", "C")},
+						Expectations: []*suite.Expectation{
+							expectNodeReportWithStatus(testThingAddressC, adapter.ConnStatusUp).ExactlyOnce(),
+						},
+					},
+					{
+						Name: "events are emitted for every decision",
+						InitCallbacks: []suite.Callback{
+							expectDecisions(&events,
+								adapter.ReconciliationDecision{ID: "C", Address: testThingAddressC, Decision: adapter.ReconciliationLeft},
+								adapter.ReconciliationDecision{ID: "C", Address: testThingAddressC, Decision: adapter.ReconciliationReturned},
+							),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	s.Run(t)
+}
+
+func setupReconciler(source *testSeedSource, gracePeriod time.Duration, events *chan event.Event) suite.BaseSetup {
+	return func(t *testing.T, mqtt *fimpgo.MqttTransport) ([]*router.Routing, []*task.Task, []suite.Mock) {
+		t.Helper()
+
+		source.set("B", "C")(t)
+
+		eventManager := event.NewManager()
+		*events = eventManager.Subscribe("reconciler_test", 10, event.WaitForClass(adapter.EventClassReconciliation))
+
+		reconciler := adapter.NewReconciler(&adapter.ReconcilerConfig{
+			Source:       source,
+			GracePeriod:  gracePeriod,
+			EventManager: eventManager,
+		})
+
+		factory := adapterhelper.FactoryHelper(func(_ adapter.Adapter, publisher adapter.Publisher, thingState adapter.ThingState) (adapter.Thing, error) {
+			return adapter.NewThing(publisher, thingState, &adapter.ThingConfig{
+				InclusionReport: &fimptype.ThingInclusionReport{Address: thingState.Address()},
+				Connector:       reconciler.Connector(thingState.ID(), mockedadapter.NewDefaultConnector(t)),
+			}), nil
+		})
+
+		seeds := adapter.ThingSeeds{
+			{ID: "B", CustomAddress: testThingAddressB},
+			{ID: "C", CustomAddress: testThingAddressC},
+		}
+
+		ad := adapterhelper.PrepareSeededAdapter(t, testAdapterWorkDir, mqtt, factory, seeds)
+
+		return nil, []*task.Task{adapter.TaskReconciler(ad, reconciler, reportingInterval)}, nil
+	}
+}
+
+func expectLeftNodeReport(address string) *suite.Expectation {
+	return suite.NewExpectation().
+		ExpectTopic(testAdapterEvtTopic).
+		ExpectType(adapter.EvtNetworkNodeReport).
+		ExpectService(testAdapterName).
+		Expect(router.MessageVoterFn(func(m *fimpgo.Message) bool {
+			report := &adapter.ConnectivityReport{}
+
+			if err := m.Payload.GetObjectValue(report); err != nil {
+				return false
+			}
+
+			return report.Address == address &&
+				report.ConnectivityDetails != nil &&
+				report.ConnStatus == adapter.ConnStatusDown &&
+				slices.Contains(report.Operationability, adapter.OperationabilityLeft)
+		}))
+}
+
+func expectDecisions(events *chan event.Event, decisions ...adapter.ReconciliationDecision) suite.Callback {
+	return func(t *testing.T) {
+		t.Helper()
+
+		var actual []adapter.ReconciliationDecision
+
+		for len(*events) > 0 {
+			e := <-*events
+
+			thingEvent, ok := e.(adapter.ThingEvent)
+			assert.True(t, ok)
+
+			payload, ok := e.(event.EventWithPayload).Payload().(*adapter.ReconciliationDecision) //nolint:forcetypeassert
+			assert.True(t, ok)
+			assert.Equal(t, payload.Address, thingEvent.Address())
+
+			actual = append(actual, *payload)
+		}
+
+		assert.Equal(t, decisions, actual)
+	}
+}
+
+type testSeedSource struct {
+	lock sync.Mutex
+	ids  []string
+}
+
+func (s *testSeedSource) set(ids ...string) suite.Callback {
+	return func(t *testing.T) {
+		t.Helper()
+
+		s.lock.Lock()
+		defer s.lock.Unlock()
+
+		s.ids = ids
+	}
+}
+
+func (s *testSeedSource) Seeds() (adapter.ThingSeeds, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var seeds adapter.ThingSeeds
+
+	for _, id := range s.ids {
+		seeds = append(seeds, &adapter.ThingSeed{ID: id})
+	}
+
+	return seeds, nil
+}
@@ -0,0 +1,181 @@
+package adapter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/futurehomeno/fimpgo"
+	"github.com/futurehomeno/fimpgo/fimptype"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/adapter/cache"
+	"github.com/futurehomeno/cliffhanger/adapter/service/outbinswitch"
+	"github.com/futurehomeno/cliffhanger/event"
+	"github.com/futurehomeno/cliffhanger/router"
+	"github.com/futurehomeno/cliffhanger/task"
+	adapterhelper "github.com/futurehomeno/cliffhanger/test/helper/adapter"
+	mockedadapter "github.com/futurehomeno/cliffhanger/test/mocks/adapter"
+	mockedoutbinswitch "github.com/futurehomeno/cliffhanger/test/mocks/adapter/service/outbinswitch"
+	"github.com/futurehomeno/cliffhanger/test/suite"
+)
+
+const (
+	testServiceCmdTopic = "pt:j1/mt:cmd/rt:dev/rn:test_adapter/ad:1/sv:out_bin_switch/ad:2"
+	testServiceEvtTopic = "pt:j1/mt:evt/rt:dev/rn:test_adapter/ad:1/sv:out_bin_switch/ad:2"
+)
+
+func TestRouteAdapter_ReportingStrategy(t *testing.T) { //nolint:paralleltest
+	var ad adapter.Adapter
+
+	s := &suite.Suite{
+		Cases: []*suite.Case{
+			{
+				Name:     "reporting strategy of a service is swapped live",
+				TearDown: adapterhelper.TearDownAdapter(testAdapterWorkDir),
+				Setup:    setupReportingStrategy(&ad, false),
+				Nodes: []*suite.Node{
+					{
+						Name:    "default strategy reports only on change",
+						Timeout: 500 * time.Millisecond,
+						Expectations: []*suite.Expectation{
+							suite.ExpectBool(testServiceEvtTopic, outbinswitch.EvtBinaryReport, outbinswitch.OutBinSwitch, true).ExactlyOnce(),
+						},
+					},
+					{
+						Name: "override strategy to always",
+						Command: suite.ObjectMessage(testServiceCmdTopic, adapter.CmdReportingSetStrategy, outbinswitch.OutBinSwitch, &cache.ReportingStrategySpec{
+							Type: cache.ReportingStrategyAlways,
+						}),
+						Expectations: []*suite.Expectation{
+							suite.ExpectObject(testServiceEvtTopic, adapter.EvtReportingStrategyReport, outbinswitch.OutBinSwitch, &cache.ReportingStrategySpec{
+								Type: cache.ReportingStrategyAlways,
+							}),
+							suite.ExpectBool(testServiceEvtTopic, outbinswitch.EvtBinaryReport, outbinswitch.OutBinSwitch, true).AtLeastOnce(),
+						},
+					},
+					{
+						Name: "override is persisted",
+						InitCallbacks: []suite.Callback{
+							func(t *testing.T) {
+								t.Helper()
+
+								rt, ok := ad.ThingByAddress(testThingAddressB).(adapter.ReportingThing)
+								assert.True(t, ok)
+								assert.Equal(t, &cache.ReportingStrategySpec{Type: cache.ReportingStrategyAlways}, rt.ReportingStrategy(testServiceCmdTopic, ""))
+							},
+						},
+						Command: suite.NullMessage(testServiceCmdTopic, adapter.CmdReportingGetStrategy, outbinswitch.OutBinSwitch),
+						Expectations: []*suite.Expectation{
+							suite.ExpectObject(testServiceEvtTopic, adapter.EvtReportingStrategyReport, outbinswitch.OutBinSwitch, &cache.ReportingStrategySpec{
+								Type: cache.ReportingStrategyAlways,
+							}),
+						},
+					},
+					{
+						Name: "restore default strategy",
+						Command: suite.ObjectMessage(testServiceCmdTopic, adapter.CmdReportingSetStrategy, outbinswitch.OutBinSwitch, &cache.ReportingStrategySpec{
+							Type: cache.ReportingStrategyDefault,
+						}),
+						Expectations: []*suite.Expectation{
+							suite.ExpectObject(testServiceEvtTopic, adapter.EvtReportingStrategyReport, outbinswitch.OutBinSwitch, &cache.ReportingStrategySpec{
+								Type: cache.ReportingStrategyDefault,
+							}),
+						},
+					},
+					{
+						Name:    "default strategy does not report unchanged value",
+						Timeout: 500 * time.Millisecond,
+						Expectations: []*suite.Expectation{
+							suite.ExpectBool(testServiceEvtTopic, outbinswitch.EvtBinaryReport, outbinswitch.OutBinSwitch, true).Never(),
+						},
+					},
+					{
+						Name: "event override unsupported by the service responds with error",
+						Command: suite.ObjectMessage(testServiceCmdTopic, adapter.CmdReportingSetStrategy, outbinswitch.OutBinSwitch, &cache.ReportingStrategySpec{
+							Type:  cache.ReportingStrategyAlways,
+							Event: outbinswitch.EvtBinaryReport,
+						}),
+						Expectations: []*suite.Expectation{
+							suite.ExpectError(testServiceEvtTopic, outbinswitch.OutBinSwitch).ExactlyOnce(),
+						},
+					},
+					{
+						Name: "invalid strategy responds with error",
+						Command: suite.ObjectMessage(testServiceCmdTopic, adapter.CmdReportingSetStrategy, outbinswitch.OutBinSwitch, &cache.ReportingStrategySpec{
+							Type: cache.ReportingStrategyDeadband,
+						}),
+						Expectations: []*suite.Expectation{
+							suite.ExpectError(testServiceEvtTopic, outbinswitch.OutBinSwitch).ExactlyOnce(),
+						},
+					},
+				},
+			},
+			{
+				Name:     "persisted reporting strategy is restored after restart",
+				TearDown: adapterhelper.TearDownAdapter(testAdapterWorkDir),
+				Setup:    setupReportingStrategy(&ad, true),
+				Nodes: []*suite.Node{
+					{
+						Name: "first report",
+						Expectations: []*suite.Expectation{
+							suite.ExpectBool(testServiceEvtTopic, outbinswitch.EvtBinaryReport, outbinswitch.OutBinSwitch, true).AtLeastOnce(),
+						},
+					},
+					{
+						Name: "unchanged value is reported again",
+						Expectations: []*suite.Expectation{
+							suite.ExpectBool(testServiceEvtTopic, outbinswitch.EvtBinaryReport, outbinswitch.OutBinSwitch, true).AtLeastOnce(),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	s.Run(t)
+}
+
+func setupReportingStrategy(ad *adapter.Adapter, restart bool) suite.BaseSetup {
+	return func(t *testing.T, mqtt *fimpgo.MqttTransport) ([]*router.Routing, []*task.Task, []suite.Mock) {
+		t.Helper()
+
+		controller := mockedoutbinswitch.NewController(t).MockedBinarySwitchBinaryReport(true, nil, false)
+
+		factory := adapterhelper.FactoryHelper(func(a adapter.Adapter, publisher adapter.Publisher, thingState adapter.ThingState) (adapter.Thing, error) {
+			return adapter.NewThing(publisher, thingState, &adapter.ThingConfig{
+				InclusionReport: &fimptype.ThingInclusionReport{Address: thingState.Address()},
+				Connector:       mockedadapter.NewDefaultConnector(t),
+			}, outbinswitch.NewService(publisher, &outbinswitch.Config{
+				Specification: outbinswitch.Specification("test_adapter", a.Address(), thingState.Address(), nil),
+				Controller:    controller,
+			})), nil
+		})
+
+		seeds := adapter.ThingSeeds{{ID: "B", CustomAddress: testThingAddressB}}
+
+		if restart {
+			previous := adapterhelper.PrepareSeededAdapter(t, testAdapterWorkDir, mqtt, factory, seeds)
+
+			rt, ok := previous.ThingByAddress(testThingAddressB).(adapter.ReportingThing)
+			if !ok {
+				t.Fatal("thing does not support reporting strategy overrides")
+			}
+
+			if err := rt.SetReportingStrategy(testServiceCmdTopic, &cache.ReportingStrategySpec{Type: cache.ReportingStrategyAlways}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		state, err := adapter.NewState(testAdapterWorkDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		*ad = adapterhelper.SeedAdapter(t, adapter.NewAdapter(mqtt, event.NewManager(), factory, state, "test_adapter", "1"), seeds)
+
+		return router.Combine(adapter.RouteAdapter(*ad), outbinswitch.RouteService(*ad)),
+			[]*task.Task{outbinswitch.TaskReporting(*ad, reportingInterval)},
+			[]suite.Mock{controller}
+	}
+}
@@ -6,32 +6,53 @@ import (
 	"github.com/futurehomeno/fimpgo"
 	"github.com/futurehomeno/fimpgo/fimptype"
 
+	"github.com/futurehomeno/cliffhanger/adapter/cache"
 	"github.com/futurehomeno/cliffhanger/router"
 )
 
 const (
-	CmdThingGetInclusionReport = "cmd.thing.get_inclusion_report"
-	EvtThingInclusionReport    = "evt.thing.inclusion_report"
-	EvtThingExclusionReport    = "evt.thing.exclusion_report"
-	CmdThingDelete             = "cmd.thing.delete"
-	CmdNetworkReset            = "cmd.network.reset"
-	EvtNetworkResetDone        = "evt.network.reset_done"
-	CmdNetworkGetNode          = "cmd.network.get_node"
-	EvtNetworkNodeReport       = "evt.network.node_report"
-	CmdNetworkGetAllNodes      = "cmd.network.get_all_nodes"
-	EvtNetworkAllNodesReport   = "evt.network.all_nodes_report"
-	CmdPingSend                = "cmd.ping.send"
-	EvtPingReport              = "evt.ping.report"
+	CmdThingGetInclusionReport  = "cmd.thing.get_inclusion_report"
+	EvtThingInclusionReport     = "evt.thing.inclusion_report"
+	EvtThingExclusionReport     = "evt.thing.exclusion_report"
+	EvtThingAddressChangeReport = "evt.thing.address_change_report"
+	CmdThingDelete              = "cmd.thing.delete"
+	CmdThingSetAlias            = "cmd.thing.set_alias"
+	CmdThingSetMetadata         = "cmd.thing.set_metadata"
+	CmdNetworkReset             = "cmd.network.reset"
+	EvtNetworkResetDone         = "evt.network.reset_done"
+	CmdNetworkGetNode           = "cmd.network.get_node"
+	EvtNetworkNodeReport        = "evt.network.node_report"
+	CmdNetworkGetAllNodes       = "cmd.network.get_all_nodes"
+	EvtNetworkAllNodesReport    = "evt.network.all_nodes_report"
+	CmdPingSend                 = "cmd.ping.send"
+	EvtPingReport               = "evt.ping.report"
+	CmdNetworkGetHealthReport   = "cmd.network.get_health_report"
+	EvtNetworkHealthReport      = "evt.network.health_report"
+	CmdNetworkGetTopology       = "cmd.network.get_topology"
+	EvtNetworkTopologyReport    = "evt.network.topology_report"
+	CmdReportingSetStrategy     = "cmd.reporting.set_strategy"
+	CmdReportingGetStrategy     = "cmd.reporting.get_strategy"
+	EvtReportingStrategyReport  = "evt.reporting.strategy_report"
+	CmdAdapterExportState       = "cmd.adapter.export_state"
+	EvtAdapterStateReport       = "evt.adapter.state_report"
+	CmdAdapterImportState       = "cmd.adapter.import_state"
+	EvtAdapterImportReport      = "evt.adapter.import_report"
+	CmdAuditGetLog              = "cmd.audit.get_log"
+	EvtAuditLogReport           = "evt.audit.log_report"
 )
 
 func RouteAdapter(adapter Adapter) []*router.Routing {
 	return []*router.Routing{
 		routeCmdThingGetInclusionReport(adapter),
 		routeCmdThingDelete(adapter),
+		routeCmdThingSetAlias(adapter),
+		routeCmdThingSetMetadata(adapter),
 		routeCmdNetworkReset(adapter),
 		routeCmdNetworkGetNode(adapter),
 		routeCmdNetworkGetAllNodes(adapter),
 		routeCmdPingSend(adapter),
+		routeCmdReportingSetStrategy(adapter),
+		routeCmdReportingGetStrategy(adapter),
 	}
 }
 
@@ -39,6 +60,7 @@ func routeCmdThingGetInclusionReport(adapter Adapter) *router.Routing {
 	return router.NewRouting(
 		handleCmdThingGetInclusionReport(adapter),
 		router.ForService(fimptype.ServiceNameT(adapter.Name())),
+		router.ForResourceAddress(adapter.Address()),
 		router.ForType(CmdThingGetInclusionReport),
 	)
 }
@@ -65,6 +87,7 @@ func routeCmdThingDelete(adapter Adapter) *router.Routing {
 	return router.NewRouting(
 		handleCmdThingDelete(adapter),
 		router.ForService(fimptype.ServiceNameT(adapter.Name())),
+		router.ForResourceAddress(adapter.Address()),
 		router.ForType(CmdThingDelete),
 	)
 }
@@ -89,10 +112,107 @@ func handleCmdThingDelete(adapter Adapter) router.MessageHandler {
 	)
 }
 
+func routeCmdThingSetAlias(adapter Adapter) *router.Routing {
+	return router.NewRouting(
+		handleCmdThingSetAlias(adapter),
+		router.ForService(fimptype.ServiceNameT(adapter.Name())),
+		router.ForResourceAddress(adapter.Address()),
+		router.ForType(CmdThingSetAlias),
+	)
+}
+
+func handleCmdThingSetAlias(adapter Adapter) router.MessageHandler {
+	return router.NewMessageHandler(
+		router.MessageProcessorFn(func(message *fimpgo.Message) (reply *fimpgo.FimpMessage, err error) {
+			value, err := message.Payload.GetStrMapValue()
+			if err != nil {
+				return nil, fmt.Errorf("provided alias has an incorrect format: %w", err)
+			}
+
+			t, err := getLabeledThing(adapter, value["address"])
+			if err != nil {
+				return nil, err
+			}
+
+			err = t.SetAlias(value["alias"])
+			if err != nil {
+				return nil, fmt.Errorf("failed to set alias: %w", err)
+			}
+
+			return nil, sendThingReports(t)
+		}),
+	)
+}
+
+func routeCmdThingSetMetadata(adapter Adapter) *router.Routing {
+	return router.NewRouting(
+		handleCmdThingSetMetadata(adapter),
+		router.ForService(fimptype.ServiceNameT(adapter.Name())),
+		router.ForResourceAddress(adapter.Address()),
+		router.ForType(CmdThingSetMetadata),
+	)
+}
+
+func handleCmdThingSetMetadata(adapter Adapter) router.MessageHandler {
+	return router.NewMessageHandler(
+		router.MessageProcessorFn(func(message *fimpgo.Message) (reply *fimpgo.FimpMessage, err error) {
+			value := &ThingMetadata{}
+
+			err = message.Payload.GetObjectValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("provided metadata has an incorrect format: %w", err)
+			}
+
+			t, err := getLabeledThing(adapter, value.Address)
+			if err != nil {
+				return nil, err
+			}
+
+			err = t.SetMetadata(value.Metadata)
+			if err != nil {
+				return nil, fmt.Errorf("failed to set metadata: %w", err)
+			}
+
+			return nil, sendThingReports(t)
+		}),
+	)
+}
+
+// getLabeledThing returns a thing under the provided address if it supports alias and metadata management.
+func getLabeledThing(adapter Adapter, address string) (LabeledThing, error) {
+	t := adapter.ThingByAddress(address)
+	if t == nil {
+		return nil, fmt.Errorf("thing not found under the provided address: %s", address)
+	}
+
+	labeled, ok := t.(LabeledThing)
+	if !ok {
+		return nil, fmt.Errorf("thing under the provided address does not support alias and metadata: %s", address)
+	}
+
+	return labeled, nil
+}
+
+// sendThingReports sends inclusion and connectivity reports of a thing after its alias or metadata were changed.
+func sendThingReports(t Thing) error {
+	_, err := t.SendInclusionReport(false)
+	if err != nil {
+		return fmt.Errorf("failed to send the inclusion report: %w", err)
+	}
+
+	_, err = t.SendConnectivityReport(true)
+	if err != nil {
+		return fmt.Errorf("failed to send the connectivity report: %w", err)
+	}
+
+	return nil
+}
+
 func routeCmdNetworkReset(adapter Adapter) *router.Routing {
 	return router.NewRouting(
 		handleCmdNetworkReset(adapter),
 		router.ForService(fimptype.ServiceNameT(adapter.Name())),
+		router.ForResourceAddress(adapter.Address()),
 		router.ForType(CmdNetworkReset),
 	)
 }
@@ -120,6 +240,7 @@ func routeCmdNetworkGetNode(adapter Adapter) *router.Routing {
 	return router.NewRouting(
 		handleCmdNetworkGetNode(adapter),
 		router.ForService(fimptype.ServiceNameT(adapter.Name())),
+		router.ForResourceAddress(adapter.Address()),
 		router.ForType(CmdNetworkGetNode),
 	)
 }
@@ -146,6 +267,7 @@ func routeCmdNetworkGetAllNodes(adapter Adapter) *router.Routing {
 	return router.NewRouting(
 		handleCmdNetworkGetAllNodes(adapter),
 		router.ForService(fimptype.ServiceNameT(adapter.Name())),
+		router.ForResourceAddress(adapter.Address()),
 		router.ForType(CmdNetworkGetAllNodes),
 	)
 }
@@ -167,6 +289,7 @@ func routeCmdPingSend(adapter Adapter) *router.Routing {
 	return router.NewRouting(
 		handleCmdPingSend(adapter),
 		router.ForService(fimptype.ServiceNameT(adapter.Name())),
+		router.ForResourceAddress(adapter.Address()),
 		router.ForType(CmdPingSend),
 	)
 }
@@ -189,6 +312,320 @@ func handleCmdPingSend(adapter Adapter) router.MessageHandler {
 	)
 }
 
+func routeCmdReportingSetStrategy(adapter Adapter) *router.Routing {
+	return router.NewRouting(
+		handleCmdReportingSetStrategy(adapter),
+		router.ForResourceType(fimptype.ResourceTypeDevice),
+		router.ForResourceName(adapter.Name()),
+		router.ForResourceAddress(adapter.Address()),
+		router.ForType(CmdReportingSetStrategy),
+	)
+}
+
+func handleCmdReportingSetStrategy(adapter Adapter) router.MessageHandler {
+	return router.NewMessageHandler(
+		router.MessageProcessorFn(func(message *fimpgo.Message) (reply *fimpgo.FimpMessage, err error) {
+			t, err := getReportingThing(adapter, message.Topic)
+			if err != nil {
+				return nil, err
+			}
+
+			spec := &cache.ReportingStrategySpec{}
+
+			err = message.Payload.GetObjectValue(spec)
+			if err != nil {
+				return nil, fmt.Errorf("provided reporting strategy has an incorrect format: %w", err)
+			}
+
+			err = t.SetReportingStrategy(message.Topic, spec)
+			if err != nil {
+				return nil, fmt.Errorf("failed to set reporting strategy: %w", err)
+			}
+
+			return newReportingStrategyReport(t, message, spec.Event), nil
+		}),
+	)
+}
+
+func routeCmdReportingGetStrategy(adapter Adapter) *router.Routing {
+	return router.NewRouting(
+		handleCmdReportingGetStrategy(adapter),
+		router.ForResourceType(fimptype.ResourceTypeDevice),
+		router.ForResourceName(adapter.Name()),
+		router.ForResourceAddress(adapter.Address()),
+		router.ForType(CmdReportingGetStrategy),
+	)
+}
+
+func handleCmdReportingGetStrategy(adapter Adapter) router.MessageHandler {
+	return router.NewMessageHandler(
+		router.MessageProcessorFn(func(message *fimpgo.Message) (reply *fimpgo.FimpMessage, err error) {
+			t, err := getReportingThing(adapter, message.Topic)
+			if err != nil {
+				return nil, err
+			}
+
+			// Optional event type limits the report to the override of a single event of the service.
+			var event string
+
+			if message.Payload.ValueType == fimptype.VTypeString {
+				event, err = message.Payload.GetStringValue()
+				if err != nil {
+					return nil, fmt.Errorf("provided event type has an incorrect format: %w", err)
+				}
+			}
+
+			return newReportingStrategyReport(t, message, event), nil
+		}),
+	)
+}
+
+// getReportingThing returns a thing owning a service under the provided topic if it supports reporting strategy overrides.
+func getReportingThing(adapter Adapter, topic string) (ReportingThing, error) {
+	t := adapter.ThingByTopic(topic)
+	if t == nil {
+		return nil, fmt.Errorf("service not found under the provided topic: %s", topic)
+	}
+
+	rt, ok := t.(ReportingThing)
+	if !ok {
+		return nil, fmt.Errorf("thing owning the service does not support reporting strategy overrides: %s", topic)
+	}
+
+	return rt, nil
+}
+
+// newReportingStrategyReport creates a report of the reporting strategy of a service addressed by the message, or of its single event.
+func newReportingStrategyReport(t ReportingThing, message *fimpgo.Message, event string) *fimpgo.FimpMessage {
+	spec := t.ReportingStrategy(message.Topic, event)
+	if spec == nil {
+		spec = &cache.ReportingStrategySpec{Type: cache.ReportingStrategyDefault, Event: event}
+	}
+
+	return fimpgo.NewObjectMessage(
+		EvtReportingStrategyReport,
+		message.Payload.Service,
+		spec,
+		nil,
+		nil,
+		message.Payload,
+	)
+}
+
+// RouteStateArchive creates routing allowing to export and import state of the adapter together with related database domains.
+func RouteStateArchive(adapter Adapter, cfg *ArchiveConfig) []*router.Routing {
+	return []*router.Routing{
+		routeCmdAdapterExportState(adapter, cfg),
+		routeCmdAdapterImportState(adapter, cfg),
+	}
+}
+
+func routeCmdAdapterExportState(adapter Adapter, cfg *ArchiveConfig) *router.Routing {
+	return router.NewRouting(
+		handleCmdAdapterExportState(adapter, cfg),
+		router.ForService(fimptype.ServiceNameT(adapter.Name())),
+		router.ForResourceAddress(adapter.Address()),
+		router.ForType(CmdAdapterExportState),
+	)
+}
+
+func handleCmdAdapterExportState(adapter Adapter, cfg *ArchiveConfig) router.MessageHandler {
+	return router.NewMessageHandler(
+		router.MessageProcessorFn(func(message *fimpgo.Message) (reply *fimpgo.FimpMessage, err error) {
+			archiver, ok := adapter.(StateArchiver)
+			if !ok {
+				return nil, fmt.Errorf("adapter does not support state export")
+			}
+
+			archive, err := archiver.ExportState(cfg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to export adapter state: %w", err)
+			}
+
+			return fimpgo.NewObjectMessage(
+				EvtAdapterStateReport,
+				fimptype.ServiceNameT(adapter.Name()),
+				archive,
+				nil,
+				nil,
+				message.Payload,
+			), nil
+		}),
+	)
+}
+
+func routeCmdAdapterImportState(adapter Adapter, cfg *ArchiveConfig) *router.Routing {
+	return router.NewRouting(
+		handleCmdAdapterImportState(adapter, cfg),
+		router.ForService(fimptype.ServiceNameT(adapter.Name())),
+		router.ForResourceAddress(adapter.Address()),
+		router.ForType(CmdAdapterImportState),
+	)
+}
+
+func handleCmdAdapterImportState(adapter Adapter, cfg *ArchiveConfig) router.MessageHandler {
+	return router.NewMessageHandler(
+		router.MessageProcessorFn(func(message *fimpgo.Message) (reply *fimpgo.FimpMessage, err error) {
+			archiver, ok := adapter.(StateArchiver)
+			if !ok {
+				return nil, fmt.Errorf("adapter does not support state import")
+			}
+
+			archive := &StateArchive{}
+
+			err = message.Payload.GetObjectValue(archive)
+			if err != nil {
+				return nil, fmt.Errorf("provided state archive has an incorrect format: %w", err)
+			}
+
+			report, err := archiver.ImportState(archive, cfg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to import adapter state: %w", err)
+			}
+
+			return fimpgo.NewObjectMessage(
+				EvtAdapterImportReport,
+				fimptype.ServiceNameT(adapter.Name()),
+				report,
+				nil,
+				nil,
+				message.Payload,
+			), nil
+		}),
+	)
+}
+
+// RouteHealthMonitor returns routing for the network health report of the adapter.
+func RouteHealthMonitor(adapter Adapter, monitor HealthMonitor) []*router.Routing {
+	return []*router.Routing{
+		routeCmdNetworkGetHealthReport(adapter, monitor),
+	}
+}
+
+func routeCmdNetworkGetHealthReport(adapter Adapter, monitor HealthMonitor) *router.Routing {
+	return router.NewRouting(
+		handleCmdNetworkGetHealthReport(adapter, monitor),
+		router.ForService(fimptype.ServiceNameT(adapter.Name())),
+		router.ForResourceAddress(adapter.Address()),
+		router.ForType(CmdNetworkGetHealthReport),
+	)
+}
+
+func handleCmdNetworkGetHealthReport(adapter Adapter, monitor HealthMonitor) router.MessageHandler {
+	return router.NewMessageHandler(
+		router.MessageProcessorFn(func(message *fimpgo.Message) (reply *fimpgo.FimpMessage, err error) {
+			return fimpgo.NewObjectMessage(
+				EvtNetworkHealthReport,
+				fimptype.ServiceNameT(adapter.Name()),
+				monitor.Report(adapter),
+				nil,
+				nil,
+				message.Payload,
+			), nil
+		}),
+	)
+}
+
+// RouteTopology returns routing for the network topology report of the adapter.
+func RouteTopology(adapter Adapter, topology Topology) []*router.Routing {
+	return []*router.Routing{
+		routeCmdNetworkGetTopology(adapter, topology),
+	}
+}
+
+func routeCmdNetworkGetTopology(adapter Adapter, topology Topology) *router.Routing {
+	return router.NewRouting(
+		handleCmdNetworkGetTopology(adapter, topology),
+		router.ForService(fimptype.ServiceNameT(adapter.Name())),
+		router.ForResourceAddress(adapter.Address()),
+		router.ForType(CmdNetworkGetTopology),
+	)
+}
+
+func handleCmdNetworkGetTopology(adapter Adapter, topology Topology) router.MessageHandler {
+	return router.NewMessageHandler(
+		router.MessageProcessorFn(func(message *fimpgo.Message) (reply *fimpgo.FimpMessage, err error) {
+			format := TopologyFormatJSON
+
+			if message.Payload.ValueType != fimptype.VTypeNull {
+				format, err = message.Payload.GetStringValue()
+				if err != nil {
+					return nil, fmt.Errorf("provided topology format has an incorrect format: %w", err)
+				}
+			}
+
+			graph := topology.Graph(adapter)
+
+			switch format {
+			case TopologyFormatJSON:
+				return fimpgo.NewObjectMessage(
+					EvtNetworkTopologyReport,
+					fimptype.ServiceNameT(adapter.Name()),
+					graph,
+					nil,
+					nil,
+					message.Payload,
+				), nil
+			case TopologyFormatDOT:
+				return fimpgo.NewStringMessage(
+					EvtNetworkTopologyReport,
+					fimptype.ServiceNameT(adapter.Name()),
+					graph.DOT(),
+					nil,
+					nil,
+					message.Payload,
+				), nil
+			default:
+				return nil, fmt.Errorf("unsupported topology format: %s", format)
+			}
+		}),
+	)
+}
+
+// RouteAuditLog returns routing for querying the command audit log of the adapter.
+func RouteAuditLog(adapter Adapter, auditLog AuditLog) []*router.Routing {
+	return []*router.Routing{
+		routeCmdAuditGetLog(adapter, auditLog),
+	}
+}
+
+func routeCmdAuditGetLog(adapter Adapter, auditLog AuditLog) *router.Routing {
+	return router.NewRouting(
+		handleCmdAuditGetLog(adapter, auditLog),
+		router.ForService(fimptype.ServiceNameT(adapter.Name())),
+		router.ForResourceAddress(adapter.Address()),
+		router.ForType(CmdAuditGetLog),
+	)
+}
+
+func handleCmdAuditGetLog(adapter Adapter, auditLog AuditLog) router.MessageHandler {
+	return router.NewMessageHandler(
+		router.MessageProcessorFn(func(message *fimpgo.Message) (*fimpgo.FimpMessage, error) {
+			filter := &AuditFilter{}
+
+			if message.Payload.ValueType != fimptype.VTypeNull {
+				if err := message.Payload.GetObjectValue(filter); err != nil {
+					return nil, fmt.Errorf("provided audit filter has an incorrect format: %w", err)
+				}
+			}
+
+			entries, err := auditLog.Query(filter)
+			if err != nil {
+				return nil, err
+			}
+
+			return fimpgo.NewObjectMessage(
+				EvtAuditLogReport,
+				fimptype.ServiceNameT(adapter.Name()),
+				entries,
+				nil,
+				nil,
+				message.Payload,
+			), nil
+		}),
+	)
+}
+
 func getThingByMessage(adapter Adapter, message *fimpgo.Message) (Thing, error) {
 	address, err := message.Payload.GetStringValue()
 	if err != nil {
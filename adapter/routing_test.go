@@ -1,6 +1,7 @@
 package adapter_test
 
 import (
+	"maps"
 	"testing"
 
 	"github.com/futurehomeno/fimpgo"
@@ -144,6 +145,77 @@ func TestRouteAdapter_NetworkGetAllNodes(t *testing.T) { //nolint:paralleltest
 	s.Run(t)
 }
 
+func TestRouteAdapter_ThingLabels(t *testing.T) { //nolint:paralleltest
+	s := &suite.Suite{
+		Cases: []*suite.Case{
+			{
+				Name:     "cmd.thing.set_alias and cmd.thing.set_metadata are reflected in reports",
+				TearDown: adapterhelper.TearDownAdapter(testAdapterWorkDir),
+				Setup:    setupAdapterWithTwoThings(),
+				Nodes: []*suite.Node{
+					{
+						Name: "set alias of thing B",
+						Command: suite.StringMapMessage(testAdapterCmdTopic, adapter.CmdThingSetAlias, testAdapterName, map[string]string{
+							"address": testThingAddressB,
+							"alias":   "Kitchen plug",
+						}),
+						Expectations: []*suite.Expectation{
+							expectLabeledInclusionReport(testThingAddressB, "Kitchen plug", nil),
+							expectLabeledNodeReport(testThingAddressB, "Kitchen plug", nil),
+						},
+					},
+					{
+						Name: "set metadata of thing B",
+						Command: suite.ObjectMessage(testAdapterCmdTopic, adapter.CmdThingSetMetadata, testAdapterName, &adapter.ThingMetadata{
+							Address:  testThingAddressB,
+							Metadata: map[string]string{"location": "kitchen", "installer": "acme"},
+						}),
+						Expectations: []*suite.Expectation{
+							expectLabeledInclusionReport(testThingAddressB, "Kitchen plug", map[string]string{"location": "kitchen", "installer": "acme"}),
+							expectLabeledNodeReport(testThingAddressB, "Kitchen plug", map[string]string{"location": "kitchen", "installer": "acme"}),
+						},
+					},
+					{
+						Name:    "labels are kept in subsequent reports",
+						Command: suite.StringMessage(testAdapterCmdTopic, adapter.CmdNetworkGetNode, testAdapterName, testThingAddressB),
+						Expectations: []*suite.Expectation{
+							expectLabeledNodeReport(testThingAddressB, "Kitchen plug", map[string]string{"location": "kitchen", "installer": "acme"}),
+						},
+					},
+					{
+						Name: "reset alias of thing B",
+						Command: suite.StringMapMessage(testAdapterCmdTopic, adapter.CmdThingSetAlias, testAdapterName, map[string]string{
+							"address": testThingAddressB,
+						}),
+						Expectations: []*suite.Expectation{
+							expectLabeledNodeReport(testThingAddressB, "", map[string]string{"location": "kitchen", "installer": "acme"}),
+						},
+					},
+					{
+						Name: "unknown address responds with error",
+						Command: suite.StringMapMessage(testAdapterCmdTopic, adapter.CmdThingSetAlias, testAdapterName, map[string]string{
+							"address": "999",
+							"alias":   "Unknown",
+						}),
+						Expectations: []*suite.Expectation{
+							suite.ExpectError(testAdapterEvtTopic, testAdapterName).ExactlyOnce(),
+						},
+					},
+					{
+						Name:    "malformed metadata responds with error",
+						Command: suite.StringMessage(testAdapterCmdTopic, adapter.CmdThingSetMetadata, testAdapterName, testThingAddressB),
+						Expectations: []*suite.Expectation{
+							suite.ExpectError(testAdapterEvtTopic, testAdapterName).ExactlyOnce(),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	s.Run(t)
+}
+
 func hasAddress(reports adapter.ConnectivityReports, addr string) bool {
 	for _, r := range reports {
 		if r.Address == addr {
@@ -170,6 +242,47 @@ func expectNodeReport(address string) *suite.Expectation {
 		}))
 }
 
+func expectLabeledInclusionReport(address, alias string, metadata map[string]string) *suite.Expectation {
+	return suite.NewExpectation().
+		ExpectTopic(testAdapterEvtTopic).
+		ExpectType(adapter.EvtThingInclusionReport).
+		ExpectService(testAdapterName).
+		Expect(router.MessageVoterFn(func(m *fimpgo.Message) bool {
+			report := &fimptype.ThingInclusionReport{}
+
+			if err := m.Payload.GetObjectValue(report); err != nil {
+				return false
+			}
+
+			propSet := make(map[string]string)
+
+			for k, v := range report.PropSets[adapter.PropSetThingMetadata] {
+				propSet[k], _ = v.(string)
+			}
+
+			if metadata == nil {
+				return report.Address == address && report.ProductName == alias && len(propSet) == 0
+			}
+
+			return report.Address == address && report.ProductName == alias && maps.Equal(propSet, metadata)
+		})).
+		ExactlyOnce()
+}
+
+func expectLabeledNodeReport(address, alias string, metadata map[string]string) *suite.Expectation {
+	return expectNodeReport(address).
+		Expect(router.MessageVoterFn(func(m *fimpgo.Message) bool {
+			report := &adapter.ConnectivityReport{}
+
+			if err := m.Payload.GetObjectValue(report); err != nil {
+				return false
+			}
+
+			return report.Alias == alias && maps.Equal(report.Metadata, metadata)
+		})).
+		ExactlyOnce()
+}
+
 func setupAdapterWithTwoThings() suite.BaseSetup {
 	return func(t *testing.T, mqtt *fimpgo.MqttTransport) ([]*router.Routing, []*task.Task, []suite.Mock) {
 		t.Helper()
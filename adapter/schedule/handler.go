@@ -0,0 +1,179 @@
+package schedule
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/futurehomeno/fimpgo"
+
+	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/adapter/cache"
+)
+
+// Controller is an interface representing a device with a native weekly program.
+// Services adapt their device specific schedule controllers to this interface.
+type Controller interface {
+	// SetSchedule sets a new weekly program of the device.
+	SetSchedule(program *Program) error
+	// ScheduleReport returns the current weekly program of the device.
+	ScheduleReport() (*Program, error)
+}
+
+// HandlerConfig represents a configuration of the schedule handler.
+type HandlerConfig struct {
+	// Service is the service to which the handler belongs. It is used to send schedule reports.
+	Service adapter.Service
+	// Executor applies transitions of the software schedule. It is usually the service itself.
+	Executor Executor
+	// Constraints returns constraints of the weekly program supported by the service.
+	Constraints func() *Constraints
+	// Controller is an optional native weekly program of the device. If provided, the scheduler is ignored.
+	Controller Controller
+	// Scheduler is an optional software scheduler used if the device lacks a native weekly program.
+	Scheduler Scheduler
+	// Lock is the lock of the service, held while the weekly program is set or reported.
+	Lock *sync.Mutex
+	// ReportingCache is the reporting cache of the service.
+	ReportingCache cache.ReportingCache
+	// ReportingStrategy is the reporting strategy of the service.
+	ReportingStrategy cache.ReportingStrategy
+	// PollingCoordinator is an optional polling coordinator of the service, used together with the data source dependent.
+	PollingCoordinator adapter.PollingCoordinator
+	// DataSourceDependent is an optional device controller, whose data sources are polled before and reset after accessing the native weekly program.
+	DataSourceDependent any
+}
+
+// Handler implements the weekly program of a service, either natively by the device or by the software scheduler.
+// It is meant to be embedded in services implementing the Service interface.
+type Handler interface {
+	// SetSchedule validates and sets the weekly program.
+	SetSchedule(program *Program) error
+	// SendScheduleReport sends a weekly program report. Returns true if a report was sent.
+	// Depending on a caching and reporting configuration the service might decide to skip a report.
+	// To make sure report is being sent regardless of circumstances set the force argument to true.
+	SendScheduleReport(force bool) (bool, error)
+	// ExecuteSchedule applies the transition of the software schedule in effect. It does nothing if the device has a native weekly program.
+	ExecuteSchedule() error
+	// SupportsSchedule returns true if the weekly program is supported natively or by the software scheduler.
+	SupportsSchedule() bool
+}
+
+// NewHandler creates new instance of a schedule handler.
+func NewHandler(cfg *HandlerConfig) Handler {
+	return &handler{
+		cfg: cfg,
+	}
+}
+
+type handler struct {
+	cfg *HandlerConfig
+}
+
+// SetSchedule validates and sets the weekly program.
+func (h *handler) SetSchedule(program *Program) error {
+	err := program.Validate(h.cfg.Constraints())
+	if err != nil {
+		return fmt.Errorf("%s: %w", h.cfg.Service.Name(), err)
+	}
+
+	h.cfg.Lock.Lock()
+	defer h.cfg.Lock.Unlock()
+
+	if h.cfg.Controller != nil {
+		err = h.cfg.Controller.SetSchedule(program)
+		if err != nil {
+			return fmt.Errorf("%s: failed to set schedule: %w", h.cfg.Service.Name(), err)
+		}
+
+		adapter.ResetDataSources(h.cfg.PollingCoordinator, h.cfg.DataSourceDependent)
+
+		return nil
+	}
+
+	if h.cfg.Scheduler == nil {
+		return fmt.Errorf("%s: schedule is not supported", h.cfg.Service.Name())
+	}
+
+	err = h.cfg.Scheduler.SetProgram(program)
+	if err != nil {
+		return fmt.Errorf("%s: failed to set schedule: %w", h.cfg.Service.Name(), err)
+	}
+
+	return nil
+}
+
+// SendScheduleReport sends a weekly program report. Returns true if a report was sent.
+func (h *handler) SendScheduleReport(force bool) (bool, error) {
+	h.cfg.Lock.Lock()
+	defer h.cfg.Lock.Unlock()
+
+	program, err := h.report()
+	if err != nil {
+		return false, err
+	}
+
+	if !force && !h.cfg.ReportingCache.ReportRequired(h.cfg.ReportingStrategy, EvtScheduleReport, "", program) {
+		return false, nil
+	}
+
+	message := fimpgo.NewObjectMessage(
+		EvtScheduleReport,
+		h.cfg.Service.Name(),
+		program,
+		nil,
+		nil,
+		nil,
+	)
+
+	err = h.cfg.Service.SendMessage(message)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to send schedule report: %w", h.cfg.Service.Name(), err)
+	}
+
+	h.cfg.ReportingCache.Reported(EvtScheduleReport, "", program)
+
+	return true, nil
+}
+
+// ExecuteSchedule applies the transition of the software schedule in effect.
+func (h *handler) ExecuteSchedule() error {
+	if h.cfg.Controller != nil || h.cfg.Scheduler == nil {
+		return nil
+	}
+
+	err := h.cfg.Scheduler.Execute(h.cfg.Executor, time.Now())
+	if err != nil {
+		return fmt.Errorf("%s: failed to execute schedule: %w", h.cfg.Service.Name(), err)
+	}
+
+	return nil
+}
+
+// SupportsSchedule returns true if the weekly program is supported natively or by the software scheduler.
+func (h *handler) SupportsSchedule() bool {
+	return h.cfg.Controller != nil || h.cfg.Scheduler != nil
+}
+
+// report returns the current weekly program of the device or the software scheduler.
+func (h *handler) report() (*Program, error) {
+	if h.cfg.Controller != nil {
+		err := adapter.PollDataSources(h.cfg.PollingCoordinator, h.cfg.DataSourceDependent)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to poll data sources: %w", h.cfg.Service.Name(), err)
+		}
+
+		program, err := h.cfg.Controller.ScheduleReport()
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to retrieve schedule report: %w", h.cfg.Service.Name(), err)
+		}
+
+		return program, nil
+	}
+
+	if h.cfg.Scheduler == nil {
+		return nil, fmt.Errorf("%s: schedule is not supported", h.cfg.Service.Name())
+	}
+
+	return h.cfg.Scheduler.Program(), nil
+}
@@ -0,0 +1,148 @@
+package schedule_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/futurehomeno/fimpgo/fimptype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/futurehomeno/cliffhanger/adapter/cache"
+	"github.com/futurehomeno/cliffhanger/adapter/schedule"
+	mockedadapter "github.com/futurehomeno/cliffhanger/test/mocks/adapter"
+)
+
+type testController struct {
+	program *schedule.Program
+	err     error
+}
+
+func (c *testController) SetSchedule(program *schedule.Program) error {
+	if c.err != nil {
+		return c.err
+	}
+
+	c.program = program
+
+	return nil
+}
+
+func (c *testController) ScheduleReport() (*schedule.Program, error) {
+	return c.program, c.err
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	program := &schedule.Program{
+		Enabled:     true,
+		Transitions: []*schedule.Transition{{Day: schedule.Monday, Time: "06:00", Mode: "heat"}},
+	}
+
+	tcs := []struct {
+		name       string
+		controller schedule.Controller
+		scheduler  bool
+		supported  bool
+		executed   bool
+	}{
+		{name: "native weekly program", controller: &testController{}, scheduler: true, supported: true},
+		{name: "software scheduler", scheduler: true, supported: true, executed: true},
+		{name: "schedule not supported"},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			service := mockedadapter.NewService(t)
+			service.On("Name").Return(fimptype.ServiceNameT("thermostat")).Maybe()
+
+			if tc.supported {
+				service.On("SendMessage", mock.Anything).Return(nil).Once()
+			}
+
+			var scheduler schedule.Scheduler
+
+			if tc.scheduler {
+				var err error
+
+				scheduler, err = schedule.NewScheduler(&schedule.SchedulerConfig{})
+				assert.NoError(t, err)
+			}
+
+			executor := &testExecutor{}
+
+			h := schedule.NewHandler(&schedule.HandlerConfig{
+				Service:  service,
+				Executor: executor,
+				Constraints: func() *schedule.Constraints {
+					return &schedule.Constraints{Modes: []string{"heat"}}
+				},
+				Controller:        tc.controller,
+				Scheduler:         scheduler,
+				Lock:              &sync.Mutex{},
+				ReportingCache:    cache.NewReportingCache(),
+				ReportingStrategy: cache.ReportOnChangeOnly(),
+			})
+
+			assert.Equal(t, tc.supported, h.SupportsSchedule())
+			assert.Error(t, h.SetSchedule(&schedule.Program{
+				Enabled:     true,
+				Transitions: []*schedule.Transition{{Day: schedule.Monday, Time: "06:00", Mode: "cool"}},
+			}), "invalid program must be rejected")
+
+			err := h.SetSchedule(program)
+			if !tc.supported {
+				assert.Error(t, err)
+
+				_, err = h.SendScheduleReport(true)
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+
+			sent, err := h.SendScheduleReport(false)
+			assert.NoError(t, err)
+			assert.True(t, sent)
+
+			sent, err = h.SendScheduleReport(false)
+			assert.NoError(t, err)
+			assert.False(t, sent, "unchanged program must not be reported")
+
+			assert.NoError(t, h.ExecuteSchedule())
+
+			if tc.executed {
+				assert.Equal(t, []string{"heat"}, executor.modes)
+			} else {
+				assert.Empty(t, executor.modes, "native weekly program must not be executed by the software scheduler")
+			}
+		})
+	}
+}
+
+func TestHandler_ControllerError(t *testing.T) {
+	t.Parallel()
+
+	service := mockedadapter.NewService(t)
+	service.On("Name").Return(fimptype.ServiceNameT("thermostat"))
+
+	h := schedule.NewHandler(&schedule.HandlerConfig{
+		Service:     service,
+		Executor:    &testExecutor{},
+		Constraints: func() *schedule.Constraints { return &schedule.Constraints{} },
+		Controller:  &testController{err: errors.New("test")},
+		Lock:        &sync.Mutex{},
+	})
+
+	assert.Error(t, h.SetSchedule(&schedule.Program{}))
+
+	_, err := h.SendScheduleReport(true)
+	assert.Error(t, err)
+}
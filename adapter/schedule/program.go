@@ -0,0 +1,165 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Constants defining days of the week used by the weekly program.
+const (
+	Monday    Weekday = "mon"
+	Tuesday   Weekday = "tue"
+	Wednesday Weekday = "wed"
+	Thursday  Weekday = "thu"
+	Friday    Weekday = "fri"
+	Saturday  Weekday = "sat"
+	Sunday    Weekday = "sun"
+
+	// DefaultMaxTransitionsPerDay is a default maximum number of transitions within a single day of the weekly program.
+	DefaultMaxTransitionsPerDay = 12
+)
+
+// Weekday represents a day of the week.
+type Weekday string
+
+// Weekdays returns all days of the week, starting with Monday.
+func Weekdays() []Weekday {
+	return []Weekday{Monday, Tuesday, Wednesday, Thursday, Friday, Saturday, Sunday}
+}
+
+// WeekdayOf returns the day of the week of the provided time.
+func WeekdayOf(t time.Time) Weekday {
+	return Weekdays()[(int(t.Weekday())+6)%7]
+}
+
+// index returns the index of the day within the week starting with Monday, or -1 if the day is invalid.
+func (d Weekday) index() int {
+	return slices.Index(Weekdays(), d)
+}
+
+// Setpoint represents a setpoint applied by a transition of the weekly program.
+type Setpoint struct {
+	Type        string  `json:"type"`
+	Temperature float64 `json:"temp"`
+	Unit        string  `json:"unit"`
+}
+
+// Transition represents a change of the mode and/or the setpoint at a given time of a day of the week.
+type Transition struct {
+	Day      Weekday   `json:"day"`
+	Time     string    `json:"time"`
+	Mode     string    `json:"mode,omitempty"`
+	Setpoint *Setpoint `json:"setpoint,omitempty"`
+}
+
+// minute returns the minute of the week at which the transition takes place.
+func (t *Transition) minute() int {
+	at, err := time.Parse("15:04", t.Time)
+	if err != nil {
+		return -1
+	}
+
+	return t.Day.index()*24*60 + at.Hour()*60 + at.Minute()
+}
+
+// Program is a weekly program of a thermostat or a water heater. It is the object sent as value of the schedule commands and reports.
+type Program struct {
+	Enabled     bool          `json:"enabled"`
+	Transitions []*Transition `json:"transitions"`
+}
+
+// Constraints represents constraints of the device the weekly program is validated against.
+type Constraints struct {
+	// Modes are modes supported by the device. If empty, transitions must not change the mode.
+	Modes []string
+	// Setpoints are setpoint types supported by the device. If empty, transitions must not change the setpoint.
+	Setpoints []string
+	// MaxTransitionsPerDay is a maximum number of transitions within a single day. Defaults to DefaultMaxTransitionsPerDay.
+	MaxTransitionsPerDay int
+}
+
+// Validate checks if the program is well-formed and satisfies the provided constraints.
+func (p *Program) Validate(constraints *Constraints) error {
+	if p == nil {
+		return errors.New("schedule: program is missing")
+	}
+
+	maxTransitions := constraints.MaxTransitionsPerDay
+	if maxTransitions <= 0 {
+		maxTransitions = DefaultMaxTransitionsPerDay
+	}
+
+	perDay := make(map[Weekday]int)
+	minutes := make(map[int]bool)
+
+	for i, t := range p.Transitions {
+		if t == nil {
+			return fmt.Errorf("schedule: transition %d is missing", i)
+		}
+
+		if t.Day.index() < 0 {
+			return fmt.Errorf("schedule: transition %d has an invalid day: %s", i, t.Day)
+		}
+
+		if _, err := time.Parse("15:04", t.Time); err != nil {
+			return fmt.Errorf("schedule: transition %d has an invalid time %s, expected HH:MM format", i, t.Time)
+		}
+
+		if t.Mode == "" && t.Setpoint == nil {
+			return fmt.Errorf("schedule: transition %d changes neither mode nor setpoint", i)
+		}
+
+		if t.Mode != "" && !slices.Contains(constraints.Modes, t.Mode) {
+			return fmt.Errorf("schedule: transition %d has an unsupported mode: %s", i, t.Mode)
+		}
+
+		if t.Setpoint != nil && !slices.Contains(constraints.Setpoints, t.Setpoint.Type) {
+			return fmt.Errorf("schedule: transition %d has an unsupported setpoint type: %s", i, t.Setpoint.Type)
+		}
+
+		if minutes[t.minute()] {
+			return fmt.Errorf("schedule: transition %d duplicates time %s on %s", i, t.Time, t.Day)
+		}
+
+		minutes[t.minute()] = true
+		perDay[t.Day]++
+
+		if perDay[t.Day] > maxTransitions {
+			return fmt.Errorf("schedule: too many transitions on %s, at most %d are allowed", t.Day, maxTransitions)
+		}
+	}
+
+	return nil
+}
+
+// Active returns the transition in effect at the provided time, i.e. the most recent one, wrapping around the week.
+// Returns nil if the program is disabled or has no transitions.
+func (p *Program) Active(at time.Time) *Transition {
+	if p == nil || !p.Enabled || len(p.Transitions) == 0 {
+		return nil
+	}
+
+	now := WeekdayOf(at).index()*24*60 + at.Hour()*60 + at.Minute()
+
+	var active, last *Transition
+
+	for _, t := range p.Transitions {
+		m := t.minute()
+
+		if m <= now && (active == nil || m > active.minute()) {
+			active = t
+		}
+
+		if last == nil || m > last.minute() {
+			last = t
+		}
+	}
+
+	if active == nil {
+		return last
+	}
+
+	return active
+}
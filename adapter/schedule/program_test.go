@@ -0,0 +1,119 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/futurehomeno/cliffhanger/adapter/schedule"
+)
+
+func TestProgram_Validate(t *testing.T) {
+	t.Parallel()
+
+	constraints := &schedule.Constraints{
+		Modes:                []string{"heat", "off"},
+		Setpoints:            []string{"heat"},
+		MaxTransitionsPerDay: 2,
+	}
+
+	setpoint := &schedule.Setpoint{Type: "heat", Temperature: 21, Unit: "C"}
+
+	tcs := []struct {
+		name        string
+		transitions []*schedule.Transition
+		wantErr     bool
+	}{
+		{
+			name: "valid program",
+			transitions: []*schedule.Transition{
+				{Day: schedule.Monday, Time: "06:30", Mode: "heat", Setpoint: setpoint},
+				{Day: schedule.Monday, Time: "22:00", Mode: "off"},
+				{Day: schedule.Sunday, Time: "08:00", Setpoint: setpoint},
+			},
+		},
+		{
+			name:        "invalid day",
+			transitions: []*schedule.Transition{{Day: "monday", Time: "06:30", Mode: "heat"}},
+			wantErr:     true,
+		},
+		{
+			name:        "invalid time",
+			transitions: []*schedule.Transition{{Day: schedule.Monday, Time: "24:00", Mode: "heat"}},
+			wantErr:     true,
+		},
+		{
+			name:        "empty transition",
+			transitions: []*schedule.Transition{{Day: schedule.Monday, Time: "06:30"}},
+			wantErr:     true,
+		},
+		{
+			name:        "unsupported mode",
+			transitions: []*schedule.Transition{{Day: schedule.Monday, Time: "06:30", Mode: "cool"}},
+			wantErr:     true,
+		},
+		{
+			name: "unsupported setpoint",
+			transitions: []*schedule.Transition{
+				{Day: schedule.Monday, Time: "06:30", Setpoint: &schedule.Setpoint{Type: "cool", Temperature: 21, Unit: "C"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicated time",
+			transitions: []*schedule.Transition{
+				{Day: schedule.Monday, Time: "06:30", Mode: "heat"},
+				{Day: schedule.Monday, Time: "06:30", Mode: "off"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "too many transitions",
+			transitions: []*schedule.Transition{
+				{Day: schedule.Monday, Time: "06:30", Mode: "heat"},
+				{Day: schedule.Monday, Time: "12:00", Mode: "off"},
+				{Day: schedule.Monday, Time: "18:00", Mode: "heat"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := &schedule.Program{Enabled: true, Transitions: tc.transitions}
+
+			err := p.Validate(constraints)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestProgram_Active(t *testing.T) {
+	t.Parallel()
+
+	morning := &schedule.Transition{Day: schedule.Monday, Time: "06:30", Mode: "heat"}
+	evening := &schedule.Transition{Day: schedule.Friday, Time: "22:00", Mode: "off"}
+
+	p := &schedule.Program{Enabled: true, Transitions: []*schedule.Transition{evening, morning}}
+
+	// 2026-01-05 is a Monday.
+	monday := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, evening, p.Active(monday.Add(6*time.Hour+29*time.Minute)))
+	assert.Equal(t, morning, p.Active(monday.Add(6*time.Hour+30*time.Minute)))
+	assert.Equal(t, morning, p.Active(monday.AddDate(0, 0, 4).Add(21*time.Hour)))
+	assert.Equal(t, evening, p.Active(monday.AddDate(0, 0, 6).Add(12*time.Hour)))
+
+	p.Enabled = false
+
+	assert.Nil(t, p.Active(monday))
+}
@@ -0,0 +1,94 @@
+package schedule
+
+import (
+	"fmt"
+
+	"github.com/futurehomeno/fimpgo"
+	"github.com/futurehomeno/fimpgo/fimptype"
+
+	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/router"
+)
+
+// RouteCmdScheduleSet returns a routing setting the weekly program of services with the provided name.
+func RouteCmdScheduleSet(serviceRegistry adapter.ServiceRegistry, serviceName fimptype.ServiceNameT) *router.Routing {
+	return router.NewRouting(
+		HandleCmdScheduleSet(serviceRegistry),
+		router.ForService(serviceName),
+		router.ForType(CmdScheduleSet),
+	)
+}
+
+// HandleCmdScheduleSet returns a handler setting the weekly program of a service and reporting it back.
+func HandleCmdScheduleSet(serviceRegistry adapter.ServiceRegistry) router.MessageHandler {
+	return router.NewMessageHandler(
+		router.MessageProcessorFn(func(message *fimpgo.Message) (*fimpgo.FimpMessage, error) {
+			s, err := serviceByTopic(serviceRegistry, message)
+			if err != nil {
+				return nil, err
+			}
+
+			program := &Program{}
+
+			err = message.Payload.GetObjectValue(program)
+			if err != nil {
+				return nil, fmt.Errorf("provided schedule has an incorrect format: %w", err)
+			}
+
+			err = s.SetSchedule(program)
+			if err != nil {
+				return nil, fmt.Errorf("failed to set schedule: %w", err)
+			}
+
+			_, err = s.SendScheduleReport(true)
+			if err != nil {
+				return nil, fmt.Errorf("failed to send schedule report: %w", err)
+			}
+
+			return nil, nil
+		}),
+	)
+}
+
+// RouteCmdScheduleGetReport returns a routing reporting the weekly program of services with the provided name.
+func RouteCmdScheduleGetReport(serviceRegistry adapter.ServiceRegistry, serviceName fimptype.ServiceNameT) *router.Routing {
+	return router.NewRouting(
+		HandleCmdScheduleGetReport(serviceRegistry),
+		router.ForService(serviceName),
+		router.ForType(CmdScheduleGetReport),
+	)
+}
+
+// HandleCmdScheduleGetReport returns a handler reporting the weekly program of a service.
+func HandleCmdScheduleGetReport(serviceRegistry adapter.ServiceRegistry) router.MessageHandler {
+	return router.NewMessageHandler(
+		router.MessageProcessorFn(func(message *fimpgo.Message) (*fimpgo.FimpMessage, error) {
+			s, err := serviceByTopic(serviceRegistry, message)
+			if err != nil {
+				return nil, err
+			}
+
+			_, err = s.SendScheduleReport(true)
+			if err != nil {
+				return nil, fmt.Errorf("failed to send schedule report: %w", err)
+			}
+
+			return nil, nil
+		}),
+	)
+}
+
+// serviceByTopic returns a service with a weekly program under the topic of the provided message.
+func serviceByTopic(serviceRegistry adapter.ServiceRegistry, message *fimpgo.Message) (Service, error) {
+	s := serviceRegistry.ServiceByTopic(message.Topic)
+	if s == nil {
+		return nil, fmt.Errorf("service not found under the provided address: %s", message.Addr.ServiceAddress)
+	}
+
+	service, ok := s.(Service)
+	if !ok {
+		return nil, fmt.Errorf("incorrect service found under the provided address: %s", message.Addr.ServiceAddress)
+	}
+
+	return service, nil
+}
@@ -0,0 +1,138 @@
+package schedule
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/futurehomeno/cliffhanger/database"
+)
+
+// schedulerBucket is a database bucket in which programs of software schedulers are persisted.
+const schedulerBucket = "schedule"
+
+// Executor is an interface representing a service applying transitions of the weekly program.
+type Executor interface {
+	// SetMode sets the mode of the device.
+	SetMode(mode string) error
+	// SetSetpoint sets the setpoint for a specific mode.
+	SetSetpoint(mode string, value float64, unit string) error
+}
+
+// SchedulerConfig represents a configuration of the software scheduler.
+type SchedulerConfig struct {
+	// Database is an optional database used to persist the program across restarts.
+	Database database.Database
+	// Key is a key under which the program is persisted. It must be unique for every scheduler sharing the database.
+	Key string
+}
+
+// Scheduler is a software implementation of the weekly program for devices lacking native support.
+// It must be executed periodically, applying the transition in effect whenever it changes.
+type Scheduler interface {
+	// SetProgram replaces the weekly program. The program must be validated beforehand.
+	SetProgram(program *Program) error
+	// Program returns the current weekly program.
+	Program() *Program
+	// Execute applies the transition in effect at the provided time using the executor, unless it has already been applied.
+	Execute(executor Executor, at time.Time) error
+}
+
+// NewScheduler creates new instance of a software scheduler.
+func NewScheduler(cfg *SchedulerConfig) (Scheduler, error) {
+	s := &scheduler{
+		cfg:     cfg,
+		program: &Program{Transitions: make([]*Transition, 0)},
+	}
+
+	if cfg.Database == nil {
+		return s, nil
+	}
+
+	_, err := cfg.Database.Get(schedulerBucket, cfg.Key, s.program)
+	if err != nil {
+		return nil, fmt.Errorf("scheduler: failed to load program %s: %w", cfg.Key, err)
+	}
+
+	return s, nil
+}
+
+type scheduler struct {
+	cfg *SchedulerConfig
+
+	lock    sync.Mutex
+	program *Program
+	applied *Transition
+}
+
+// SetProgram replaces the weekly program.
+func (s *scheduler) SetProgram(program *Program) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.cfg.Database != nil {
+		if err := s.cfg.Database.Set(schedulerBucket, s.cfg.Key, program); err != nil {
+			return fmt.Errorf("scheduler: failed to persist program %s: %w", s.cfg.Key, err)
+		}
+	}
+
+	s.program = program
+	s.applied = nil
+
+	return nil
+}
+
+// Program returns the current weekly program.
+func (s *scheduler) Program() *Program {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.program
+}
+
+// Execute applies the transition in effect at the provided time using the executor, unless it has already been applied.
+// The executor is invoked without holding the lock, as it usually takes the lock of the service which calls SetProgram and Program.
+func (s *scheduler) Execute(executor Executor, at time.Time) error {
+	program, active, ok := s.pending(at)
+	if !ok {
+		return nil
+	}
+
+	if active.Mode != "" {
+		if err := executor.SetMode(active.Mode); err != nil {
+			return fmt.Errorf("scheduler: failed to apply mode of transition on %s at %s: %w", active.Day, active.Time, err)
+		}
+	}
+
+	if active.Setpoint != nil {
+		err := executor.SetSetpoint(active.Setpoint.Type, active.Setpoint.Temperature, active.Setpoint.Unit)
+		if err != nil {
+			return fmt.Errorf("scheduler: failed to apply setpoint of transition on %s at %s: %w", active.Day, active.Time, err)
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// The program might have been replaced in the meantime, in which case its transition must be applied anew.
+	if s.program == program {
+		s.applied = active
+	}
+
+	return nil
+}
+
+// pending returns the current program and its transition in effect at the provided time, if it has not been applied yet.
+func (s *scheduler) pending(at time.Time) (*Program, *Transition, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	active := s.program.Active(at)
+	if active == nil || active == s.applied {
+		s.applied = active
+
+		return nil, nil, false
+	}
+
+	return s.program, active, true
+}
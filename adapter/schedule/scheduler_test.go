@@ -0,0 +1,80 @@
+package schedule_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/futurehomeno/cliffhanger/adapter/schedule"
+	databasehelper "github.com/futurehomeno/cliffhanger/test/helper/database"
+)
+
+type testExecutor struct {
+	modes     []string
+	setpoints []float64
+	err       error
+}
+
+func (e *testExecutor) SetMode(mode string) error {
+	if e.err != nil {
+		return e.err
+	}
+
+	e.modes = append(e.modes, mode)
+
+	return nil
+}
+
+func (e *testExecutor) SetSetpoint(_ string, value float64, _ string) error {
+	e.setpoints = append(e.setpoints, value)
+
+	return nil
+}
+
+func TestScheduler(t *testing.T) {
+	t.Parallel()
+
+	db := databasehelper.NewDatabase(t)
+
+	cfg := &schedule.SchedulerConfig{Database: db, Key: "thermostat_1"}
+
+	s, err := schedule.NewScheduler(cfg)
+	assert.NoError(t, err)
+	assert.False(t, s.Program().Enabled)
+
+	err = s.SetProgram(&schedule.Program{
+		Enabled: true,
+		Transitions: []*schedule.Transition{
+			{Day: schedule.Monday, Time: "06:00", Mode: "heat", Setpoint: &schedule.Setpoint{Type: "heat", Temperature: 21, Unit: "C"}},
+			{Day: schedule.Monday, Time: "22:00", Setpoint: &schedule.Setpoint{Type: "heat", Temperature: 17, Unit: "C"}},
+		},
+	})
+	assert.NoError(t, err)
+
+	// 2026-01-05 is a Monday.
+	monday := time.Date(2026, 1, 5, 0, 0, 0, 0, time.Local)
+	executor := &testExecutor{}
+
+	assert.NoError(t, s.Execute(executor, monday.Add(7*time.Hour)))
+	assert.NoError(t, s.Execute(executor, monday.Add(8*time.Hour)))
+	assert.NoError(t, s.Execute(executor, monday.Add(23*time.Hour)))
+	assert.Equal(t, []string{"heat"}, executor.modes)
+	assert.Equal(t, []float64{21, 17}, executor.setpoints)
+
+	restarted, err := schedule.NewScheduler(cfg)
+	assert.NoError(t, err)
+	assert.True(t, restarted.Program().Enabled)
+	assert.Len(t, restarted.Program().Transitions, 2)
+
+	failing := &testExecutor{err: errors.New("test")}
+
+	assert.Error(t, restarted.Execute(failing, monday.Add(7*time.Hour)))
+	assert.Error(t, restarted.Execute(failing, monday.Add(7*time.Hour)))
+
+	failing.err = nil
+
+	assert.NoError(t, restarted.Execute(failing, monday.Add(7*time.Hour)))
+	assert.Equal(t, []string{"heat"}, failing.modes)
+}
@@ -0,0 +1,43 @@
+package schedule
+
+import (
+	"github.com/futurehomeno/fimpgo/fimptype"
+
+	"github.com/futurehomeno/cliffhanger/adapter"
+)
+
+const (
+	CmdScheduleSet       = "cmd.schedule.set"
+	CmdScheduleGetReport = "cmd.schedule.get_report"
+	EvtScheduleReport    = "evt.schedule.report"
+)
+
+// Service is an interface representing a service supporting a weekly program, either natively or by the software scheduler.
+type Service interface {
+	adapter.Service
+	Handler
+}
+
+// Interfaces returns interfaces supported by a service with a weekly program.
+func Interfaces() []fimptype.Interface {
+	return []fimptype.Interface{
+		{
+			Type:      fimptype.TypeIn,
+			MsgType:   CmdScheduleSet,
+			ValueType: fimptype.VTypeObject,
+			Version:   "1",
+		},
+		{
+			Type:      fimptype.TypeIn,
+			MsgType:   CmdScheduleGetReport,
+			ValueType: fimptype.VTypeNull,
+			Version:   "1",
+		},
+		{
+			Type:      fimptype.TypeOut,
+			MsgType:   EvtScheduleReport,
+			ValueType: fimptype.VTypeObject,
+			Version:   "1",
+		},
+	}
+}
@@ -0,0 +1,44 @@
+package schedule
+
+import (
+	"time"
+
+	"github.com/futurehomeno/fimpgo/fimptype"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/task"
+)
+
+// TaskSchedule creates a task executing software schedules of services with the provided name lacking a native weekly program.
+func TaskSchedule(
+	serviceRegistry adapter.ServiceRegistry,
+	serviceName fimptype.ServiceNameT,
+	frequency time.Duration,
+	voters ...task.Voter,
+) *task.Task {
+	voters = append(voters, adapter.IsRegistryInitialized(serviceRegistry))
+
+	return task.New(handleSchedule(serviceRegistry, serviceName), frequency, voters...)
+}
+
+// handleSchedule creates handler of a schedule task.
+func handleSchedule(serviceRegistry adapter.ServiceRegistry, serviceName fimptype.ServiceNameT) func() {
+	return func() {
+		for _, s := range serviceRegistry.Services(serviceName) {
+			service, ok := s.(Service)
+			if !ok {
+				continue
+			}
+
+			if !service.SupportsSchedule() || adapter.ShouldSkipServiceTask(serviceRegistry, service) {
+				continue
+			}
+
+			err := service.ExecuteSchedule()
+			if err != nil {
+				log.WithError(err).Errorf("failed to execute %s schedule", serviceName)
+			}
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/futurehomeno/fimpgo"
 	"github.com/futurehomeno/fimpgo/fimptype"
 
+	"github.com/futurehomeno/cliffhanger/adapter/cache"
 	"github.com/futurehomeno/cliffhanger/task"
 )
 
@@ -21,6 +22,24 @@ type Service interface {
 	PublishEvent(event ServiceEvent)
 }
 
+// ReportingStrategyOverrider is an optional interface of a service allowing to override its reporting strategy at runtime.
+type ReportingStrategyOverrider interface {
+	Service
+
+	// OverrideReportingStrategy overrides the reporting strategy of the service. Nil restores the configured strategy.
+	OverrideReportingStrategy(strategy cache.ReportingStrategy)
+}
+
+// EventReportingStrategyOverrider is an optional interface of a service reporting multiple events with separate strategies,
+// allowing to override the reporting strategy of a single event at runtime.
+type EventReportingStrategyOverrider interface {
+	ReportingStrategyOverrider
+
+	// OverrideEventReportingStrategy overrides the reporting strategy of the provided event type. Nil restores the configured strategy.
+	// Returns error if the service does not report the event.
+	OverrideEventReportingStrategy(event string, strategy cache.ReportingStrategy) error
+}
+
 // ServiceRegistry is an interface representing a service registry.
 type ServiceRegistry interface {
 	// Services returns all services from all things that match the provided name. If empty all services are returned.
@@ -71,6 +71,7 @@ type Config struct {
 	Specification     *fimptype.Service
 	Reporter          Reporter
 	ReportingStrategy cache.ReportingStrategy
+	ReportingCache    cache.ReportingCache
 }
 
 // NewService creates a new instance of a battery FIMP service.
@@ -86,11 +87,15 @@ func NewService(
 		cfg.ReportingStrategy = DefaultReportingStrategy
 	}
 
+	if cfg.ReportingCache == nil {
+		cfg.ReportingCache = cache.NewReportingCache()
+	}
+
 	s := &service{
 		Service:           adapter.NewService(publisher, cfg.Specification),
 		reporter:          cfg.Reporter,
 		lock:              &sync.Mutex{},
-		reportingCache:    cache.NewReportingCache(),
+		reportingCache:    cfg.ReportingCache,
 		reportingStrategy: cfg.ReportingStrategy,
 	}
 
@@ -107,6 +112,11 @@ type service struct {
 	reportingStrategy cache.ReportingStrategy
 }
 
+// OverrideReportingStrategy overrides the reporting strategy of the service. Nil restores the configured strategy.
+func (s *service) OverrideReportingStrategy(strategy cache.ReportingStrategy) {
+	s.reportingCache.OverrideStrategy(strategy)
+}
+
 // SendBatteryLevelReport sends a battery level report. Returns true if a report was sent.
 // Depending on a caching and reporting configuration the service might decide to skip a report.
 // To make sure report is being sent regardless of circumstances set the force argument to true.
@@ -0,0 +1,162 @@
+package chargepoint
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/futurehomeno/cliffhanger/database"
+)
+
+// authorizationBucket is a database bucket in which authorization lists are persisted.
+const authorizationBucket = "chargepoint_authorization"
+
+// Tag represents an RFID tag allowed to start charging.
+// Zero values of the validity are treated as unbounded.
+type Tag struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	ValidFrom time.Time `json:"valid_from,omitempty"`
+	ValidTo   time.Time `json:"valid_to,omitempty"`
+}
+
+// ValidAt returns true if the tag is valid at the provided time.
+func (t *Tag) ValidAt(at time.Time) bool {
+	if !t.ValidFrom.IsZero() && at.Before(t.ValidFrom) {
+		return false
+	}
+
+	if !t.ValidTo.IsZero() && !at.Before(t.ValidTo) {
+		return false
+	}
+
+	return true
+}
+
+// AuthorizationList is the object sent as value of the authorization list commands and reports.
+type AuthorizationList struct {
+	// FreeCharging allows charging without authorization. Authorized tags are still attributed in session reports.
+	FreeCharging bool   `json:"free_charging"`
+	Tags         []*Tag `json:"tags"`
+}
+
+// Validate checks if the authorization list is well-formed.
+func (l *AuthorizationList) Validate() error {
+	if l == nil {
+		return errors.New("authorization: list is missing")
+	}
+
+	ids := make(map[string]bool)
+
+	for i, t := range l.Tags {
+		if t == nil || t.ID == "" {
+			return fmt.Errorf("authorization: tag %d has no ID", i)
+		}
+
+		id := strings.ToLower(t.ID)
+		if ids[id] {
+			return fmt.Errorf("authorization: tag %s is duplicated", t.ID)
+		}
+
+		ids[id] = true
+
+		if !t.ValidFrom.IsZero() && !t.ValidTo.IsZero() && !t.ValidFrom.Before(t.ValidTo) {
+			return fmt.Errorf("authorization: tag %s has an empty validity period", t.ID)
+		}
+	}
+
+	return nil
+}
+
+// AuthorizationReport is the object sent as value of the authorization report.
+type AuthorizationReport struct {
+	TagID      string `json:"tag_id"`
+	User       string `json:"user,omitempty"`
+	Authorized bool   `json:"authorized"`
+}
+
+// AuthorizationConfig represents a configuration of the authorizer.
+type AuthorizationConfig struct {
+	// Database is a database in which the authorization list is persisted.
+	Database database.Database
+	// Key is a key under which the authorization list is persisted. It must be unique for every chargepoint sharing the database.
+	Key string
+}
+
+// Authorizer is an allow-list of RFID tags authorizing users to start charging.
+type Authorizer interface {
+	// Authorize returns the tag of the provided ID if it is allowed and valid at the provided time.
+	Authorize(tagID string, at time.Time) (*Tag, bool)
+	// SetList validates and replaces the authorization list.
+	SetList(list *AuthorizationList) error
+	// List returns the current authorization list.
+	List() *AuthorizationList
+}
+
+// NewAuthorizer creates new instance of an authorizer.
+func NewAuthorizer(cfg *AuthorizationConfig) (Authorizer, error) {
+	a := &authorizer{
+		cfg:  cfg,
+		list: &AuthorizationList{Tags: make([]*Tag, 0)},
+	}
+
+	_, err := cfg.Database.Get(authorizationBucket, cfg.Key, a.list)
+	if err != nil {
+		return nil, fmt.Errorf("authorization: failed to load list %s: %w", cfg.Key, err)
+	}
+
+	return a, nil
+}
+
+type authorizer struct {
+	cfg *AuthorizationConfig
+
+	lock sync.Mutex
+	list *AuthorizationList
+}
+
+// Authorize returns the tag of the provided ID if it is allowed and valid at the provided time.
+func (a *authorizer) Authorize(tagID string, at time.Time) (*Tag, bool) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	for _, t := range a.list.Tags {
+		if strings.EqualFold(t.ID, tagID) {
+			return t, t.ValidAt(at)
+		}
+	}
+
+	return nil, false
+}
+
+// SetList validates and replaces the authorization list.
+func (a *authorizer) SetList(list *AuthorizationList) error {
+	if err := list.Validate(); err != nil {
+		return err
+	}
+
+	if list.Tags == nil {
+		list.Tags = make([]*Tag, 0)
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if err := a.cfg.Database.Set(authorizationBucket, a.cfg.Key, list); err != nil {
+		return fmt.Errorf("authorization: failed to persist list %s: %w", a.cfg.Key, err)
+	}
+
+	a.list = list
+
+	return nil
+}
+
+// List returns the current authorization list.
+func (a *authorizer) List() *AuthorizationList {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	return a.list
+}
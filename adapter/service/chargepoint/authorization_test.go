@@ -0,0 +1,137 @@
+package chargepoint_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/futurehomeno/fimpgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/futurehomeno/cliffhanger/adapter/service/chargepoint"
+	"github.com/futurehomeno/cliffhanger/database"
+	databasehelper "github.com/futurehomeno/cliffhanger/test/helper/database"
+	mockedadapter "github.com/futurehomeno/cliffhanger/test/mocks/adapter"
+	mockedchargepoint "github.com/futurehomeno/cliffhanger/test/mocks/adapter/service/chargepoint"
+)
+
+func newTestAuthorizer(t *testing.T, db database.Database) chargepoint.Authorizer {
+	t.Helper()
+
+	a, err := chargepoint.NewAuthorizer(&chargepoint.AuthorizationConfig{
+		Database: db,
+		Key:      "chargepoint_1",
+	})
+	assert.NoError(t, err)
+
+	return a
+}
+
+func TestAuthorizer(t *testing.T) {
+	t.Parallel()
+
+	db := databasehelper.NewDatabase(t)
+	a := newTestAuthorizer(t, db)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, ok := a.Authorize("04A1B2", now)
+	assert.False(t, ok)
+
+	assert.Error(t, a.SetList(&chargepoint.AuthorizationList{Tags: []*chargepoint.Tag{{ID: "04a1b2"}, {ID: "04A1B2"}}}))
+	assert.Error(t, a.SetList(&chargepoint.AuthorizationList{Tags: []*chargepoint.Tag{{Name: "John"}}}))
+	assert.Error(t, a.SetList(&chargepoint.AuthorizationList{Tags: []*chargepoint.Tag{{ID: "04a1b2", ValidFrom: now, ValidTo: now}}}))
+
+	assert.NoError(t, a.SetList(&chargepoint.AuthorizationList{
+		Tags: []*chargepoint.Tag{
+			{ID: "04a1b2", Name: "John"},
+			{ID: "04c3d4", Name: "Guest", ValidFrom: now, ValidTo: now.Add(24 * time.Hour)},
+		},
+	}))
+
+	// The list is restored after a restart.
+	a = newTestAuthorizer(t, db)
+
+	tag, ok := a.Authorize("04A1B2", now)
+	assert.True(t, ok)
+	assert.Equal(t, "John", tag.Name)
+
+	_, ok = a.Authorize("04c3d4", now.Add(-time.Second))
+	assert.False(t, ok)
+
+	_, ok = a.Authorize("04c3d4", now.Add(time.Hour))
+	assert.True(t, ok)
+
+	_, ok = a.Authorize("04c3d4", now.Add(24*time.Hour))
+	assert.False(t, ok)
+}
+
+func TestService_Authorization(t *testing.T) {
+	t.Parallel()
+
+	db := databasehelper.NewDatabase(t)
+	a := newTestAuthorizer(t, db)
+
+	assert.NoError(t, a.SetList(&chargepoint.AuthorizationList{Tags: []*chargepoint.Tag{{ID: "04a1b2", Name: "John"}}}))
+
+	publisher := mockedadapter.NewServicePublisher(t)
+	controller := mockedchargepoint.NewController(t)
+
+	s := chargepoint.NewService(publisher, &chargepoint.Config{
+		Specification: chargepoint.Specification("test", "1", "1", nil, nil),
+		Controller:    controller,
+		Authorizer:    a,
+	})
+
+	assert.True(t, s.SupportsAuthorization())
+	assert.Error(t, s.StartCharging(&chargepoint.ChargingSettings{}), "user is not authorized")
+
+	publisher.On("PublishServiceMessage", mock.Anything, mock.MatchedBy(func(m *fimpgo.FimpMessage) bool {
+		report, ok := m.Value.(*chargepoint.AuthorizationReport)
+
+		return m.Interface == chargepoint.EvtAuthReport && ok && !report.Authorized
+	})).Return(nil).Once()
+	publisher.On("PublishServiceMessage", mock.Anything, mock.MatchedBy(func(m *fimpgo.FimpMessage) bool {
+		report, ok := m.Value.(*chargepoint.AuthorizationReport)
+
+		return m.Interface == chargepoint.EvtAuthReport && ok && report.Authorized && report.User == "John"
+	})).Return(nil).Once()
+
+	ok, err := s.Authorize("unknown")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = s.Authorize("04A1B2")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	controller.On("StartChargepointCharging", &chargepoint.ChargingSettings{User: "John"}).Return(nil).Once()
+	assert.NoError(t, s.StartCharging(&chargepoint.ChargingSettings{}))
+
+	controller.On("ChargepointStateReport").Return(chargepoint.StateDisconnected, nil).Once()
+	publisher.On("PublishServiceMessage", mock.Anything, mock.Anything).Return(nil).Once()
+
+	_, err = s.SendStateReport(true)
+	assert.NoError(t, err)
+	assert.Error(t, s.StartCharging(&chargepoint.ChargingSettings{}), "authorization ends when the car is disconnected")
+
+	publisher.On("PublishServiceMessage", mock.Anything, mock.Anything).Return(nil).Twice()
+
+	ok, err = s.Authorize("04A1B2")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// The tag is removed from the list directly in the authorizer after it was presented.
+	assert.NoError(t, a.SetList(&chargepoint.AuthorizationList{}))
+	assert.Error(t, s.StartCharging(&chargepoint.ChargingSettings{}), "authorization is verified again when charging starts")
+
+	assert.NoError(t, s.SetAuthorizationList(&chargepoint.AuthorizationList{Tags: []*chargepoint.Tag{{ID: "04a1b2", Name: "John"}}}))
+
+	ok, err = s.Authorize("04A1B2")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.NoError(t, s.SetAuthorizationList(&chargepoint.AuthorizationList{FreeCharging: true}))
+
+	controller.On("StartChargepointCharging", &chargepoint.ChargingSettings{}).Return(nil).Once()
+	assert.NoError(t, s.StartCharging(&chargepoint.ChargingSettings{}), "replacing the list clears the authorization")
+}
@@ -0,0 +1,453 @@
+package chargepoint
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/futurehomeno/cliffhanger/adapter/service/numericmeter"
+	"github.com/futurehomeno/cliffhanger/task"
+	"github.com/futurehomeno/cliffhanger/types"
+	"github.com/futurehomeno/cliffhanger/utils"
+)
+
+const (
+	// BalancingPolicyFairShare distributes available current evenly across active sessions.
+	BalancingPolicyFairShare BalancingPolicy = "fair_share"
+	// BalancingPolicyPriority satisfies active sessions one by one, starting with the highest priority.
+	BalancingPolicyPriority BalancingPolicy = "priority"
+
+	// MinChargingCurrent is the minimum current at which a car may be charged. Sessions which cannot be offered it are paused.
+	MinChargingCurrent = 6
+
+	// defaultMeterTimeout is a default period without a successful main meter reading after which the load balancer falls back.
+	defaultMeterTimeout = time.Minute
+)
+
+// BalancingPolicy defines how available current is distributed across active sessions.
+type BalancingPolicy string
+
+// BalancedController is an interface representing a charger device participating in the load balancing.
+type BalancedController interface {
+	Controller
+	AdjustableOfferedCurrentController
+}
+
+// BalancedChargepoint represents a chargepoint participating in the load balancing.
+type BalancedChargepoint struct {
+	// Controller is the charger device.
+	Controller BalancedController
+	// MaxCurrent is the maximum current which may be offered to the chargepoint.
+	MaxCurrent int
+	// PhaseMode is the phase mode of the chargepoint, used if the controller is not aware of phase modes. Defaults to all phases.
+	PhaseMode types.PhaseMode
+	// Priority is the priority of the chargepoint used by the priority policy. Chargepoints with higher values are satisfied first.
+	Priority int
+}
+
+// LoadBalancerConfig represents a configuration of the load balancer.
+type LoadBalancerConfig struct {
+	// Meter is the main meter providing phase currents of the site in its extended report.
+	Meter numericmeter.ExtendedReporter
+	// Chargepoints are the chargepoints sharing the main fuse.
+	Chargepoints []*BalancedChargepoint
+	// MainFuse is a current limit of every phase of the site.
+	MainFuse int
+	// PhaseLimits are optional current limits of specific phases, overriding the main fuse.
+	PhaseLimits map[types.Phase]int
+	// Policy is the balancing policy. Defaults to fair share.
+	Policy BalancingPolicy
+	// MeterTimeout is a period without a successful main meter reading after which the load balancer falls back.
+	MeterTimeout time.Duration
+	// FallbackCurrent is a current offered to every active session while meter data is stale, as long as chargepoints alone respect
+	// the phase limits. Defaults to the minimum charging current.
+	FallbackCurrent int
+}
+
+// withDefaults sets default values for all options which were not provided.
+func (c *LoadBalancerConfig) withDefaults() *LoadBalancerConfig {
+	if c.Policy == "" {
+		c.Policy = BalancingPolicyFairShare
+	}
+
+	if c.MeterTimeout <= 0 {
+		c.MeterTimeout = defaultMeterTimeout
+	}
+
+	if c.FallbackCurrent <= 0 {
+		c.FallbackCurrent = MinChargingCurrent
+	}
+
+	return c
+}
+
+// validate checks if the configuration is consistent.
+func (c *LoadBalancerConfig) validate() error {
+	if c.Meter == nil {
+		return errors.New("load balancer: main meter is missing")
+	}
+
+	if len(c.Chargepoints) == 0 {
+		return errors.New("load balancer: chargepoints are missing")
+	}
+
+	for i, cp := range c.Chargepoints {
+		if cp == nil || cp.Controller == nil {
+			return fmt.Errorf("load balancer: controller of chargepoint %d is missing", i)
+		}
+
+		if cp.MaxCurrent < MinChargingCurrent {
+			return fmt.Errorf("load balancer: max current of chargepoint %d must be at least %dA", i, MinChargingCurrent)
+		}
+	}
+
+	for _, phase := range utils.Phases() {
+		if c.limit(phase) <= 0 {
+			return fmt.Errorf("load balancer: current limit of phase %s is missing", phase)
+		}
+	}
+
+	switch c.Policy {
+	case BalancingPolicyFairShare, BalancingPolicyPriority:
+	default:
+		return fmt.Errorf("load balancer: unsupported policy: %s", c.Policy)
+	}
+
+	return nil
+}
+
+// limit returns the current limit of the phase.
+func (c *LoadBalancerConfig) limit(phase types.Phase) int {
+	if limit, ok := c.PhaseLimits[phase]; ok {
+		return limit
+	}
+
+	return c.MainFuse
+}
+
+// LoadBalancer is a site-level controller distributing offered current across chargepoints sharing the main fuse.
+// It must be executed periodically, e.g. using TaskLoadBalancer.
+type LoadBalancer interface {
+	// Balance reads the main meter and the chargepoints and adjusts offered current of active sessions.
+	// Charging sessions are assumed to draw their offered current and suspended sessions nothing, the rest of the measured current is treated
+	// as the base load of the site. Offered current is never increased beyond the headroom left between the measured current and the limit.
+	// Chargepoints which cannot be read are assumed to draw their maximum current on all phases, while the others are still balanced.
+	Balance() error
+}
+
+// NewLoadBalancer creates new instance of a load balancer.
+func NewLoadBalancer(cfg *LoadBalancerConfig) (LoadBalancer, error) {
+	cfg = cfg.withDefaults()
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return &loadBalancer{
+		cfg: cfg,
+		now: time.Now,
+	}, nil
+}
+
+type loadBalancer struct {
+	cfg *LoadBalancerConfig
+	now func() time.Time
+
+	lock        sync.Mutex
+	phases      map[types.Phase]float64
+	lastReading time.Time
+}
+
+// balancedSession represents an active session of a chargepoint during a single balancing round.
+type balancedSession struct {
+	chargepoint *BalancedChargepoint
+	phases      []types.Phase
+	offered     int
+	// drawn is the current the session is assumed to draw, which is the offered current only if the car is actually charging.
+	drawn     int
+	allocated int
+}
+
+// Balance reads the main meter and the chargepoints and adjusts offered current of active sessions.
+func (b *loadBalancer) Balance() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	now := b.now()
+
+	meterErr := b.readMeter(now)
+	stale := b.lastReading.IsZero() || now.Sub(b.lastReading) >= b.cfg.MeterTimeout
+
+	sessions, reserved, readErr := b.sessions()
+
+	available := make(map[types.Phase]int)
+
+	// Headroom limits increases of offered current, as current freed by decreases is not guaranteed until the next reading of the meter.
+	// It is unlimited while meter data is stale, as the fallback current is then bounded by phase limits alone.
+	var headroom map[types.Phase]int
+
+	if !stale {
+		headroom = make(map[types.Phase]int)
+	}
+
+	for _, phase := range utils.Phases() {
+		available[phase] = b.cfg.limit(phase)
+
+		for _, s := range reserved {
+			available[phase] -= s.drawn
+		}
+
+		if stale {
+			continue
+		}
+
+		base := b.phases[phase]
+
+		for _, s := range slices.Concat(sessions, reserved) {
+			if slices.Contains(s.phases, phase) {
+				base -= float64(s.drawn)
+			}
+		}
+
+		available[phase] -= int(math.Ceil(max(base, 0)))
+		headroom[phase] = max(b.cfg.limit(phase)-int(math.Ceil(b.phases[phase])), 0)
+	}
+
+	maxCurrent := func(s *balancedSession) int { return s.chargepoint.MaxCurrent }
+	if stale {
+		maxCurrent = func(s *balancedSession) int { return min(s.chargepoint.MaxCurrent, b.cfg.FallbackCurrent) }
+	}
+
+	b.allocate(sessions, available, maxCurrent)
+
+	// Decreases are applied first, so the main fuse is not exceeded while current is moved between sessions.
+	slices.SortStableFunc(sessions, func(a, c *balancedSession) int {
+		return (a.allocated - a.offered) - (c.allocated - c.offered)
+	})
+
+	errs := []error{readErr}
+
+	for _, s := range sessions {
+		if s.allocated > s.offered {
+			s.allocated = s.limitIncrease(headroom)
+		}
+
+		if s.allocated == s.offered {
+			continue
+		}
+
+		if err := s.chargepoint.Controller.SetChargepointOfferedCurrent(s.allocated); err != nil {
+			errs = append(errs, fmt.Errorf("load balancer: failed to set offered current to %d: %w", s.allocated, err))
+		}
+	}
+
+	switch {
+	case stale:
+		errs = append(errs, fmt.Errorf("load balancer: main meter data is stale, falling back to %dA per session: %w", b.cfg.FallbackCurrent, meterErr))
+	case meterErr != nil:
+		errs = append(errs, meterErr)
+	}
+
+	return errors.Join(errs...)
+}
+
+// readMeter reads phase currents from the main meter. Previous reading is kept if the current one fails.
+func (b *loadBalancer) readMeter(now time.Time) error {
+	values := numericmeter.Values{numericmeter.ValueCurrentPhase1, numericmeter.ValueCurrentPhase2, numericmeter.ValueCurrentPhase3}
+
+	report, err := b.cfg.Meter.MeterExtendedReport(values)
+	if err != nil {
+		return fmt.Errorf("load balancer: failed to read main meter: %w", err)
+	}
+
+	phases := make(map[types.Phase]float64)
+
+	for i, phase := range utils.Phases() {
+		value, ok := report[values[i]]
+		if !ok {
+			return fmt.Errorf("load balancer: main meter did not report current of phase %s", phase)
+		}
+
+		phases[phase] = value
+	}
+
+	b.phases = phases
+	b.lastReading = now
+
+	return nil
+}
+
+// sessions returns active sessions of all chargepoints. Chargepoints which cannot be read are returned as reserved sessions,
+// which are assumed to draw their maximum current on all phases, together with the joined errors.
+func (b *loadBalancer) sessions() ([]*balancedSession, []*balancedSession, error) {
+	var (
+		sessions, reserved []*balancedSession
+		errs               []error
+	)
+
+	for _, cp := range b.cfg.Chargepoints {
+		s, err := b.session(cp)
+		if err != nil {
+			errs = append(errs, err)
+			reserved = append(reserved, &balancedSession{
+				chargepoint: cp,
+				phases:      utils.Phases(),
+				drawn:       cp.MaxCurrent,
+			})
+
+			continue
+		}
+
+		if s != nil {
+			sessions = append(sessions, s)
+		}
+	}
+
+	return sessions, reserved, errors.Join(errs...)
+}
+
+// session returns an active session of the chargepoint or nil if the chargepoint has no active session.
+func (b *loadBalancer) session(cp *BalancedChargepoint) (*balancedSession, error) {
+	state, err := cp.Controller.ChargepointStateReport()
+	if err != nil {
+		return nil, fmt.Errorf("load balancer: failed to retrieve chargepoint state: %w", err)
+	}
+
+	if !isSessionState(state) {
+		return nil, nil
+	}
+
+	report, err := cp.Controller.ChargepointCurrentSessionReport()
+	if err != nil {
+		return nil, fmt.Errorf("load balancer: failed to retrieve chargepoint current session report: %w", err)
+	}
+
+	phaseMode := cp.PhaseMode
+
+	if controller, ok := cp.Controller.(PhaseModeAwareController); ok {
+		phaseMode, err = controller.ChargepointPhaseModeReport()
+		if err != nil {
+			return nil, fmt.Errorf("load balancer: failed to retrieve chargepoint phase mode: %w", err)
+		}
+	}
+
+	s := &balancedSession{
+		chargepoint: cp,
+		phases:      phaseMode.Phases(),
+		offered:     report.OfferedCurrent,
+	}
+
+	if state == StateCharging {
+		s.drawn = report.OfferedCurrent
+	}
+
+	return s, nil
+}
+
+// limitIncrease returns the allocated current limited, so the current drawn by the session does not grow beyond the headroom of its phases.
+// The headroom is consumed by the returned increase. Nil headroom is unlimited. Offered current is kept if it cannot be raised to the minimum.
+func (s *balancedSession) limitIncrease(headroom map[types.Phase]int) int {
+	if headroom == nil {
+		return s.allocated
+	}
+
+	allocated := s.allocated
+
+	for _, phase := range s.phases {
+		allocated = min(allocated, s.drawn+headroom[phase])
+	}
+
+	if allocated <= s.offered || allocated < MinChargingCurrent {
+		return s.offered
+	}
+
+	for _, phase := range s.phases {
+		headroom[phase] -= max(allocated-s.drawn, 0)
+	}
+
+	return allocated
+}
+
+// allocate distributes available current across sessions according to the policy, one ampere at a time.
+// Sessions which cannot be offered the minimum charging current are paused by allocating zero.
+func (b *loadBalancer) allocate(sessions []*balancedSession, available map[types.Phase]int, maxCurrent func(*balancedSession) int) {
+	ordered := slices.Clone(sessions)
+
+	slices.SortStableFunc(ordered, func(a, c *balancedSession) int {
+		return c.chargepoint.Priority - a.chargepoint.Priority
+	})
+
+	fits := func(s *balancedSession, current int) bool {
+		for _, phase := range s.phases {
+			if available[phase] < current {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	take := func(s *balancedSession, current int) {
+		s.allocated += current
+
+		for _, phase := range s.phases {
+			available[phase] -= current
+		}
+	}
+
+	// Priority policy satisfies sessions one by one, while fair share first offers the minimum current to as many sessions as possible.
+	if b.cfg.Policy == BalancingPolicyPriority {
+		for _, s := range ordered {
+			if maxCurrent(s) >= MinChargingCurrent && fits(s, MinChargingCurrent) {
+				take(s, MinChargingCurrent)
+
+				for s.allocated < maxCurrent(s) && fits(s, 1) {
+					take(s, 1)
+				}
+			}
+		}
+
+		return
+	}
+
+	var started []*balancedSession
+
+	for _, s := range ordered {
+		if maxCurrent(s) >= MinChargingCurrent && fits(s, MinChargingCurrent) {
+			take(s, MinChargingCurrent)
+			started = append(started, s)
+		}
+	}
+
+	for progress := true; progress; {
+		progress = false
+
+		for _, s := range started {
+			if s.allocated < maxCurrent(s) && fits(s, 1) {
+				take(s, 1)
+
+				progress = true
+			}
+		}
+	}
+}
+
+// TaskLoadBalancer creates a task executing the load balancer.
+func TaskLoadBalancer(balancer LoadBalancer, frequency time.Duration, voters ...task.Voter) *task.Task {
+	return task.New(handleLoadBalancer(balancer), frequency, voters...)
+}
+
+// handleLoadBalancer creates handler of a load balancer task.
+func handleLoadBalancer(balancer LoadBalancer) func() {
+	return func() {
+		err := balancer.Balance()
+		if err != nil {
+			log.WithError(err).Errorf("failed to execute chargepoint load balancer")
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package chargepoint
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/futurehomeno/cliffhanger/adapter/service/numericmeter"
+	"github.com/futurehomeno/cliffhanger/types"
+)
+
+type fakeMainMeter struct {
+	report numericmeter.ValuesReport
+	err    error
+}
+
+func (f *fakeMainMeter) MeterExtendedReport(numericmeter.Values) (numericmeter.ValuesReport, error) {
+	return f.report, f.err
+}
+
+type fakeBalancedController struct {
+	state   State
+	offered int
+	history []int
+	err     error
+}
+
+func (f *fakeBalancedController) StartChargepointCharging(*ChargingSettings) error { return nil }
+
+func (f *fakeBalancedController) StopChargepointCharging() error { return nil }
+
+func (f *fakeBalancedController) ChargepointCurrentSessionReport() (*SessionReport, error) {
+	return &SessionReport{OfferedCurrent: f.offered}, nil
+}
+
+func (f *fakeBalancedController) ChargepointStateReport() (State, error) {
+	return f.state, f.err
+}
+
+func (f *fakeBalancedController) SetChargepointOfferedCurrent(current int) error {
+	f.offered = current
+	f.history = append(f.history, current)
+
+	return nil
+}
+
+func newTestLoadBalancer(t *testing.T, cfg *LoadBalancerConfig, now *time.Time) *loadBalancer {
+	t.Helper()
+
+	b, err := NewLoadBalancer(cfg)
+	assert.NoError(t, err)
+
+	lb := b.(*loadBalancer) //nolint:forcetypeassert
+	lb.now = func() time.Time { return *now }
+
+	return lb
+}
+
+func phaseCurrents(l1, l2, l3 float64) numericmeter.ValuesReport {
+	return numericmeter.ValuesReport{
+		numericmeter.ValueCurrentPhase1: l1,
+		numericmeter.ValueCurrentPhase2: l2,
+		numericmeter.ValueCurrentPhase3: l3,
+	}
+}
+
+func TestLoadBalancer_FairShare(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	meter := &fakeMainMeter{report: phaseCurrents(10, 4, 4)}
+	cp1 := &fakeBalancedController{state: StateCharging}
+	cp2 := &fakeBalancedController{state: StateSuspendedByEVSE}
+	idle := &fakeBalancedController{state: StateDisconnected}
+
+	b := newTestLoadBalancer(t, &LoadBalancerConfig{
+		Meter: meter,
+		Chargepoints: []*BalancedChargepoint{
+			{Controller: cp1, MaxCurrent: 32},
+			{Controller: cp2, MaxCurrent: 16, PhaseMode: types.PhaseModeNL2},
+			{Controller: idle, MaxCurrent: 32},
+		},
+		MainFuse: 25,
+	}, &now)
+
+	assert.NoError(t, b.Balance())
+	assert.Equal(t, 11, cp1.offered, "L2 is shared evenly")
+	assert.Equal(t, 10, cp2.offered, "L2 is shared evenly")
+	assert.Empty(t, idle.history)
+
+	// The second car starts charging and base load on L2 rises, measured currents include offered currents of charging sessions.
+	cp2.state = StateCharging
+	meter.report = phaseCurrents(11+2, 11+10+8, 11+4)
+
+	assert.NoError(t, b.Balance())
+	assert.Equal(t, 9, cp1.offered)
+	assert.Equal(t, 8, cp2.offered)
+}
+
+func TestLoadBalancer_SuspendedSession(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	meter := &fakeMainMeter{report: phaseCurrents(30, 30, 30)}
+	cp := &fakeBalancedController{state: StateSuspendedByEV, offered: 16}
+
+	b := newTestLoadBalancer(t, &LoadBalancerConfig{
+		Meter:        meter,
+		Chargepoints: []*BalancedChargepoint{{Controller: cp, MaxCurrent: 32}},
+		MainFuse:     40,
+	}, &now)
+
+	assert.NoError(t, b.Balance())
+	assert.Equal(t, 10, cp.offered, "suspended car does not draw its offered current")
+
+	// The car resumes charging at the offered current.
+	cp.state = StateCharging
+	meter.report = phaseCurrents(40, 40, 40)
+
+	assert.NoError(t, b.Balance())
+	assert.Equal(t, []int{10}, cp.history)
+}
+
+func TestLoadBalancer_Headroom(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	meter := &fakeMainMeter{report: phaseCurrents(36, 36, 36)}
+	cp1 := &fakeBalancedController{state: StateCharging, offered: 16}
+	cp2 := &fakeBalancedController{state: StateSuspendedByEVSE}
+
+	b := newTestLoadBalancer(t, &LoadBalancerConfig{
+		Meter: meter,
+		Chargepoints: []*BalancedChargepoint{
+			{Controller: cp1, MaxCurrent: 16},
+			{Controller: cp2, MaxCurrent: 16, Priority: 1},
+		},
+		MainFuse: 40,
+		Policy:   BalancingPolicyPriority,
+	}, &now)
+
+	assert.NoError(t, b.Balance())
+	assert.Equal(t, 0, cp1.offered)
+	assert.Empty(t, cp2.history, "only 4A are left under the main fuse until the decrease is measured")
+
+	meter.report = phaseCurrents(20, 20, 20)
+
+	assert.NoError(t, b.Balance())
+	assert.Equal(t, 0, cp1.offered)
+	assert.Equal(t, 16, cp2.offered)
+}
+
+func TestLoadBalancer_Priority(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cp1 := &fakeBalancedController{state: StateCharging}
+	cp2 := &fakeBalancedController{state: StateCharging}
+	cp3 := &fakeBalancedController{state: StateCharging}
+
+	b := newTestLoadBalancer(t, &LoadBalancerConfig{
+		Meter: &fakeMainMeter{report: phaseCurrents(2, 2, 2)},
+		Chargepoints: []*BalancedChargepoint{
+			{Controller: cp1, MaxCurrent: 32},
+			{Controller: cp2, MaxCurrent: 10, Priority: 1},
+			{Controller: cp3, MaxCurrent: 32},
+		},
+		MainFuse:    25,
+		PhaseLimits: map[types.Phase]int{types.PhaseL3: 20},
+		Policy:      BalancingPolicyPriority,
+	}, &now)
+
+	assert.NoError(t, b.Balance())
+	assert.Equal(t, 10, cp2.offered)
+	assert.Equal(t, 8, cp1.offered, "limited by L3")
+	assert.Equal(t, 0, cp3.offered, "paused below minimum current")
+}
+
+func TestLoadBalancer_StaleMeter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	meter := &fakeMainMeter{report: phaseCurrents(0, 0, 0)}
+	cp1 := &fakeBalancedController{state: StateCharging}
+	cp2 := &fakeBalancedController{state: StateCharging}
+
+	b := newTestLoadBalancer(t, &LoadBalancerConfig{
+		Meter: meter,
+		Chargepoints: []*BalancedChargepoint{
+			{Controller: cp1, MaxCurrent: 16},
+			{Controller: cp2, MaxCurrent: 16},
+		},
+		MainFuse:        40,
+		MeterTimeout:    time.Minute,
+		FallbackCurrent: 8,
+	}, &now)
+
+	assert.NoError(t, b.Balance())
+	assert.Equal(t, 16, cp1.offered)
+	assert.Equal(t, 16, cp2.offered)
+
+	meter.err = errors.New("test")
+	now = now.Add(30 * time.Second)
+
+	assert.Error(t, b.Balance())
+	assert.Equal(t, 16, cp1.offered, "last reading is used until it is stale")
+
+	now = now.Add(30 * time.Second)
+
+	assert.Error(t, b.Balance())
+	assert.Equal(t, 8, cp1.offered)
+	assert.Equal(t, 8, cp2.offered)
+
+	meter.err = nil
+	meter.report = phaseCurrents(16, 16, 16)
+	now = now.Add(time.Second)
+
+	assert.NoError(t, b.Balance())
+	assert.Equal(t, 16, cp1.offered)
+	assert.Equal(t, 16, cp2.offered)
+}
+
+func TestNewLoadBalancer_Validation(t *testing.T) {
+	t.Parallel()
+
+	cp := &BalancedChargepoint{Controller: &fakeBalancedController{}, MaxCurrent: 16}
+
+	_, err := NewLoadBalancer(&LoadBalancerConfig{Chargepoints: []*BalancedChargepoint{cp}, MainFuse: 25})
+	assert.Error(t, err)
+
+	_, err = NewLoadBalancer(&LoadBalancerConfig{Meter: &fakeMainMeter{}, MainFuse: 25})
+	assert.Error(t, err)
+
+	_, err = NewLoadBalancer(&LoadBalancerConfig{Meter: &fakeMainMeter{}, Chargepoints: []*BalancedChargepoint{cp}})
+	assert.Error(t, err)
+
+	_, err = NewLoadBalancer(&LoadBalancerConfig{
+		Meter:        &fakeMainMeter{},
+		Chargepoints: []*BalancedChargepoint{{Controller: &fakeBalancedController{}, MaxCurrent: 4}},
+		MainFuse:     25,
+	})
+	assert.Error(t, err)
+}
+
+func TestLoadBalancer_UnreadableChargepoint(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	meter := &fakeMainMeter{report: phaseCurrents(50, 50, 50)}
+	cp := &fakeBalancedController{state: StateCharging, offered: 32}
+	broken := &fakeBalancedController{state: StateCharging, err: errors.New("test")}
+
+	b := newTestLoadBalancer(t, &LoadBalancerConfig{
+		Meter: meter,
+		Chargepoints: []*BalancedChargepoint{
+			{Controller: cp, MaxCurrent: 32},
+			{Controller: broken, MaxCurrent: 16},
+		},
+		MainFuse: 40,
+	}, &now)
+
+	// Base load of 2A is what remains after the offered current of the readable session and the maximum current of the broken one.
+	assert.Error(t, b.Balance())
+	assert.Equal(t, 22, cp.offered, "readable chargepoint is turned down despite the broken one")
+	assert.Empty(t, broken.history)
+
+	// The broken chargepoint is assumed to draw its maximum current even if the meter measures less.
+	meter.report = phaseCurrents(20, 20, 20)
+
+	assert.Error(t, b.Balance())
+	assert.Equal(t, []int{22, 24}, cp.history, "current reserved for the broken chargepoint is not handed out")
+}
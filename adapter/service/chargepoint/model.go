@@ -19,6 +19,7 @@ const (
 	PropertySupportedPhaseModes    = "sup_phase_modes"
 	PropertyGridType               = "grid_type"
 	PropertyPhases                 = "phases"
+	PropertyUser                   = "user"
 
 	StateDisconnected    State = "disconnected"
 	StateRequesting      State = "requesting"
@@ -45,6 +46,8 @@ func (s State) String() string {
 // ChargingSettings represents optional charging settings.
 type ChargingSettings struct {
 	Mode string
+	// User is a name of the authorized user starting the charging, if any.
+	User string
 }
 
 // CableReport represents an extended cable status report.
@@ -70,6 +73,8 @@ type SessionReport struct {
 	StartedAt             time.Time
 	FinishedAt            time.Time
 	OfferedCurrent        int
+	// User is a name of the user the session is attributed to, if any.
+	User string
 }
 
 func (r *SessionReport) reportProperties(supportsAdjustingCurrent bool) map[string]string {
@@ -87,6 +92,10 @@ func (r *SessionReport) reportProperties(supportsAdjustingCurrent bool) map[stri
 		properties[PropertyFinishedAt] = r.FinishedAt.Format(time.RFC3339)
 	}
 
+	if r.User != "" {
+		properties[PropertyUser] = r.User
+	}
+
 	if supportsAdjustingCurrent {
 		properties[PropertyOfferedCurrent] = strconv.Itoa(r.OfferedCurrent)
 	}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/futurehomeno/fimpgo"
+	"github.com/futurehomeno/fimpgo/fimptype"
 
 	"github.com/futurehomeno/cliffhanger/adapter"
 	"github.com/futurehomeno/cliffhanger/router"
@@ -27,6 +28,13 @@ const (
 	CmdPhaseModeSet             = "cmd.phase_mode.set"
 	CmdPhaseModeGetReport       = "cmd.phase_mode.get_report"
 	EvtPhaseModeReport          = "evt.phase_mode.report"
+	CmdSessionHistoryGetReport  = "cmd.session_history.get_report"
+	EvtSessionHistoryReport     = "evt.session_history.report"
+	CmdAuthAuthorize            = "cmd.auth.authorize"
+	EvtAuthReport               = "evt.auth.report"
+	CmdAuthListSet              = "cmd.auth_list.set"
+	CmdAuthListGetReport        = "cmd.auth_list.get_report"
+	EvtAuthListReport           = "evt.auth_list.report"
 
 	Chargepoint = "chargepoint"
 )
@@ -44,6 +52,10 @@ func RouteService(serviceRegistry adapter.ServiceRegistry) []*router.Routing {
 		routeCmdMaxCurrentGetReport(serviceRegistry),
 		routeCmdPhaseModeSet(serviceRegistry),
 		routeCmdPhaseModeGetReport(serviceRegistry),
+		routeCmdSessionHistoryGetReport(serviceRegistry),
+		routeCmdAuthAuthorize(serviceRegistry),
+		routeCmdAuthListSet(serviceRegistry),
+		routeCmdAuthListGetReport(serviceRegistry),
 	}
 }
 
@@ -397,6 +409,136 @@ func handleCmdPhaseModeGetReport(serviceRegistry adapter.ServiceRegistry) router
 	)
 }
 
+func routeCmdSessionHistoryGetReport(serviceRegistry adapter.ServiceRegistry) *router.Routing {
+	return router.NewRouting(
+		handleCmdSessionHistoryGetReport(serviceRegistry),
+		router.ForService(Chargepoint),
+		router.ForType(CmdSessionHistoryGetReport),
+	)
+}
+
+func handleCmdSessionHistoryGetReport(serviceRegistry adapter.ServiceRegistry) router.MessageHandler {
+	return router.NewMessageHandler(
+		router.MessageProcessorFn(func(message *fimpgo.Message) (*fimpgo.FimpMessage, error) {
+			chargepoint, err := getService(serviceRegistry, message)
+			if err != nil {
+				return nil, err
+			}
+
+			request := &SessionHistoryRequest{}
+
+			if message.Payload.ValueType != fimptype.VTypeNull {
+				err = message.Payload.GetObjectValue(request)
+				if err != nil {
+					return nil, fmt.Errorf("provided session history request has an incorrect format: %w", err)
+				}
+			}
+
+			err = chargepoint.SendSessionHistoryReport(request)
+			if err != nil {
+				return nil, fmt.Errorf("failed to send chargepoint session history report: %w", err)
+			}
+
+			return nil, nil
+		}),
+	)
+}
+
+func routeCmdAuthAuthorize(serviceRegistry adapter.ServiceRegistry) *router.Routing {
+	return router.NewRouting(
+		handleCmdAuthAuthorize(serviceRegistry),
+		router.ForService(Chargepoint),
+		router.ForType(CmdAuthAuthorize),
+	)
+}
+
+func handleCmdAuthAuthorize(serviceRegistry adapter.ServiceRegistry) router.MessageHandler {
+	return router.NewMessageHandler(
+		router.MessageProcessorFn(func(message *fimpgo.Message) (*fimpgo.FimpMessage, error) {
+			chargepoint, err := getService(serviceRegistry, message)
+			if err != nil {
+				return nil, err
+			}
+
+			tagID, err := message.Payload.GetStringValue()
+			if err != nil {
+				return nil, fmt.Errorf("provided tag ID has an incorrect format: %w", err)
+			}
+
+			_, err = chargepoint.Authorize(tagID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to authorize chargepoint user: %w", err)
+			}
+
+			return nil, nil
+		}),
+	)
+}
+
+func routeCmdAuthListSet(serviceRegistry adapter.ServiceRegistry) *router.Routing {
+	return router.NewRouting(
+		handleCmdAuthListSet(serviceRegistry),
+		router.ForService(Chargepoint),
+		router.ForType(CmdAuthListSet),
+	)
+}
+
+func handleCmdAuthListSet(serviceRegistry adapter.ServiceRegistry) router.MessageHandler {
+	return router.NewMessageHandler(
+		router.MessageProcessorFn(func(message *fimpgo.Message) (*fimpgo.FimpMessage, error) {
+			chargepoint, err := getService(serviceRegistry, message)
+			if err != nil {
+				return nil, err
+			}
+
+			list := &AuthorizationList{}
+
+			err = message.Payload.GetObjectValue(list)
+			if err != nil {
+				return nil, fmt.Errorf("provided authorization list has an incorrect format: %w", err)
+			}
+
+			err = chargepoint.SetAuthorizationList(list)
+			if err != nil {
+				return nil, fmt.Errorf("failed to set chargepoint authorization list: %w", err)
+			}
+
+			err = chargepoint.SendAuthorizationListReport()
+			if err != nil {
+				return nil, fmt.Errorf("failed to send chargepoint authorization list report: %w", err)
+			}
+
+			return nil, nil
+		}),
+	)
+}
+
+func routeCmdAuthListGetReport(serviceRegistry adapter.ServiceRegistry) *router.Routing {
+	return router.NewRouting(
+		handleCmdAuthListGetReport(serviceRegistry),
+		router.ForService(Chargepoint),
+		router.ForType(CmdAuthListGetReport),
+	)
+}
+
+func handleCmdAuthListGetReport(serviceRegistry adapter.ServiceRegistry) router.MessageHandler {
+	return router.NewMessageHandler(
+		router.MessageProcessorFn(func(message *fimpgo.Message) (*fimpgo.FimpMessage, error) {
+			chargepoint, err := getService(serviceRegistry, message)
+			if err != nil {
+				return nil, err
+			}
+
+			err = chargepoint.SendAuthorizationListReport()
+			if err != nil {
+				return nil, fmt.Errorf("failed to send chargepoint authorization list report: %w", err)
+			}
+
+			return nil, nil
+		}),
+	)
+}
+
 // getService returns a service responsible for handling the message.
 func getService(serviceRegistry adapter.ServiceRegistry, message *fimpgo.Message) (Service, error) {
 	s := serviceRegistry.ServiceByTopic(message.Topic)
@@ -8,6 +8,7 @@ import (
 
 	"github.com/futurehomeno/fimpgo"
 	"github.com/futurehomeno/fimpgo/fimptype"
+	log "github.com/sirupsen/logrus"
 
 	"github.com/futurehomeno/cliffhanger/adapter"
 	"github.com/futurehomeno/cliffhanger/adapter/cache"
@@ -117,6 +118,19 @@ type Service interface {
 	SupportsAdjustingCableLock() bool
 	// IsCableLockAware returns true if the chargepoint is aware of cable lock.
 	IsCableLockAware() bool
+	// SendSessionHistoryReport sends a report of completed charging sessions within the requested range.
+	SendSessionHistoryReport(request *SessionHistoryRequest) error
+	// SupportsSessionHistory returns true if the chargepoint keeps the history of charging sessions.
+	SupportsSessionHistory() bool
+	// Authorize authorizes the user of the provided RFID tag to start charging and sends an authorization report.
+	// Returns true if the user was authorized. Authorization lasts until the car is disconnected.
+	Authorize(tagID string) (bool, error)
+	// SetAuthorizationList validates and replaces the authorization list.
+	SetAuthorizationList(list *AuthorizationList) error
+	// SendAuthorizationListReport sends an authorization list report.
+	SendAuthorizationListReport() error
+	// SupportsAuthorization returns true if the chargepoint requires authorization to start charging.
+	SupportsAuthorization() bool
 }
 
 // Config represents a service configuration.
@@ -125,6 +139,12 @@ type Config struct {
 	Controller               Controller
 	StateReportingStrategy   cache.ReportingStrategy
 	SessionReportingStrategy cache.ReportingStrategy
+	ReportingCache           cache.ReportingCache
+	// SessionHistory is an optional history of charging sessions, detected whenever a state report is retrieved.
+	SessionHistory SessionHistory
+	// Authorizer is an optional allow-list of RFID tags. If provided, charging may be started only by an authorized user,
+	// unless free charging is enabled.
+	Authorizer Authorizer
 }
 
 // NewService creates new instance of a water heater FIMP service.
@@ -144,13 +164,19 @@ func NewService(
 		cfg.StateReportingStrategy = DefaultStateReportingStrategy
 	}
 
+	if cfg.ReportingCache == nil {
+		cfg.ReportingCache = cache.NewReportingCache()
+	}
+
 	s := &service{
 		Service:                  adapter.NewService(publisher, cfg.Specification),
 		controller:               cfg.Controller,
 		lock:                     &sync.Mutex{},
-		reportingCache:           cache.NewReportingCache(),
+		reportingCache:           cfg.ReportingCache,
 		sessionReportingStrategy: cfg.SessionReportingStrategy,
 		stateReportingStrategy:   cfg.StateReportingStrategy,
+		sessionHistory:           cfg.SessionHistory,
+		authorizer:               cfg.Authorizer,
 	}
 
 	if s.SupportsAdjustingMaxCurrent() {
@@ -175,6 +201,14 @@ func NewService(
 		cfg.Specification.EnsureInterfaces(cableLockAwareInterfaces()...)
 	}
 
+	if s.SupportsSessionHistory() {
+		cfg.Specification.EnsureInterfaces(sessionHistoryInterfaces()...)
+	}
+
+	if s.SupportsAuthorization() {
+		cfg.Specification.EnsureInterfaces(authorizationInterfaces()...)
+	}
+
 	return s
 }
 
@@ -187,6 +221,28 @@ type service struct {
 	reportingCache           cache.ReportingCache
 	stateReportingStrategy   cache.ReportingStrategy
 	sessionReportingStrategy cache.ReportingStrategy
+	sessionHistory           SessionHistory
+	authorizer               Authorizer
+	authorized               *Tag
+	lastState                State
+}
+
+// OverrideReportingStrategy overrides the reporting strategy of all events of the service. Nil restores the configured strategies.
+// Overrides of single events take precedence.
+func (s *service) OverrideReportingStrategy(strategy cache.ReportingStrategy) {
+	s.reportingCache.OverrideStrategy(strategy)
+}
+
+// OverrideEventReportingStrategy overrides the reporting strategy of the provided event type. Nil restores the configured strategy.
+func (s *service) OverrideEventReportingStrategy(event string, strategy cache.ReportingStrategy) error {
+	switch event {
+	case EvtStateReport, EvtCableLockReport, EvtMaxCurrentReport, EvtPhaseModeReport, EvtCurrentSessionReport:
+		s.reportingCache.OverrideKeyStrategy(event, strategy)
+
+		return nil
+	default:
+		return fmt.Errorf("%s: event %s is not reported periodically", s.Name(), event)
+	}
 }
 
 // StartCharging starts car charging.
@@ -201,11 +257,34 @@ func (s *service) StartCharging(settings *ChargingSettings) error {
 		return fmt.Errorf("%s: failed to start charging: %w", s.Name(), err)
 	}
 
+	if s.SupportsAuthorization() {
+		// Authorization is verified again, as the tag might have expired or been removed from the list since it was presented.
+		if s.authorized != nil {
+			if tag, ok := s.authorizer.Authorize(s.authorized.ID, time.Now()); ok {
+				s.authorized = tag
+			} else {
+				s.authorized = nil
+			}
+		}
+
+		if s.authorized == nil && !s.authorizer.List().FreeCharging {
+			return fmt.Errorf("%s: failed to start charging: user is not authorized", s.Name())
+		}
+
+		if s.authorized != nil {
+			settings.User = s.authorized.Name
+		}
+	}
+
 	err = s.controller.StartChargepointCharging(settings)
 	if err != nil {
 		return fmt.Errorf("%s: failed to start charging: %w", s.Name(), err)
 	}
 
+	if s.SupportsSessionHistory() {
+		s.sessionHistory.RecordRemoteStart()
+	}
+
 	return nil
 }
 
@@ -219,6 +298,10 @@ func (s *service) StopCharging() error {
 		return fmt.Errorf("%s: failed to stop charging: %w", s.Name(), err)
 	}
 
+	if s.SupportsSessionHistory() {
+		s.sessionHistory.RecordRemoteStop()
+	}
+
 	return nil
 }
 
@@ -319,6 +402,9 @@ func (s *service) SendCurrentSessionReport(force bool) (bool, error) {
 		return false, fmt.Errorf("%s: failed to retrieve current session report: %w", s.Name(), err)
 	}
 
+	s.attributeUser(value)
+	s.recordSessionReport(value)
+
 	if !force && !s.reportingCache.ReportRequired(s.sessionReportingStrategy, EvtCurrentSessionReport, "", value) {
 		return false, nil
 	}
@@ -390,6 +476,12 @@ func (s *service) SendStateReport(force bool) (bool, error) {
 		return false, fmt.Errorf("%s: failed to retrieve state report: %w", s.Name(), err)
 	}
 
+	s.recordSessionState(value)
+
+	if value == StateDisconnected {
+		s.authorized = nil
+	}
+
 	if !force && !s.reportingCache.ReportRequired(s.stateReportingStrategy, EvtStateReport, "", value) {
 		return false, nil
 	}
@@ -489,6 +581,112 @@ func (s *service) SendPhaseModeReport(force bool) (bool, error) {
 	return true, nil
 }
 
+// SendSessionHistoryReport sends a report of completed charging sessions within the requested range.
+func (s *service) SendSessionHistoryReport(request *SessionHistoryRequest) error {
+	if !s.SupportsSessionHistory() {
+		return fmt.Errorf("%s: session history is not supported", s.Name())
+	}
+
+	report, err := s.sessionHistory.Query(request)
+	if err != nil {
+		return fmt.Errorf("%s: failed to query session history: %w", s.Name(), err)
+	}
+
+	message := fimpgo.NewObjectMessage(
+		EvtSessionHistoryReport,
+		s.Name(),
+		report,
+		nil,
+		nil,
+		nil,
+	)
+
+	err = s.SendMessage(message)
+	if err != nil {
+		return fmt.Errorf("%s: failed to send session history report: %w", s.Name(), err)
+	}
+
+	return nil
+}
+
+// Authorize authorizes the user of the provided RFID tag to start charging and sends an authorization report.
+func (s *service) Authorize(tagID string) (bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.SupportsAuthorization() {
+		return false, fmt.Errorf("%s: authorization is not supported", s.Name())
+	}
+
+	report := &AuthorizationReport{TagID: tagID}
+
+	tag, ok := s.authorizer.Authorize(tagID, time.Now())
+	if ok {
+		s.authorized = tag
+		report.User = tag.Name
+		report.Authorized = true
+	}
+
+	message := fimpgo.NewObjectMessage(
+		EvtAuthReport,
+		s.Name(),
+		report,
+		nil,
+		nil,
+		nil,
+	)
+
+	err := s.SendMessage(message)
+	if err != nil {
+		return ok, fmt.Errorf("%s: failed to send authorization report: %w", s.Name(), err)
+	}
+
+	return ok, nil
+}
+
+// SetAuthorizationList validates and replaces the authorization list. A pending authorization is cleared, so a tag removed from
+// the list cannot be used to start charging.
+func (s *service) SetAuthorizationList(list *AuthorizationList) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if !s.SupportsAuthorization() {
+		return fmt.Errorf("%s: authorization is not supported", s.Name())
+	}
+
+	err := s.authorizer.SetList(list)
+	if err != nil {
+		return fmt.Errorf("%s: failed to set authorization list: %w", s.Name(), err)
+	}
+
+	s.authorized = nil
+
+	return nil
+}
+
+// SendAuthorizationListReport sends an authorization list report.
+func (s *service) SendAuthorizationListReport() error {
+	if !s.SupportsAuthorization() {
+		return fmt.Errorf("%s: authorization is not supported", s.Name())
+	}
+
+	message := fimpgo.NewObjectMessage(
+		EvtAuthListReport,
+		s.Name(),
+		s.authorizer.List(),
+		nil,
+		nil,
+		nil,
+	)
+
+	err := s.SendMessage(message)
+	if err != nil {
+		return fmt.Errorf("%s: failed to send authorization list report: %w", s.Name(), err)
+	}
+
+	return nil
+}
+
 // SupportedStates returns states that are supported by the chargepoint.
 func (s *service) SupportedStates() []string {
 	return s.Service.Specification().PropertyStrings(PropertySupportedStates)
@@ -536,6 +734,60 @@ func (s *service) IsCableLockAware() bool {
 	return err == nil
 }
 
+// SupportsSessionHistory returns true if the chargepoint keeps the history of charging sessions.
+func (s *service) SupportsSessionHistory() bool {
+	return s.sessionHistory != nil
+}
+
+// SupportsAuthorization returns true if the chargepoint requires authorization to start charging.
+func (s *service) SupportsAuthorization() bool {
+	return s.authorizer != nil
+}
+
+// attributeUser attributes the session report to the authorized user, unless the controller has already done so.
+func (s *service) attributeUser(report *SessionReport) {
+	if report.User == "" && s.authorized != nil {
+		report.User = s.authorized.Name
+	}
+}
+
+// recordSessionState records the state in the session history, if supported.
+// On a transition out of a session state, or if the previous state is unknown, the current session report is recorded beforehand,
+// so the energy of a finishing session is up-to-date.
+func (s *service) recordSessionState(state State) {
+	if !s.SupportsSessionHistory() {
+		return
+	}
+
+	previous := s.lastState
+	s.lastState = state
+
+	if previous == "" || (isSessionState(previous) && !isSessionState(state)) {
+		report, err := s.controller.ChargepointCurrentSessionReport()
+		if err != nil {
+			log.WithError(err).Errorf("%s: failed to retrieve current session report for session history", s.Name())
+		} else {
+			s.attributeUser(report)
+			s.recordSessionReport(report)
+		}
+	}
+
+	if err := s.sessionHistory.RecordState(state, time.Now()); err != nil {
+		log.WithError(err).Errorf("%s: failed to record state %s in session history", s.Name(), state)
+	}
+}
+
+// recordSessionReport records the current session report in the session history, if supported.
+func (s *service) recordSessionReport(report *SessionReport) {
+	if !s.SupportsSessionHistory() {
+		return
+	}
+
+	if err := s.sessionHistory.RecordSessionReport(report); err != nil {
+		log.WithError(err).Errorf("%s: failed to record current session report in session history", s.Name())
+	}
+}
+
 // adjustableMaxCurrentController returns the AdjustableMaxCurrentController, if supported.
 func (s *service) adjustableMaxCurrentController() (AdjustableMaxCurrentController, error) {
 	_, ok := s.Specification().PropertyInteger(PropertySupportedMaxCurrent)
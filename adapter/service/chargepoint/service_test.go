@@ -0,0 +1,88 @@
+package chargepoint_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/adapter/cache"
+	"github.com/futurehomeno/cliffhanger/adapter/service/chargepoint"
+	databasehelper "github.com/futurehomeno/cliffhanger/test/helper/database"
+	mockedadapter "github.com/futurehomeno/cliffhanger/test/mocks/adapter"
+	mockedchargepoint "github.com/futurehomeno/cliffhanger/test/mocks/adapter/service/chargepoint"
+)
+
+func TestService_OverrideEventReportingStrategy(t *testing.T) {
+	t.Parallel()
+
+	publisher := mockedadapter.NewServicePublisher(t)
+	controller := mockedchargepoint.NewController(t)
+
+	s := chargepoint.NewService(publisher, &chargepoint.Config{
+		Specification:            chargepoint.Specification("test", "1", "1", nil, nil),
+		Controller:               controller,
+		SessionReportingStrategy: cache.ReportAlways(),
+	})
+
+	overrider, ok := s.(adapter.EventReportingStrategyOverrider)
+	assert.True(t, ok)
+
+	assert.Error(t, overrider.OverrideEventReportingStrategy(chargepoint.EvtAuthReport, cache.ReportAlways()))
+	assert.NoError(t, overrider.OverrideEventReportingStrategy(chargepoint.EvtStateReport, cache.ReportAlways()))
+	assert.NoError(t, overrider.OverrideEventReportingStrategy(chargepoint.EvtCurrentSessionReport, cache.ReportOnChangeOnly()))
+
+	controller.On("ChargepointStateReport").Return(chargepoint.StateCharging, nil)
+	controller.On("ChargepointCurrentSessionReport").Return(&chargepoint.SessionReport{SessionEnergy: 1.5}, nil)
+	publisher.On("PublishServiceMessage", mock.Anything, mock.Anything).Return(nil).Times(3)
+
+	for _, want := range []bool{true, true} {
+		sent, err := s.SendStateReport(false)
+		assert.NoError(t, err)
+		assert.Equal(t, want, sent, "state is reported on every poll")
+	}
+
+	for _, want := range []bool{true, false} {
+		sent, err := s.SendCurrentSessionReport(false)
+		assert.NoError(t, err)
+		assert.Equal(t, want, sent, "unchanged session is not reported")
+	}
+
+	assert.NoError(t, overrider.OverrideEventReportingStrategy(chargepoint.EvtStateReport, nil))
+
+	sent, err := s.SendStateReport(false)
+	assert.NoError(t, err)
+	assert.False(t, sent, "configured strategy does not report unchanged state")
+}
+
+func TestService_SessionHistory(t *testing.T) {
+	t.Parallel()
+
+	h, err := chargepoint.NewSessionHistory(&chargepoint.SessionHistoryConfig{
+		Database: databasehelper.NewDatabase(t),
+		Key:      "chargepoint_1",
+	})
+	assert.NoError(t, err)
+
+	publisher := mockedadapter.NewServicePublisher(t)
+	controller := mockedchargepoint.NewController(t)
+
+	s := chargepoint.NewService(publisher, &chargepoint.Config{
+		Specification:  chargepoint.Specification("test", "1", "1", nil, nil),
+		Controller:     controller,
+		SessionHistory: h,
+	})
+
+	publisher.On("PublishServiceMessage", mock.Anything, mock.Anything).Return(nil)
+
+	// Session report is retrieved only when the previous state is unknown and when the session finishes.
+	controller.On("ChargepointCurrentSessionReport").Return(&chargepoint.SessionReport{SessionEnergy: 2.5}, nil).Twice()
+
+	for _, state := range []chargepoint.State{chargepoint.StateCharging, chargepoint.StateCharging, chargepoint.StateSuspendedByEV, chargepoint.StateFinished} {
+		controller.On("ChargepointStateReport").Return(state, nil).Once()
+
+		_, err = s.SendStateReport(true)
+		assert.NoError(t, err)
+	}
+}
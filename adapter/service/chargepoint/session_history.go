@@ -0,0 +1,301 @@
+package chargepoint
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/futurehomeno/cliffhanger/database"
+)
+
+// Constants defining reasons of starting and stopping a charging session.
+const (
+	SessionReasonRemote         = "remote"
+	SessionReasonLocal          = "local"
+	SessionReasonEVDisconnected = "ev_disconnected"
+	SessionReasonError          = "error"
+
+	// defaultSessionRetention is a default period for which completed sessions are kept.
+	defaultSessionRetention = 400 * 24 * time.Hour
+	// sessionHistoryBucket is a database bucket in which session history is persisted.
+	sessionHistoryBucket = "chargepoint_sessions"
+)
+
+// SessionHistoryConfig represents a configuration of the session history.
+type SessionHistoryConfig struct {
+	// Database is a database in which session history is persisted.
+	Database database.Database
+	// Key is a key under which session history is persisted. It must be unique for every chargepoint sharing the database.
+	Key string
+	// Retention is a period after which completed sessions expire.
+	Retention time.Duration
+}
+
+// withDefaults sets default values for all options which were not provided.
+func (c *SessionHistoryConfig) withDefaults() *SessionHistoryConfig {
+	if c.Retention <= 0 {
+		c.Retention = defaultSessionRetention
+	}
+
+	return c
+}
+
+// Session represents a single charging session.
+type Session struct {
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at,omitempty"`
+	Duration    int64     `json:"duration"`
+	Energy      float64   `json:"energy"`
+	StartReason string    `json:"start_reason"`
+	StopReason  string    `json:"stop_reason,omitempty"`
+	// PeakOfferedCurrent is the highest current offered to the chargepoint during the session, not necessarily the current drawn by the car.
+	PeakOfferedCurrent int    `json:"peak_offered_current"`
+	User               string `json:"user,omitempty"`
+}
+
+// SessionHistoryRequest is the object sent as value of the command requesting the session history.
+// Zero values of the range are treated as unbounded.
+type SessionHistoryRequest struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// SessionHistoryReport is the object sent as value of the session history report.
+type SessionHistoryReport struct {
+	From     time.Time  `json:"from"`
+	To       time.Time  `json:"to"`
+	Sessions []*Session `json:"sessions"`
+}
+
+// SessionHistory is a local history of completed charging sessions.
+// Sessions are detected from transitions of the chargepoint state, starting whenever the chargepoint begins charging
+// and finishing whenever it leaves charging or suspended states.
+type SessionHistory interface {
+	// RecordState records a state of the chargepoint observed at the provided time, starting or finishing a session on transitions.
+	RecordState(state State, timestamp time.Time) error
+	// RecordSessionReport records a current session report, updating energy, peak offered current and user of the active session.
+	RecordSessionReport(report *SessionReport) error
+	// RecordRemoteStart records that charging was started on request, which determines the start reason of the next session.
+	RecordRemoteStart()
+	// RecordRemoteStop records that charging was stopped on request, which determines the stop reason of the active session.
+	RecordRemoteStop()
+	// Query returns completed sessions started within the requested range.
+	Query(request *SessionHistoryRequest) (*SessionHistoryReport, error)
+}
+
+// NewSessionHistory creates new instance of the session history.
+func NewSessionHistory(cfg *SessionHistoryConfig) (SessionHistory, error) {
+	h := &sessionHistory{
+		cfg: cfg.withDefaults(),
+	}
+
+	active := &Session{}
+
+	ok, err := cfg.Database.Get(sessionHistoryBucket, h.activeKey(), active)
+	if err != nil {
+		return nil, fmt.Errorf("session history: failed to load active session %s: %w", cfg.Key, err)
+	}
+
+	if ok {
+		h.active = active
+	}
+
+	return h, nil
+}
+
+type sessionHistory struct {
+	cfg *SessionHistoryConfig
+
+	lock        sync.Mutex
+	active      *Session
+	remoteStart bool
+	remoteStop  bool
+}
+
+// RecordState records a state of the chargepoint observed at the provided time, starting or finishing a session on transitions.
+func (h *sessionHistory) RecordState(state State, timestamp time.Time) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	switch {
+	case h.active == nil && isSessionState(state):
+		return h.start(timestamp)
+	case h.active != nil && !isSessionState(state) && state != StateUnknown:
+		return h.finish(state, timestamp)
+	default:
+		return nil
+	}
+}
+
+// RecordSessionReport records a current session report, updating energy, peak offered current and user of the active session.
+func (h *sessionHistory) RecordSessionReport(report *SessionReport) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.active == nil || report == nil {
+		return nil
+	}
+
+	if report.SessionEnergy <= h.active.Energy && report.OfferedCurrent <= h.active.PeakOfferedCurrent && (report.User == "" || report.User == h.active.User) {
+		return nil
+	}
+
+	h.active.Energy = max(h.active.Energy, report.SessionEnergy)
+	h.active.PeakOfferedCurrent = max(h.active.PeakOfferedCurrent, report.OfferedCurrent)
+
+	if report.User != "" {
+		h.active.User = report.User
+	}
+
+	return h.persistActive()
+}
+
+// RecordRemoteStart records that charging was started on request, which determines the start reason of the next session.
+func (h *sessionHistory) RecordRemoteStart() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.remoteStart = true
+	h.remoteStop = false
+}
+
+// RecordRemoteStop records that charging was stopped on request, which determines the stop reason of the active session.
+func (h *sessionHistory) RecordRemoteStop() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.remoteStart = false
+	h.remoteStop = h.active != nil
+}
+
+// Query returns completed sessions started within the requested range.
+func (h *sessionHistory) Query(request *SessionHistoryRequest) (*SessionHistoryReport, error) {
+	if !request.To.IsZero() && !request.From.Before(request.To) {
+		return nil, errors.New("session history: requested range is empty")
+	}
+
+	from := h.sessionKey(request.From)
+	if request.From.IsZero() {
+		from = h.sessionPrefix()
+	}
+
+	// Tilde sorts after all digits, therefore it bounds keys of all sessions of this chargepoint.
+	to := h.sessionPrefix() + "~"
+	if !request.To.IsZero() {
+		to = h.sessionKey(request.To)
+	}
+
+	keys, err := h.cfg.Database.KeysBetween(sessionHistoryBucket, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("session history: failed to get sessions: %w", err)
+	}
+
+	report := &SessionHistoryReport{
+		From:     request.From,
+		To:       request.To,
+		Sessions: make([]*Session, 0, len(keys)),
+	}
+
+	for _, key := range keys {
+		session := &Session{}
+
+		ok, err := h.cfg.Database.Get(sessionHistoryBucket, key, session)
+		if err != nil {
+			return nil, fmt.Errorf("session history: failed to get session %s: %w", key, err)
+		}
+
+		if ok {
+			report.Sessions = append(report.Sessions, session)
+		}
+	}
+
+	return report, nil
+}
+
+// start starts a new active session.
+func (h *sessionHistory) start(timestamp time.Time) error {
+	reason := SessionReasonLocal
+	if h.remoteStart {
+		reason = SessionReasonRemote
+	}
+
+	h.active = &Session{
+		StartedAt:   timestamp,
+		StartReason: reason,
+	}
+	h.remoteStart = false
+	h.remoteStop = false
+
+	return h.persistActive()
+}
+
+// finish completes the active session and moves it to the history.
+func (h *sessionHistory) finish(state State, timestamp time.Time) error {
+	session := h.active
+	session.FinishedAt = timestamp
+	session.Duration = int64(timestamp.Sub(session.StartedAt).Seconds())
+	session.StopReason = h.stopReason(state)
+
+	key := h.sessionKey(session.StartedAt)
+
+	err := h.cfg.Database.SetWithExpiry(sessionHistoryBucket, key, session, h.cfg.Retention)
+	if err != nil {
+		return fmt.Errorf("session history: failed to persist session %s: %w", key, err)
+	}
+
+	err = h.cfg.Database.Delete(sessionHistoryBucket, h.activeKey())
+	if err != nil {
+		return fmt.Errorf("session history: failed to delete active session %s: %w", h.cfg.Key, err)
+	}
+
+	h.active = nil
+	h.remoteStop = false
+
+	return nil
+}
+
+// stopReason returns the reason of finishing the active session upon transition to the provided state.
+func (h *sessionHistory) stopReason(state State) string {
+	switch {
+	case h.remoteStop:
+		return SessionReasonRemote
+	case state == StateDisconnected:
+		return SessionReasonEVDisconnected
+	case state == StateError || state == StateUnavailable:
+		return SessionReasonError
+	default:
+		return SessionReasonLocal
+	}
+}
+
+// persistActive persists the active session, so it survives restarts.
+func (h *sessionHistory) persistActive() error {
+	err := h.cfg.Database.Set(sessionHistoryBucket, h.activeKey(), h.active)
+	if err != nil {
+		return fmt.Errorf("session history: failed to persist active session %s: %w", h.cfg.Key, err)
+	}
+
+	return nil
+}
+
+// activeKey returns a key of the active session.
+func (h *sessionHistory) activeKey() string {
+	return fmt.Sprintf("%s:active", h.cfg.Key)
+}
+
+// sessionPrefix returns a prefix of keys of completed sessions.
+func (h *sessionHistory) sessionPrefix() string {
+	return fmt.Sprintf("%s:session:", h.cfg.Key)
+}
+
+// sessionKey returns a chronologically sortable key of the completed session started at the provided time.
+func (h *sessionHistory) sessionKey(startedAt time.Time) string {
+	return fmt.Sprintf("%s%020d", h.sessionPrefix(), startedAt.Unix())
+}
+
+// isSessionState returns true if the state belongs to an ongoing charging session.
+func isSessionState(state State) bool {
+	return slices.Contains([]State{StateCharging, StateSuspendedByEV, StateSuspendedByEVSE, StateSwitchingPhases}, state)
+}
@@ -0,0 +1,94 @@
+package chargepoint_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/futurehomeno/cliffhanger/adapter/service/chargepoint"
+	databasehelper "github.com/futurehomeno/cliffhanger/test/helper/database"
+)
+
+func TestSessionHistory(t *testing.T) {
+	t.Parallel()
+
+	db := databasehelper.NewDatabase(t)
+
+	cfg := &chargepoint.SessionHistoryConfig{
+		Database: db,
+		Key:      "chargepoint_1",
+	}
+
+	h, err := chargepoint.NewSessionHistory(cfg)
+	assert.NoError(t, err)
+
+	start := time.Date(2026, 1, 1, 18, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, h.RecordState(chargepoint.StateRequesting, start))
+	assert.NoError(t, h.RecordSessionReport(&chargepoint.SessionReport{SessionEnergy: 1}))
+
+	h.RecordRemoteStart()
+
+	assert.NoError(t, h.RecordState(chargepoint.StateCharging, start.Add(time.Minute)))
+	assert.NoError(t, h.RecordSessionReport(&chargepoint.SessionReport{SessionEnergy: 4.5, OfferedCurrent: 16}))
+	assert.NoError(t, h.RecordState(chargepoint.StateSuspendedByEV, start.Add(2*time.Hour)))
+	assert.NoError(t, h.RecordState(chargepoint.StateUnknown, start.Add(150*time.Minute)))
+	assert.NoError(t, h.RecordSessionReport(&chargepoint.SessionReport{SessionEnergy: 7.25, OfferedCurrent: 10}))
+
+	// Active session is restored after a restart.
+	h, err = chargepoint.NewSessionHistory(cfg)
+	assert.NoError(t, err)
+
+	assert.NoError(t, h.RecordState(chargepoint.StateDisconnected, start.Add(3*time.Hour+time.Minute)))
+
+	assert.NoError(t, h.RecordState(chargepoint.StateCharging, start.Add(24*time.Hour)))
+	assert.NoError(t, h.RecordSessionReport(&chargepoint.SessionReport{SessionEnergy: 2}))
+
+	h.RecordRemoteStop()
+
+	assert.NoError(t, h.RecordState(chargepoint.StateFinished, start.Add(25*time.Hour)))
+
+	assert.NoError(t, h.RecordState(chargepoint.StateCharging, start.Add(48*time.Hour)))
+	assert.NoError(t, h.RecordState(chargepoint.StateError, start.Add(49*time.Hour)))
+
+	report, err := h.Query(&chargepoint.SessionHistoryRequest{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []*chargepoint.Session{
+		{
+			StartedAt:          start.Add(time.Minute),
+			FinishedAt:         start.Add(3*time.Hour + time.Minute),
+			Duration:           3 * 60 * 60,
+			Energy:             7.25,
+			StartReason:        chargepoint.SessionReasonRemote,
+			StopReason:         chargepoint.SessionReasonEVDisconnected,
+			PeakOfferedCurrent: 16,
+		},
+		{
+			StartedAt:   start.Add(24 * time.Hour),
+			FinishedAt:  start.Add(25 * time.Hour),
+			Duration:    60 * 60,
+			Energy:      2,
+			StartReason: chargepoint.SessionReasonLocal,
+			StopReason:  chargepoint.SessionReasonRemote,
+		},
+		{
+			StartedAt:   start.Add(48 * time.Hour),
+			FinishedAt:  start.Add(49 * time.Hour),
+			Duration:    60 * 60,
+			StartReason: chargepoint.SessionReasonLocal,
+			StopReason:  chargepoint.SessionReasonError,
+		},
+	}, report.Sessions)
+
+	report, err = h.Query(&chargepoint.SessionHistoryRequest{From: start.Add(time.Hour), To: start.Add(48 * time.Hour)})
+	assert.NoError(t, err)
+
+	if assert.Len(t, report.Sessions, 1) {
+		assert.Equal(t, chargepoint.SessionReasonRemote, report.Sessions[0].StopReason)
+	}
+
+	_, err = h.Query(&chargepoint.SessionHistoryRequest{From: start, To: start})
+	assert.Error(t, err)
+}
@@ -215,3 +215,55 @@ func adjustableOfferedCurrentInterfaces() []fimptype.Interface {
 		},
 	}
 }
+
+func sessionHistoryInterfaces() []fimptype.Interface {
+	return []fimptype.Interface{
+		{
+			Type:      fimptype.TypeIn,
+			MsgType:   CmdSessionHistoryGetReport,
+			ValueType: fimptype.VTypeObject,
+			Version:   "1",
+		},
+		{
+			Type:      fimptype.TypeOut,
+			MsgType:   EvtSessionHistoryReport,
+			ValueType: fimptype.VTypeObject,
+			Version:   "1",
+		},
+	}
+}
+
+func authorizationInterfaces() []fimptype.Interface {
+	return []fimptype.Interface{
+		{
+			Type:      fimptype.TypeIn,
+			MsgType:   CmdAuthAuthorize,
+			ValueType: fimptype.VTypeString,
+			Version:   "1",
+		},
+		{
+			Type:      fimptype.TypeOut,
+			MsgType:   EvtAuthReport,
+			ValueType: fimptype.VTypeObject,
+			Version:   "1",
+		},
+		{
+			Type:      fimptype.TypeIn,
+			MsgType:   CmdAuthListSet,
+			ValueType: fimptype.VTypeObject,
+			Version:   "1",
+		},
+		{
+			Type:      fimptype.TypeIn,
+			MsgType:   CmdAuthListGetReport,
+			ValueType: fimptype.VTypeNull,
+			Version:   "1",
+		},
+		{
+			Type:      fimptype.TypeOut,
+			MsgType:   EvtAuthListReport,
+			ValueType: fimptype.VTypeObject,
+			Version:   "1",
+		},
+	}
+}
@@ -49,6 +49,7 @@ type Config struct {
 	Specification     *fimptype.Service
 	Controller        Controller
 	ReportingStrategy cache.ReportingStrategy
+	ReportingCache    cache.ReportingCache
 }
 
 // NewService creates a new instance of a colorctrl FIMP service.
@@ -64,11 +65,15 @@ func NewService(
 		cfg.ReportingStrategy = DefaultReportingStrategy
 	}
 
+	if cfg.ReportingCache == nil {
+		cfg.ReportingCache = cache.NewReportingCache()
+	}
+
 	s := &service{
 		Service:           adapter.NewService(publisher, cfg.Specification),
 		controller:        cfg.Controller,
 		lock:              &sync.Mutex{},
-		reportingCache:    cache.NewReportingCache(),
+		reportingCache:    cfg.ReportingCache,
 		reportingStrategy: cfg.ReportingStrategy,
 	}
 
@@ -85,6 +90,11 @@ type service struct {
 	reportingStrategy cache.ReportingStrategy
 }
 
+// OverrideReportingStrategy overrides the reporting strategy of the service. Nil restores the configured strategy.
+func (s *service) OverrideReportingStrategy(strategy cache.ReportingStrategy) {
+	s.reportingCache.OverrideStrategy(strategy)
+}
+
 // SetColor sets the color of the device.
 func (s *service) SetColor(color map[string]int) error {
 	s.lock.Lock()
@@ -115,6 +115,7 @@ type (
 		Specification     *fimptype.Service
 		Controller        Controller
 		ReportingStrategy cache.ReportingStrategy
+		ReportingCache    cache.ReportingCache
 	}
 )
 
@@ -129,12 +130,16 @@ func NewService(
 		cfg.ReportingStrategy = DefaultReportingStrategy
 	}
 
+	if cfg.ReportingCache == nil {
+		cfg.ReportingCache = cache.NewReportingCache()
+	}
+
 	return &service{
 		Service:           adapter.NewService(publisher, cfg.Specification),
 		controller:        cfg.Controller,
 		reportingStrategy: cfg.ReportingStrategy,
 
-		reportingCache: cache.NewReportingCache(),
+		reportingCache: cfg.ReportingCache,
 		lock:           &sync.Mutex{},
 	}
 }
@@ -149,6 +154,11 @@ type service struct {
 	reportingStrategy cache.ReportingStrategy
 }
 
+// OverrideReportingStrategy overrides the reporting strategy of the service. Nil restores the configured strategy.
+func (s *service) OverrideReportingStrategy(strategy cache.ReportingStrategy) {
+	s.reportingCache.OverrideStrategy(strategy)
+}
+
 // SetPlayback sets the playback state.
 func (s *service) SetPlayback(action string) error {
 	s.lock.Lock()
@@ -0,0 +1,303 @@
+package numericmeter
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/futurehomeno/cliffhanger/database"
+)
+
+const (
+	// ResolutionHour represents hourly aggregation of the meter history.
+	ResolutionHour Resolution = "hour"
+	// ResolutionDay represents daily aggregation of the meter history.
+	ResolutionDay Resolution = "day"
+
+	// defaultHourlyRetention is a default period for which hourly aggregates are kept.
+	defaultHourlyRetention = 31 * 24 * time.Hour
+	// defaultDailyRetention is a default period for which daily aggregates are kept.
+	defaultDailyRetention = 400 * 24 * time.Hour
+	// historyBucket is a database bucket in which meter history is persisted.
+	historyBucket = "numericmeter_history"
+)
+
+// Resolution defines aggregation period of the meter history.
+type Resolution string
+
+// String returns string representation of the resolution.
+func (r Resolution) String() string {
+	return string(r)
+}
+
+// start returns the beginning of the aggregation period containing the provided time.
+func (r Resolution) start(t time.Time) time.Time {
+	switch r {
+	case ResolutionHour:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	case ResolutionDay:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	default:
+		return t
+	}
+}
+
+// end returns the end of the aggregation period beginning at the provided time.
+func (r Resolution) end(start time.Time) time.Time {
+	switch r {
+	case ResolutionHour:
+		return start.Add(time.Hour)
+	case ResolutionDay:
+		return start.AddDate(0, 0, 1)
+	default:
+		return start
+	}
+}
+
+// HistoryConfig represents a configuration of the meter history.
+type HistoryConfig struct {
+	// Database is a database in which meter history is persisted.
+	Database database.Database
+	// Key is a key under which meter history is persisted. It must be unique for every meter sharing the database.
+	Key string
+	// HourlyRetention is a period after which hourly aggregates expire.
+	HourlyRetention time.Duration
+	// DailyRetention is a period after which daily aggregates expire.
+	DailyRetention time.Duration
+	// Location is a time zone in which aggregation periods are aligned. Defaults to the local time zone.
+	Location *time.Location
+}
+
+// withDefaults sets default values for all options which were not provided.
+func (c *HistoryConfig) withDefaults() *HistoryConfig {
+	if c.HourlyRetention <= 0 {
+		c.HourlyRetention = defaultHourlyRetention
+	}
+
+	if c.DailyRetention <= 0 {
+		c.DailyRetention = defaultDailyRetention
+	}
+
+	if c.Location == nil {
+		c.Location = time.Local
+	}
+
+	return c
+}
+
+// HistoryRequest is the object sent as value of the command requesting the meter history.
+type HistoryRequest struct {
+	Unit       Unit       `json:"unit"`
+	From       time.Time  `json:"from"`
+	To         time.Time  `json:"to"`
+	Resolution Resolution `json:"resolution"`
+}
+
+// HistoryReport is the object sent as value of the meter history report.
+type HistoryReport struct {
+	Unit       Unit            `json:"unit"`
+	Resolution Resolution      `json:"resolution"`
+	From       time.Time       `json:"from"`
+	To         time.Time       `json:"to"`
+	Entries    []*HistoryEntry `json:"entries"`
+}
+
+// HistoryEntry represents consumption within a single aggregation period.
+type HistoryEntry struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Value float64   `json:"value"`
+}
+
+// History is a local time series of cumulative meter readings, downsampled to hourly and daily aggregates.
+type History interface {
+	// Record records a cumulative meter reading taken at the provided time.
+	Record(unit Unit, value float64, timestamp time.Time) error
+	// Query returns consumption deltas within the requested range and resolution.
+	Query(request *HistoryRequest) (*HistoryReport, error)
+}
+
+// NewHistory creates new instance of the meter history.
+func NewHistory(cfg *HistoryConfig) History {
+	return &history{
+		cfg: cfg.withDefaults(),
+	}
+}
+
+// historyAggregate represents persisted readings within a single aggregation period.
+type historyAggregate struct {
+	Start time.Time `json:"start"`
+	First float64   `json:"first"`
+	Last  float64   `json:"last"`
+}
+
+type history struct {
+	cfg *HistoryConfig
+
+	lock sync.Mutex
+}
+
+// Record records a cumulative meter reading taken at the provided time.
+func (h *history) Record(unit Unit, value float64, timestamp time.Time) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	timestamp = timestamp.In(h.cfg.Location)
+
+	for _, resolution := range []Resolution{ResolutionHour, ResolutionDay} {
+		start := resolution.start(timestamp)
+		key := h.key(unit, resolution, start)
+		aggregate := &historyAggregate{}
+
+		ok, err := h.cfg.Database.Get(historyBucket, key, aggregate)
+		if err != nil {
+			return fmt.Errorf("meter history: failed to get aggregate %s: %w", key, err)
+		}
+
+		if !ok {
+			aggregate = &historyAggregate{Start: start, First: value}
+		}
+
+		aggregate.Last = value
+
+		err = h.cfg.Database.SetWithExpiry(historyBucket, key, aggregate, h.retention(resolution))
+		if err != nil {
+			return fmt.Errorf("meter history: failed to persist aggregate %s: %w", key, err)
+		}
+	}
+
+	return nil
+}
+
+// Query returns consumption deltas within the requested range and resolution.
+// Consumption of a period is a difference between its last reading and the last known reading before it, so consumption during a gap
+// without readings is attributed to the first period after the gap. A decrease of the reading is treated as a meter reset.
+func (h *history) Query(request *HistoryRequest) (*HistoryReport, error) {
+	if request.Resolution != ResolutionHour && request.Resolution != ResolutionDay {
+		return nil, fmt.Errorf("meter history: unsupported resolution: %s", request.Resolution)
+	}
+
+	if !request.From.Before(request.To) {
+		return nil, errors.New("meter history: requested range is empty")
+	}
+
+	from := request.Resolution.start(request.From.In(h.cfg.Location))
+
+	keys, err := h.cfg.Database.KeysBetween(
+		historyBucket,
+		h.key(request.Unit, request.Resolution, from),
+		h.key(request.Unit, request.Resolution, request.To),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("meter history: failed to get aggregates: %w", err)
+	}
+
+	previous, err := h.previous(request.Unit, request.Resolution, from)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &HistoryReport{
+		Unit:       request.Unit,
+		Resolution: request.Resolution,
+		From:       request.From,
+		To:         request.To,
+		Entries:    make([]*HistoryEntry, 0),
+	}
+
+	for _, key := range keys {
+		aggregate := &historyAggregate{}
+
+		ok, err := h.cfg.Database.Get(historyBucket, key, aggregate)
+		if err != nil {
+			return nil, fmt.Errorf("meter history: failed to get aggregate %s: %w", key, err)
+		}
+
+		if !ok {
+			continue
+		}
+
+		report.Entries = append(report.Entries, &HistoryEntry{
+			Start: aggregate.Start,
+			End:   request.Resolution.end(aggregate.Start),
+			Value: consumption(previous, aggregate),
+		})
+
+		previous = aggregate
+	}
+
+	return report, nil
+}
+
+// previous returns the last aggregate of the provided unit and resolution beginning before the provided time, or nil if there is none.
+// Only aggregates within the retention are looked up, as older ones are already expired.
+func (h *history) previous(unit Unit, resolution Resolution, before time.Time) (*historyAggregate, error) {
+	keys, err := h.cfg.Database.KeysBetween(
+		historyBucket,
+		h.key(unit, resolution, before.Add(-h.retention(resolution))),
+		h.key(unit, resolution, before),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("meter history: failed to get aggregates: %w", err)
+	}
+
+	for i := len(keys) - 1; i >= 0; i-- {
+		aggregate := &historyAggregate{}
+
+		ok, err := h.cfg.Database.Get(historyBucket, keys[i], aggregate)
+		if err != nil {
+			return nil, fmt.Errorf("meter history: failed to get aggregate %s: %w", keys[i], err)
+		}
+
+		if ok {
+			return aggregate, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// retention returns the retention of aggregates of the provided resolution.
+func (h *history) retention(resolution Resolution) time.Duration {
+	if resolution == ResolutionDay {
+		return h.cfg.DailyRetention
+	}
+
+	return h.cfg.HourlyRetention
+}
+
+// prefix returns a prefix of keys of aggregates of the provided unit and resolution.
+func (h *history) prefix(unit Unit, resolution Resolution) string {
+	return fmt.Sprintf("%s:%s:%s:", h.cfg.Key, unit, resolution)
+}
+
+// key returns a chronologically sortable key of the aggregate of the provided unit and resolution beginning at the provided time.
+func (h *history) key(unit Unit, resolution Resolution, start time.Time) string {
+	return fmt.Sprintf("%s%020d", h.prefix(unit, resolution), start.Unix())
+}
+
+// consumption returns consumption within the period of the aggregate since the previous one.
+func consumption(previous, current *historyAggregate) float64 {
+	baseline := current.First
+	if previous != nil {
+		baseline = previous.Last
+	}
+
+	if current.Last < baseline {
+		return current.Last
+	}
+
+	return current.Last - baseline
+}
+
+// historyUnits returns cumulative units for which meter history is recorded.
+func historyUnits() Units {
+	return Units{UnitKWh, UnitKVAh, UnitKVArh, UnitPulseCount, UnitCubicMeter, UnitCubicFeet, UnitGallon}
+}
+
+// isHistoryUnit returns true if meter history is recorded for the provided unit.
+func isHistoryUnit(unit Unit) bool {
+	return slices.Contains(historyUnits(), unit)
+}
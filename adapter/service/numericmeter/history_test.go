@@ -0,0 +1,107 @@
+package numericmeter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/futurehomeno/cliffhanger/adapter/service/numericmeter"
+	databasehelper "github.com/futurehomeno/cliffhanger/test/helper/database"
+)
+
+func TestHistory(t *testing.T) {
+	t.Parallel()
+
+	db := databasehelper.NewDatabase(t)
+
+	h := numericmeter.NewHistory(&numericmeter.HistoryConfig{
+		Database: db,
+		Key:      "meter_elec",
+		Location: time.UTC,
+	})
+
+	day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	readings := []struct {
+		at    time.Duration
+		value float64
+	}{
+		{at: 10 * time.Minute, value: 100},
+		{at: 50 * time.Minute, value: 101},
+		{at: 70 * time.Minute, value: 103},
+		{at: 3*time.Hour + 30*time.Minute, value: 110},
+		{at: 25 * time.Hour, value: 120},
+		{at: 26 * time.Hour, value: 2},
+	}
+
+	for _, r := range readings {
+		assert.NoError(t, h.Record(numericmeter.UnitKWh, r.value, day.Add(r.at)))
+	}
+
+	assert.NoError(t, h.Record(numericmeter.UnitCubicMeter, 5, day))
+
+	report, err := h.Query(&numericmeter.HistoryRequest{
+		Unit:       numericmeter.UnitKWh,
+		From:       day,
+		To:         day.Add(24 * time.Hour),
+		Resolution: numericmeter.ResolutionHour,
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, numericmeter.UnitKWh, report.Unit)
+
+	if assert.Len(t, report.Entries, 3) {
+		assert.True(t, report.Entries[0].Start.Equal(day))
+		assert.True(t, report.Entries[0].End.Equal(day.Add(time.Hour)))
+		assert.Equal(t, 1.0, report.Entries[0].Value)
+		assert.True(t, report.Entries[1].Start.Equal(day.Add(time.Hour)))
+		assert.Equal(t, 2.0, report.Entries[1].Value)
+		assert.True(t, report.Entries[2].Start.Equal(day.Add(3*time.Hour)))
+		assert.Equal(t, 7.0, report.Entries[2].Value, "consumption during the gap is attributed to the first period after it")
+	}
+
+	report, err = h.Query(&numericmeter.HistoryRequest{
+		Unit:       numericmeter.UnitKWh,
+		From:       day.Add(25*time.Hour + 30*time.Minute),
+		To:         day.Add(48 * time.Hour),
+		Resolution: numericmeter.ResolutionHour,
+	})
+	assert.NoError(t, err)
+
+	if assert.Len(t, report.Entries, 2) {
+		assert.True(t, report.Entries[0].Start.Equal(day.Add(25*time.Hour)))
+		assert.Equal(t, 10.0, report.Entries[0].Value, "baseline is the last reading before the requested range")
+		assert.True(t, report.Entries[1].Start.Equal(day.Add(26*time.Hour)))
+		assert.Equal(t, 2.0, report.Entries[1].Value)
+	}
+
+	report, err = h.Query(&numericmeter.HistoryRequest{
+		Unit:       numericmeter.UnitKWh,
+		From:       day,
+		To:         day.Add(48 * time.Hour),
+		Resolution: numericmeter.ResolutionDay,
+	})
+	assert.NoError(t, err)
+
+	if assert.Len(t, report.Entries, 2) {
+		assert.Equal(t, 10.0, report.Entries[0].Value)
+		assert.True(t, report.Entries[1].End.Equal(day.Add(48*time.Hour)))
+		assert.Equal(t, 2.0, report.Entries[1].Value)
+	}
+
+	_, err = h.Query(&numericmeter.HistoryRequest{
+		Unit:       numericmeter.UnitKWh,
+		From:       day,
+		To:         day.Add(time.Hour),
+		Resolution: "week",
+	})
+	assert.Error(t, err)
+
+	_, err = h.Query(&numericmeter.HistoryRequest{
+		Unit:       numericmeter.UnitKWh,
+		From:       day,
+		To:         day,
+		Resolution: numericmeter.ResolutionDay,
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,247 @@
+package numericmeter
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/database"
+)
+
+const (
+	// defaultMaxGap is a default maximum time between two power samples which is integrated without applying the gap policy.
+	defaultMaxGap = 15 * time.Minute
+	// integratingReporterBucket is a database bucket in which energy accumulators are persisted.
+	integratingReporterBucket = "numericmeter_energy"
+)
+
+// GapPolicy represents a policy of integrating energy between two power samples which are further apart than the maximum gap,
+// e.g. because of a connectivity issue or a restart of the application.
+type GapPolicy string
+
+const (
+	// GapPolicySkip does not accumulate any energy for the gap.
+	GapPolicySkip GapPolicy = "skip"
+	// GapPolicyCap accumulates energy for the maximum gap only, assuming the last known power.
+	GapPolicyCap GapPolicy = "cap"
+	// GapPolicyInterpolate accumulates energy for the whole gap, interpolating linearly between the samples.
+	GapPolicyInterpolate GapPolicy = "interpolate"
+)
+
+// IntegratingReporterConfig represents a configuration of the integrating reporter.
+type IntegratingReporterConfig struct {
+	// Database is an optional database used to persist the energy accumulator across restarts.
+	Database database.Database
+	// Key is a key under which the energy accumulator is persisted. It must be unique for every meter sharing the database.
+	Key string
+	// MaxGap is a maximum time between two power samples which is integrated without applying the gap policy.
+	MaxGap time.Duration
+	// GapPolicy is a policy applied to gaps between power samples exceeding the maximum gap.
+	GapPolicy GapPolicy
+}
+
+// withDefaults sets default values for all options which were not provided.
+func (c *IntegratingReporterConfig) withDefaults() *IntegratingReporterConfig {
+	if c.MaxGap <= 0 {
+		c.MaxGap = defaultMaxGap
+	}
+
+	if c.GapPolicy == "" {
+		c.GapPolicy = GapPolicySkip
+	}
+
+	return c
+}
+
+// IntegratingReporter is a reporter wrapping a reporter of instantaneous power and accumulating energy in kWh
+// by trapezoidal integration of power samples taken every time a report in W or kWh is requested.
+// It implements ResettableReporter, resetting the accumulator and the wrapped reporter if it supports reset as well.
+// Export and extended reports as well as data sources are delegated to the wrapped reporter, failing or returning none if it does not
+// support them, so the specification must declare export units and extended values only if the wrapped reporter supports them.
+type IntegratingReporter interface {
+	Reporter
+	ResettableReporter
+	ExportReporter
+	ExtendedReporter
+	adapter.DataSourceDependent
+
+	// Energy returns the accumulated energy in kWh without sampling power.
+	Energy() float64
+}
+
+// NewIntegratingReporter creates new instance of an integrating reporter. Reports in units other than W and kWh are delegated to the wrapped reporter.
+func NewIntegratingReporter(reporter Reporter, cfg *IntegratingReporterConfig) (IntegratingReporter, error) {
+	if cfg == nil {
+		cfg = &IntegratingReporterConfig{}
+	}
+
+	r := &integratingReporter{
+		reporter: reporter,
+		cfg:      cfg.withDefaults(),
+		now:      time.Now,
+	}
+
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// energyAccumulator represents a persisted state of the integrating reporter.
+type energyAccumulator struct {
+	Energy     float64   `json:"energy"`
+	LastPower  float64   `json:"last_power"`
+	LastSample time.Time `json:"last_sample"`
+}
+
+type integratingReporter struct {
+	reporter Reporter
+	cfg      *IntegratingReporterConfig
+	now      func() time.Time
+
+	lock        sync.Mutex
+	accumulator energyAccumulator
+}
+
+// MeterReport returns simplified meter report based on requested unit.
+func (r *integratingReporter) MeterReport(unit Unit) (float64, error) {
+	switch unit { //nolint:exhaustive
+	case UnitW:
+		return r.sample()
+	case UnitKWh:
+		if _, err := r.sample(); err != nil {
+			return 0, err
+		}
+
+		return r.Energy(), nil
+	default:
+		return r.reporter.MeterReport(unit)
+	}
+}
+
+// MeterExportReport returns the export report of the wrapped reporter, if supported.
+func (r *integratingReporter) MeterExportReport(unit Unit) (float64, error) {
+	reporter, ok := r.reporter.(ExportReporter)
+	if !ok {
+		return 0, errors.New("integrating reporter: wrapped reporter does not support export report")
+	}
+
+	return reporter.MeterExportReport(unit)
+}
+
+// MeterExtendedReport returns the extended report of the wrapped reporter, if supported.
+func (r *integratingReporter) MeterExtendedReport(values Values) (ValuesReport, error) {
+	reporter, ok := r.reporter.(ExtendedReporter)
+	if !ok {
+		return nil, errors.New("integrating reporter: wrapped reporter does not support extended report")
+	}
+
+	return reporter.MeterExtendedReport(values)
+}
+
+// DataSources returns data sources of the wrapped reporter, if it depends on any.
+func (r *integratingReporter) DataSources() []string {
+	dependent, ok := r.reporter.(adapter.DataSourceDependent)
+	if !ok {
+		return nil
+	}
+
+	return dependent.DataSources()
+}
+
+// MeterReset resets the accumulated energy and the wrapped reporter if it supports reset.
+func (r *integratingReporter) MeterReset() error {
+	if resettable, ok := r.reporter.(ResettableReporter); ok {
+		if err := resettable.MeterReset(); err != nil {
+			return fmt.Errorf("integrating reporter: failed to reset wrapped reporter: %w", err)
+		}
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.accumulator.Energy = 0
+
+	return r.save()
+}
+
+// Energy returns the accumulated energy in kWh without sampling power.
+func (r *integratingReporter) Energy() float64 {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.accumulator.Energy
+}
+
+// sample samples power from the wrapped reporter and accumulates energy consumed since the previous sample.
+func (r *integratingReporter) sample() (float64, error) {
+	power, err := r.reporter.MeterReport(UnitW)
+	if err != nil {
+		return 0, err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.now()
+
+	if !r.accumulator.LastSample.IsZero() && now.After(r.accumulator.LastSample) {
+		r.accumulator.Energy += r.integrate(r.accumulator.LastPower, power, now.Sub(r.accumulator.LastSample))
+	}
+
+	r.accumulator.LastPower = power
+	r.accumulator.LastSample = now
+
+	if err := r.save(); err != nil {
+		log.WithError(err).Errorf("integrating reporter: failed to persist energy accumulator")
+	}
+
+	return power, nil
+}
+
+// integrate returns energy in kWh consumed during the provided period, applying the gap policy if the period exceeds the maximum gap.
+func (r *integratingReporter) integrate(previous, current float64, period time.Duration) float64 {
+	if period > r.cfg.MaxGap {
+		switch r.cfg.GapPolicy {
+		case GapPolicySkip:
+			return 0
+		case GapPolicyCap:
+			return previous * r.cfg.MaxGap.Hours() / 1000
+		case GapPolicyInterpolate:
+		}
+	}
+
+	return (previous + current) / 2 * period.Hours() / 1000
+}
+
+// load loads the persisted energy accumulator if the database is configured.
+func (r *integratingReporter) load() error {
+	if r.cfg.Database == nil {
+		return nil
+	}
+
+	_, err := r.cfg.Database.Get(integratingReporterBucket, r.cfg.Key, &r.accumulator)
+	if err != nil {
+		return fmt.Errorf("integrating reporter: failed to load energy accumulator %s: %w", r.cfg.Key, err)
+	}
+
+	return nil
+}
+
+// save persists the energy accumulator if the database is configured. It must be used only while holding the lock.
+func (r *integratingReporter) save() error {
+	if r.cfg.Database == nil {
+		return nil
+	}
+
+	if err := r.cfg.Database.Set(integratingReporterBucket, r.cfg.Key, &r.accumulator); err != nil {
+		return fmt.Errorf("integrating reporter: failed to persist energy accumulator %s: %w", r.cfg.Key, err)
+	}
+
+	return nil
+}
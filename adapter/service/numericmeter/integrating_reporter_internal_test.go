@@ -0,0 +1,192 @@
+package numericmeter
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	databasehelper "github.com/futurehomeno/cliffhanger/test/helper/database"
+)
+
+type fakePowerReporter struct {
+	power float64
+	err   error
+	reset int
+}
+
+func (f *fakePowerReporter) MeterReport(unit Unit) (float64, error) {
+	if unit != UnitW {
+		return 0, errors.New("unsupported unit")
+	}
+
+	return f.power, f.err
+}
+
+func (f *fakePowerReporter) MeterReset() error {
+	f.reset++
+
+	return nil
+}
+
+type fakeExtendedPowerReporter struct {
+	fakePowerReporter
+}
+
+func (f *fakeExtendedPowerReporter) MeterExportReport(unit Unit) (float64, error) {
+	return 1.5, nil
+}
+
+func (f *fakeExtendedPowerReporter) MeterExtendedReport(values Values) (ValuesReport, error) {
+	return ValuesReport{ValuePowerImport: f.power}, nil
+}
+
+func (f *fakeExtendedPowerReporter) DataSources() []string {
+	return []string{"device"}
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestIntegratingReporter(t *testing.T, reporter Reporter, clock *fakeClock, cfg *IntegratingReporterConfig) *integratingReporter {
+	t.Helper()
+
+	r, err := NewIntegratingReporter(reporter, cfg)
+	assert.NoError(t, err)
+
+	ir := r.(*integratingReporter) //nolint:forcetypeassert
+	ir.now = clock.Now
+
+	return ir
+}
+
+func TestIntegratingReporter(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name     string
+		policy   GapPolicy
+		expected float64
+	}{
+		{name: "skip", policy: GapPolicySkip, expected: 1},
+		{name: "cap", policy: GapPolicyCap, expected: 2.5},
+		{name: "interpolate", policy: GapPolicyInterpolate, expected: 4},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+			reporter := &fakePowerReporter{power: 1000}
+			r := newTestIntegratingReporter(t, reporter, clock, &IntegratingReporterConfig{
+				MaxGap:    30 * time.Minute,
+				GapPolicy: tc.policy,
+			})
+
+			v, err := r.MeterReport(UnitKWh)
+			assert.NoError(t, err)
+			assert.Equal(t, 0.0, v)
+
+			clock.Advance(30 * time.Minute)
+			reporter.power = 3000
+
+			v, err = r.MeterReport(UnitW)
+			assert.NoError(t, err)
+			assert.Equal(t, 3000.0, v)
+			assert.InDelta(t, 1.0, r.Energy(), 1e-9)
+
+			clock.Advance(time.Hour)
+			reporter.power = 3000
+
+			v, err = r.MeterReport(UnitKWh)
+			assert.NoError(t, err)
+			assert.InDelta(t, tc.expected, v, 1e-9)
+
+			_, err = r.MeterReport(UnitA)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestIntegratingReporter_Persistence(t *testing.T) {
+	t.Parallel()
+
+	db := databasehelper.NewDatabase(t)
+
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	reporter := &fakePowerReporter{power: 2000}
+	cfg := &IntegratingReporterConfig{Database: db, Key: "meter_elec"}
+
+	r := newTestIntegratingReporter(t, reporter, clock, cfg)
+
+	_, err := r.MeterReport(UnitW)
+	assert.NoError(t, err)
+
+	clock.Advance(15 * time.Minute)
+
+	_, err = r.MeterReport(UnitW)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.5, r.Energy(), 1e-9)
+
+	clock.Advance(5 * time.Minute)
+
+	restarted := newTestIntegratingReporter(t, reporter, clock, cfg)
+	assert.InDelta(t, 0.5, restarted.Energy(), 1e-9)
+
+	v, err := restarted.MeterReport(UnitKWh)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.5+2.0/12, v, 1e-9)
+
+	reporter.err = errors.New("test")
+
+	_, err = restarted.MeterReport(UnitKWh)
+	assert.Error(t, err)
+
+	assert.NoError(t, restarted.MeterReset())
+	assert.Equal(t, 1, reporter.reset)
+	assert.Equal(t, 0.0, restarted.Energy())
+
+	restarted = newTestIntegratingReporter(t, reporter, clock, cfg)
+	assert.Equal(t, 0.0, restarted.Energy())
+}
+
+func TestIntegratingReporter_Delegation(t *testing.T) {
+	t.Parallel()
+
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	r := newTestIntegratingReporter(t, &fakeExtendedPowerReporter{fakePowerReporter{power: 2000}}, clock, nil)
+
+	v, err := r.MeterExportReport(UnitKWh)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, v)
+
+	report, err := r.MeterExtendedReport(Values{ValuePowerImport})
+	assert.NoError(t, err)
+	assert.Equal(t, ValuesReport{ValuePowerImport: 2000}, report)
+
+	assert.Equal(t, []string{"device"}, r.DataSources())
+
+	r = newTestIntegratingReporter(t, &fakePowerReporter{power: 2000}, clock, nil)
+
+	_, err = r.MeterExportReport(UnitKWh)
+	assert.Error(t, err)
+
+	_, err = r.MeterExtendedReport(Values{ValuePowerImport})
+	assert.Error(t, err)
+
+	assert.Empty(t, r.DataSources())
+}
@@ -18,6 +18,8 @@ const (
 	EvtMeterExportReport    = "evt.meter_export.report"
 	CmdMeterExtGetReport    = "cmd.meter_ext.get_report"
 	EvtMeterExtReport       = "evt.meter_ext.report"
+	CmdMeterGetHistory      = "cmd.meter.get_history"
+	EvtMeterHistoryReport   = "evt.meter.history_report"
 
 	MeterElec    = "meter_elec"
 	MeterGas     = "meter_gas"
@@ -34,6 +36,7 @@ func RouteService(serviceRegistry adapter.ServiceRegistry) []*router.Routing {
 		routeCmdMeterExportGetReport(serviceRegistry),
 		routeCmdMeterExtGetReport(serviceRegistry),
 		routeCmdMeterReset(serviceRegistry),
+		routeCmdMeterGetHistory(serviceRegistry),
 	}
 }
 
@@ -181,6 +184,39 @@ func handleCmdMeterReset(serviceRegistry adapter.ServiceRegistry) router.Message
 	)
 }
 
+func routeCmdMeterGetHistory(serviceRegistry adapter.ServiceRegistry) *router.Routing {
+	return router.NewRouting(
+		handleCmdMeterGetHistory(serviceRegistry),
+		router.ForServicePrefix(prefix),
+		router.ForType(CmdMeterGetHistory),
+	)
+}
+
+func handleCmdMeterGetHistory(serviceRegistry adapter.ServiceRegistry) router.MessageHandler {
+	return router.NewMessageHandler(
+		router.MessageProcessorFn(func(message *fimpgo.Message) (reply *fimpgo.FimpMessage, err error) {
+			meter, err := getService(serviceRegistry, message)
+			if err != nil {
+				return nil, err
+			}
+
+			request := &HistoryRequest{}
+
+			err = message.Payload.GetObjectValue(request)
+			if err != nil {
+				return nil, fmt.Errorf("provided history request has an incorrect format: %w", err)
+			}
+
+			err = meter.SendMeterHistoryReport(request)
+			if err != nil {
+				return nil, fmt.Errorf("failed to send meter history report: %w", err)
+			}
+
+			return nil, nil
+		}),
+	)
+}
+
 // unitsToReport is a helper method that determines which units should be reported.
 func unitsToReport(message *fimpgo.Message, supportedUnits Units) (Units, error) {
 	if message.Payload.ValueType == fimptype.VTypeNull {
@@ -2,6 +2,7 @@ package numericmeter
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -9,9 +10,11 @@ import (
 
 	"github.com/futurehomeno/fimpgo"
 	"github.com/futurehomeno/fimpgo/fimptype"
+	log "github.com/sirupsen/logrus"
 
 	"github.com/futurehomeno/cliffhanger/adapter"
 	"github.com/futurehomeno/cliffhanger/adapter/cache"
+	"github.com/futurehomeno/cliffhanger/adapter/unitconv"
 )
 
 // DefaultReportingStrategy is the default reporting strategy used by the service for periodic reports.
@@ -56,25 +59,41 @@ type Service interface {
 	SendMeterExportReport(unit Unit, force bool) (bool, error)
 	// SendMeterExtendedReport sends an extended meter report based on requested values. Returns true if a report was sent.
 	SendMeterExtendedReport(values Values, force bool) (bool, error)
+	// SendMeterHistoryReport sends a report of consumption within the requested range and resolution.
+	SendMeterHistoryReport(request *HistoryRequest) error
 	// ResetMeter resets the meter.
 	ResetMeter() error
 	// SupportedUnits returns units that are supported by the simplified meter report.
 	SupportedUnits() Units
 	// SupportedExportUnits returns units that are supported by the simplified meter export report.
 	SupportedExportUnits() Units
+	// ReportedUnits returns units of the simplified meter report sent periodically. Units obtained by conversion are reported on request only.
+	ReportedUnits() Units
+	// ReportedExportUnits returns units of the simplified meter export report sent periodically. Units obtained by conversion are reported
+	// on request only.
+	ReportedExportUnits() Units
 	// SupportedExtendedValues returns extended values that are supported by the extended meter report.
 	SupportedExtendedValues() Values
 	// SupportsExportReport returns true if meter supports the export report.
 	SupportsExportReport() bool
 	// SupportsExtendedReport returns true if meter supports the extended report.
 	SupportsExtendedReport() bool
+	// SupportsHistory returns true if meter keeps the history of readings.
+	SupportsHistory() bool
 }
 
 // Config represents a service configuration.
 type Config struct {
-	Specification     *fimptype.Service
-	Reporter          Reporter
-	ReportingStrategy cache.ReportingStrategy
+	Specification      *fimptype.Service
+	Reporter           Reporter
+	ReportingStrategy  cache.ReportingStrategy
+	ReportingCache     cache.ReportingCache
+	PollingCoordinator adapter.PollingCoordinator
+	// History is an optional history of readings in cumulative units, recorded whenever a meter report is retrieved.
+	History History
+	// UnitRegistry is an optional registry of unit conversions. If provided, the service supports all units convertible
+	// from the units of the specification, while the reporter is always asked for a value in one of the latter.
+	UnitRegistry unitconv.Registry
 }
 
 // NewService creates new instance of a meter FIMP service.
@@ -88,12 +107,32 @@ func NewService(
 		cfg.ReportingStrategy = DefaultReportingStrategy
 	}
 
+	if cfg.ReportingCache == nil {
+		cfg.ReportingCache = cache.NewReportingCache()
+	}
+
+	nativeUnits := NewUnits(cfg.Specification.PropertyStrings(PropertySupportedUnits)...)
+	nativeExportUnits := NewUnits(cfg.Specification.PropertyStrings(PropertySupportedExportUnits)...)
+
+	if cfg.UnitRegistry != nil {
+		cfg.Specification.Props[PropertySupportedUnits] = NewUnits(cfg.UnitRegistry.Expand(nativeUnits.Strings())...)
+
+		if len(nativeExportUnits) > 0 {
+			cfg.Specification.Props[PropertySupportedExportUnits] = NewUnits(cfg.UnitRegistry.Expand(nativeExportUnits.Strings())...)
+		}
+	}
+
 	s := &service{
-		Service:           adapter.NewService(publisher, cfg.Specification),
-		reporter:          cfg.Reporter,
-		lock:              &sync.Mutex{},
-		reportingStrategy: cfg.ReportingStrategy,
-		reportingCache:    cache.NewReportingCache(),
+		Service:            adapter.NewService(publisher, cfg.Specification),
+		reporter:           cfg.Reporter,
+		lock:               &sync.Mutex{},
+		reportingStrategy:  cfg.ReportingStrategy,
+		reportingCache:     cfg.ReportingCache,
+		pollingCoordinator: cfg.PollingCoordinator,
+		history:            cfg.History,
+		unitRegistry:       cfg.UnitRegistry,
+		nativeUnits:        nativeUnits,
+		nativeExportUnits:  nativeExportUnits,
 	}
 
 	if s.SupportsExportReport() {
@@ -108,6 +147,10 @@ func NewService(
 		cfg.Specification.EnsureInterfaces(resetInterfaces()...)
 	}
 
+	if s.SupportsHistory() {
+		cfg.Specification.EnsureInterfaces(historyInterfaces()...)
+	}
+
 	return s
 }
 
@@ -115,10 +158,20 @@ func NewService(
 type service struct {
 	adapter.Service
 
-	reporter          Reporter
-	lock              *sync.Mutex
-	reportingCache    cache.ReportingCache
-	reportingStrategy cache.ReportingStrategy
+	reporter           Reporter
+	lock               *sync.Mutex
+	reportingCache     cache.ReportingCache
+	reportingStrategy  cache.ReportingStrategy
+	pollingCoordinator adapter.PollingCoordinator
+	history            History
+	unitRegistry       unitconv.Registry
+	nativeUnits        Units
+	nativeExportUnits  Units
+}
+
+// OverrideReportingStrategy overrides the reporting strategy of the service. Nil restores the configured strategy.
+func (s *service) OverrideReportingStrategy(strategy cache.ReportingStrategy) {
+	s.reportingCache.OverrideStrategy(strategy)
 }
 
 // SendMeterReport sends a simplified meter report based on requested unit. Returns true if a report was sent.
@@ -131,11 +184,18 @@ func (s *service) SendMeterReport(unit Unit, force bool) (bool, error) {
 		return false, fmt.Errorf("%s: unit is unsupported: %s", s.Name(), unit)
 	}
 
-	value, err := s.reporter.MeterReport(unit)
+	err := adapter.PollDataSources(s.pollingCoordinator, s.reporter)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to poll data sources: %w", s.Name(), err)
+	}
+
+	value, err := s.convertedReport(s.reporter.MeterReport, unit, normalizedUnit, s.nativeUnits)
 	if err != nil {
 		return false, fmt.Errorf("%s: failed to retrieve meter report: %w", s.Name(), err)
 	}
 
+	s.recordHistory(normalizedUnit, value)
+
 	if !force && !s.reportingCache.ReportRequired(s.reportingStrategy, EvtMeterReport, normalizedUnit.String(), value) {
 		return false, nil
 	}
@@ -177,7 +237,12 @@ func (s *service) SendMeterExportReport(unit Unit, force bool) (bool, error) {
 		return false, fmt.Errorf("%s: unit is unsupported: %s", s.Name(), unit)
 	}
 
-	value, err := exportReporter.MeterExportReport(unit)
+	err = adapter.PollDataSources(s.pollingCoordinator, s.reporter)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to poll data sources: %w", s.Name(), err)
+	}
+
+	value, err := s.convertedReport(exportReporter.MeterExportReport, unit, normalizedUnit, s.nativeExportUnits)
 	if err != nil {
 		return false, fmt.Errorf("%s: failed to retrieve meter export report: %w", s.Name(), err)
 	}
@@ -223,6 +288,11 @@ func (s *service) SendMeterExtendedReport(extendedValues Values, force bool) (bo
 		return false, fmt.Errorf("%s: failed to normalize extended values: %w", s.Name(), err)
 	}
 
+	err = adapter.PollDataSources(s.pollingCoordinator, s.reporter)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to poll data sources: %w", s.Name(), err)
+	}
+
 	values, err := extendedReporter.MeterExtendedReport(normalizedExtendedValues)
 	if err != nil {
 		return false, fmt.Errorf("%s: failed to retrieve extended meter report: %w", s.Name(), err)
@@ -253,6 +323,42 @@ func (s *service) SendMeterExtendedReport(extendedValues Values, force bool) (bo
 	return true, nil
 }
 
+// SendMeterHistoryReport sends a report of consumption within the requested range and resolution.
+func (s *service) SendMeterHistoryReport(request *HistoryRequest) error {
+	if !s.SupportsHistory() {
+		return fmt.Errorf("%s: meter history is not supported", s.Name())
+	}
+
+	// History is recorded in native units only.
+	normalizedUnit, ok := s.normalizeUnit(request.Unit, s.nativeUnits)
+	if !ok || !isHistoryUnit(normalizedUnit) {
+		return fmt.Errorf("%s: unit is unsupported by the meter history: %s", s.Name(), request.Unit)
+	}
+
+	request.Unit = normalizedUnit
+
+	report, err := s.history.Query(request)
+	if err != nil {
+		return fmt.Errorf("%s: failed to query meter history: %w", s.Name(), err)
+	}
+
+	message := fimpgo.NewObjectMessage(
+		EvtMeterHistoryReport,
+		s.Name(),
+		report,
+		nil,
+		nil,
+		nil,
+	)
+
+	err = s.SendMessage(message)
+	if err != nil {
+		return fmt.Errorf("%s: failed to send meter history report for unit %s: %w", s.Name(), normalizedUnit, err)
+	}
+
+	return nil
+}
+
 // ResetMeter resets the meter.
 func (s *service) ResetMeter() error {
 	s.lock.Lock()
@@ -268,6 +374,8 @@ func (s *service) ResetMeter() error {
 		return fmt.Errorf("%s: failed to reset meter: %w", s.Name(), err)
 	}
 
+	adapter.ResetDataSources(s.pollingCoordinator, s.reporter)
+
 	return nil
 }
 
@@ -281,6 +389,17 @@ func (s *service) SupportedExportUnits() Units {
 	return NewUnits(s.Specification().PropertyStrings(PropertySupportedExportUnits)...)
 }
 
+// ReportedUnits returns units of the simplified meter report sent periodically. Units obtained by conversion are reported on request only.
+func (s *service) ReportedUnits() Units {
+	return s.nativeUnits
+}
+
+// ReportedExportUnits returns units of the simplified meter export report sent periodically. Units obtained by conversion are reported
+// on request only.
+func (s *service) ReportedExportUnits() Units {
+	return s.nativeExportUnits
+}
+
 // SupportedExtendedValues returns extended values that are supported by the extended meter report.
 func (s *service) SupportedExtendedValues() Values {
 	return NewValues(s.Specification().PropertyStrings(PropertySupportedExtendedValues)...)
@@ -328,6 +447,23 @@ func (s *service) extendedReporter() (ExtendedReporter, error) {
 	return reporter, nil
 }
 
+// SupportsHistory returns true if meter keeps the history of readings.
+func (s *service) SupportsHistory() bool {
+	return s.history != nil
+}
+
+// recordHistory records the reading in the meter history if the unit is cumulative.
+func (s *service) recordHistory(unit Unit, value float64) {
+	// Readings in converted units are not recorded, as they would duplicate readings of the native ones.
+	if !s.SupportsHistory() || !isHistoryUnit(unit) || !slices.Contains(s.nativeUnits, unit) {
+		return
+	}
+
+	if err := s.history.Record(unit, value, time.Now()); err != nil {
+		log.WithError(err).Errorf("%s: failed to record meter history for unit %s", s.Name(), unit)
+	}
+}
+
 // SupportsMeterReset returns true if meter supports the reset.
 func (s *service) SupportsMeterReset() bool {
 	_, err := s.resettableReporter()
@@ -345,6 +481,25 @@ func (s *service) resettableReporter() (ResettableReporter, error) {
 	return reporter, nil
 }
 
+// convertedReport returns the report in the requested unit, converting it from a native unit of the reporter if needed.
+func (s *service) convertedReport(report func(Unit) (float64, error), unit, normalizedUnit Unit, nativeUnits Units) (float64, error) {
+	if s.unitRegistry == nil {
+		return report(unit)
+	}
+
+	nativeUnit, ok := s.unitRegistry.Resolve(normalizedUnit.String(), nativeUnits.Strings())
+	if !ok || nativeUnit == normalizedUnit.String() {
+		return report(unit)
+	}
+
+	value, err := report(Unit(nativeUnit))
+	if err != nil {
+		return 0, err
+	}
+
+	return s.unitRegistry.Convert(value, nativeUnit, normalizedUnit.String())
+}
+
 // normalizeUnit checks if unit is supported and returns its normalized form.
 func (s *service) normalizeUnit(unit Unit, units Units) (Unit, bool) {
 	for _, u := range units {
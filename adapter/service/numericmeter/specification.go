@@ -109,6 +109,24 @@ func exportInterfaces() []fimptype.Interface {
 	}
 }
 
+// historyInterfaces returns interfaces supported by the service keeping the history of readings.
+func historyInterfaces() []fimptype.Interface {
+	return []fimptype.Interface{
+		{
+			Type:      fimptype.TypeIn,
+			MsgType:   CmdMeterGetHistory,
+			ValueType: fimptype.VTypeObject,
+			Version:   "1",
+		},
+		{
+			Type:      fimptype.TypeOut,
+			MsgType:   EvtMeterHistoryReport,
+			ValueType: fimptype.VTypeObject,
+			Version:   "1",
+		},
+	}
+}
+
 // extendedInterfaces returns interfaces supported by the extended service.
 func extendedInterfaces() []fimptype.Interface {
 	return []fimptype.Interface{
@@ -50,7 +50,7 @@ func handlePeriodicReporting(meter Service) {
 		return
 	}
 
-	for _, unit := range meter.SupportedUnits() {
+	for _, unit := range meter.ReportedUnits() {
 		_, err := meter.SendMeterReport(unit, false)
 		if err != nil {
 			log.WithError(err).Errorf("failed to send meter report for unit: %s", unit)
@@ -61,7 +61,7 @@ func handlePeriodicReporting(meter Service) {
 		return
 	}
 
-	for _, unit := range meter.SupportedExportUnits() {
+	for _, unit := range meter.ReportedExportUnits() {
 		_, err := meter.SendMeterExportReport(unit, false)
 		if err != nil {
 			log.WithError(err).Errorf("failed to send meter export report for unit: %s", unit)
@@ -1,13 +1,16 @@
 package numericmeter_test
 
 import (
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/futurehomeno/fimpgo"
 	"github.com/futurehomeno/fimpgo/fimptype"
+	"github.com/stretchr/testify/assert"
 
 	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/adapter/cache"
 	"github.com/futurehomeno/cliffhanger/adapter/service/numericmeter"
 	"github.com/futurehomeno/cliffhanger/router"
 	"github.com/futurehomeno/cliffhanger/task"
@@ -49,6 +52,131 @@ func TestTaskReporting(t *testing.T) { //nolint:paralleltest
 	s.Run(t)
 }
 
+func TestTaskReporting_PollingCoordinator(t *testing.T) { //nolint:paralleltest
+	source := &testSharedSource{}
+
+	s := &cliffSuite.Suite{
+		Cases: []*cliffSuite.Case{
+			{
+				Name:     "meters sharing a data source",
+				TearDown: adapterhelper.TearDownAdapter("../../testdata/adapter/test_adapter"),
+				Setup:    taskSharedSourceMeters(source, 100*time.Millisecond),
+				Nodes: []*cliffSuite.Node{
+					{
+						Name: "both meters report values of the shared source",
+						Expectations: []*cliffSuite.Expectation{
+							cliffSuite.ExpectFloat(meterEvtTopic, numericmeter.EvtMeterReport, numericmeter.MeterElec, 1.0).ExactlyOnce(),
+							cliffSuite.ExpectFloat(meterEvtTopic, numericmeter.EvtMeterReport, numericmeter.MeterElec, 2.0).ExactlyOnce(),
+							cliffSuite.ExpectFloat(meterEvtTopicC, numericmeter.EvtMeterReport, numericmeter.MeterElec, 10.0).ExactlyOnce(),
+							cliffSuite.ExpectFloat(meterEvtTopicC, numericmeter.EvtMeterReport, numericmeter.MeterElec, 20.0).ExactlyOnce(),
+						},
+					},
+					{
+						Name: "source is fetched once per cycle",
+						InitCallbacks: []cliffSuite.Callback{
+							func(t *testing.T) {
+								t.Helper()
+
+								fetches, reads := source.counts()
+								assert.Less(t, fetches, reads)
+								assert.Greater(t, fetches, 1)
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	s.Run(t)
+}
+
+const meterEvtTopicC = "pt:j1/mt:evt/rt:dev/rn:test_adapter/ad:1/sv:meter_elec/ad:3"
+
+// testSharedSource is a data source shared by meters, incrementing the value on every fetch.
+type testSharedSource struct {
+	lock    sync.Mutex
+	value   float64
+	fetches int
+	reads   int
+}
+
+func (s *testSharedSource) Fetch() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.fetches++
+	s.value++
+
+	return nil
+}
+
+func (s *testSharedSource) counts() (int, int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.fetches, s.reads
+}
+
+// testSharedReporter is a meter reporter reading values of the shared source multiplied by a factor.
+type testSharedReporter struct {
+	source *testSharedSource
+	factor float64
+}
+
+func (r *testSharedReporter) DataSources() []string {
+	return []string{"shared"}
+}
+
+func (r *testSharedReporter) MeterReport(_ numericmeter.Unit) (float64, error) {
+	r.source.lock.Lock()
+	defer r.source.lock.Unlock()
+
+	r.source.reads++
+
+	return r.source.value * r.factor, nil
+}
+
+func taskSharedSourceMeters(source *testSharedSource, interval time.Duration) cliffSuite.BaseSetup {
+	return func(t *testing.T, mqtt *fimpgo.MqttTransport) ([]*router.Routing, []*task.Task, []cliffSuite.Mock) {
+		t.Helper()
+
+		coordinator := adapter.NewPollingCoordinator(interval)
+		coordinator.Register("shared", source, 0)
+
+		factory := adapterhelper.FactoryHelper(func(a adapter.Adapter, p adapter.Publisher, ts adapter.ThingState) (adapter.Thing, error) {
+			factor := 1.0
+			if ts.Address() == "3" {
+				factor = 10.0
+			}
+
+			return adapter.NewThing(p, ts, &adapter.ThingConfig{
+				InclusionReport: &fimptype.ThingInclusionReport{Address: ts.Address()},
+				Connector:       mockedadapter.NewDefaultConnector(t),
+			}, numericmeter.NewService(p, &numericmeter.Config{
+				Specification: numericmeter.Specification(
+					numericmeter.MeterElec,
+					"test_adapter",
+					a.Address(),
+					ts.Address(),
+					nil,
+					numericmeter.Units{numericmeter.UnitKWh},
+				),
+				Reporter:           &testSharedReporter{source: source, factor: factor},
+				ReportingStrategy:  cache.ReportOnChangeOnly(),
+				PollingCoordinator: coordinator,
+			})), nil
+		})
+
+		ad := adapterhelper.PrepareSeededAdapter(t, "../../testdata/adapter/test_adapter", mqtt, factory, adapter.ThingSeeds{
+			{ID: "B", CustomAddress: "2"},
+			{ID: "C", CustomAddress: "3"},
+		})
+
+		return nil, []*task.Task{numericmeter.TaskReporting(ad, interval)}, nil
+	}
+}
+
 func taskMeter(reporter *mockednumericmeter.Reporter, interval time.Duration) cliffSuite.BaseSetup {
 	return func(t *testing.T, mqtt *fimpgo.MqttTransport) ([]*router.Routing, []*task.Task, []cliffSuite.Mock) {
 		t.Helper()
@@ -11,6 +11,7 @@ import (
 
 	"github.com/futurehomeno/cliffhanger/adapter"
 	"github.com/futurehomeno/cliffhanger/adapter/cache"
+	"github.com/futurehomeno/cliffhanger/adapter/unitconv"
 )
 
 // Constants defining important properties specific for the service.
@@ -52,13 +53,20 @@ type Service interface {
 	SendSensorReport(unit string, force bool) (bool, error)
 	// SupportedUnits returns units that are supported by the numeric sensor report.
 	SupportedUnits() []string
+	// ReportedUnits returns units of the numeric sensor report sent periodically. Units obtained by conversion are reported on request only.
+	ReportedUnits() []string
 }
 
 // Config represents a service configuration.
 type Config struct {
-	Specification     *fimptype.Service
-	Reporter          Reporter
-	ReportingStrategy cache.ReportingStrategy
+	Specification      *fimptype.Service
+	Reporter           Reporter
+	ReportingStrategy  cache.ReportingStrategy
+	ReportingCache     cache.ReportingCache
+	PollingCoordinator adapter.PollingCoordinator
+	// UnitRegistry is an optional registry of unit conversions. If provided, the service supports all units convertible
+	// from the units of the specification, while the reporter is always asked for a value in one of the latter.
+	UnitRegistry unitconv.Registry
 }
 
 // NewService creates new instance of a numeric sensor FIMP service.
@@ -72,12 +80,24 @@ func NewService(
 		cfg.ReportingStrategy = DefaultReportingStrategy
 	}
 
+	if cfg.ReportingCache == nil {
+		cfg.ReportingCache = cache.NewReportingCache()
+	}
+
+	nativeUnits := cfg.Specification.PropertyStrings(PropertySupportedUnits)
+	if cfg.UnitRegistry != nil {
+		cfg.Specification.Props[PropertySupportedUnits] = cfg.UnitRegistry.Expand(nativeUnits)
+	}
+
 	return &service{
-		Service:           adapter.NewService(publisher, cfg.Specification),
-		sensor:            cfg.Reporter,
-		lock:              &sync.Mutex{},
-		reportingStrategy: cfg.ReportingStrategy,
-		reportingCache:    cache.NewReportingCache(),
+		Service:            adapter.NewService(publisher, cfg.Specification),
+		sensor:             cfg.Reporter,
+		lock:               &sync.Mutex{},
+		reportingStrategy:  cfg.ReportingStrategy,
+		reportingCache:     cfg.ReportingCache,
+		pollingCoordinator: cfg.PollingCoordinator,
+		unitRegistry:       cfg.UnitRegistry,
+		nativeUnits:        nativeUnits,
 	}
 }
 
@@ -85,10 +105,18 @@ func NewService(
 type service struct {
 	adapter.Service
 
-	sensor            Reporter
-	lock              *sync.Mutex
-	reportingCache    cache.ReportingCache
-	reportingStrategy cache.ReportingStrategy
+	sensor             Reporter
+	lock               *sync.Mutex
+	reportingCache     cache.ReportingCache
+	reportingStrategy  cache.ReportingStrategy
+	pollingCoordinator adapter.PollingCoordinator
+	unitRegistry       unitconv.Registry
+	nativeUnits        []string
+}
+
+// OverrideReportingStrategy overrides the reporting strategy of the service. Nil restores the configured strategy.
+func (s *service) OverrideReportingStrategy(strategy cache.ReportingStrategy) {
+	s.reportingCache.OverrideStrategy(strategy)
 }
 
 // SendSensorReport sends a numeric sensor report based on requested unit. Returns true if a report was sent.
@@ -103,7 +131,12 @@ func (s *service) SendSensorReport(unit string, force bool) (bool, error) {
 		return false, fmt.Errorf("%s: unit is unsupported: %s", s.Name(), unit)
 	}
 
-	value, err := s.sensor.NumericSensorReport(unit)
+	err := adapter.PollDataSources(s.pollingCoordinator, s.sensor)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to poll data sources: %w", s.Name(), err)
+	}
+
+	value, err := s.sensorReport(unit, normalizedUnit)
 	if err != nil {
 		return false, fmt.Errorf("%s: failed to retrieve sensor report: %w", s.Name(), err)
 	}
@@ -138,6 +171,30 @@ func (s *service) SupportedUnits() []string {
 	return s.Service.Specification().PropertyStrings(PropertySupportedUnits)
 }
 
+// ReportedUnits returns units of the numeric sensor report sent periodically. Units obtained by conversion are reported on request only.
+func (s *service) ReportedUnits() []string {
+	return s.nativeUnits
+}
+
+// sensorReport returns the sensor value in the requested unit, converting it from a native unit of the reporter if needed.
+func (s *service) sensorReport(unit, normalizedUnit string) (float64, error) {
+	if s.unitRegistry == nil {
+		return s.sensor.NumericSensorReport(unit)
+	}
+
+	nativeUnit, ok := s.unitRegistry.Resolve(normalizedUnit, s.nativeUnits)
+	if !ok || nativeUnit == normalizedUnit {
+		return s.sensor.NumericSensorReport(unit)
+	}
+
+	value, err := s.sensor.NumericSensorReport(nativeUnit)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.unitRegistry.Convert(value, nativeUnit, normalizedUnit)
+}
+
 // normalizeUnit checks if unit is supported and returns its normalized form.
 func (s *service) normalizeUnit(unit string) (string, bool) {
 	for _, u := range s.SupportedUnits() {
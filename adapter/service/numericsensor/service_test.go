@@ -0,0 +1,40 @@
+package numericsensor_test
+
+import (
+	"testing"
+
+	"github.com/futurehomeno/fimpgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/futurehomeno/cliffhanger/adapter/service/numericsensor"
+	"github.com/futurehomeno/cliffhanger/adapter/unitconv"
+	mockedadapter "github.com/futurehomeno/cliffhanger/test/mocks/adapter"
+	mockednumericsensor "github.com/futurehomeno/cliffhanger/test/mocks/adapter/service/numericsensor"
+)
+
+func TestService_UnitRegistry(t *testing.T) {
+	t.Parallel()
+
+	publisher := mockedadapter.NewServicePublisher(t)
+	reporter := mockednumericsensor.NewReporter(t).MockNumericSensorReport("C", 21.5, nil, true)
+
+	s := numericsensor.NewService(publisher, &numericsensor.Config{
+		Specification: numericsensor.Specification("test_adapter", "1", numericsensor.SensorTemp, "2", nil, []string{"C"}),
+		Reporter:      reporter,
+		UnitRegistry:  unitconv.NewDefaultRegistry(),
+	})
+
+	assert.ElementsMatch(t, []string{"C", "F", "K"}, s.SupportedUnits())
+	assert.Equal(t, []string{"C"}, s.ReportedUnits(), "converted units are not reported periodically")
+
+	publisher.On("PublishServiceMessage", mock.Anything, mock.MatchedBy(func(m *fimpgo.FimpMessage) bool {
+		value, err := m.GetFloatValue()
+
+		return err == nil && m.Properties["unit"] == "F" && value == 70.7
+	})).Return(nil).Once()
+
+	sent, err := s.SendSensorReport("f", true)
+	assert.NoError(t, err)
+	assert.True(t, sent)
+}
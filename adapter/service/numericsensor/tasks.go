@@ -34,7 +34,7 @@ func handleReporting(serviceRegistry adapter.ServiceRegistry) func() {
 				continue
 			}
 
-			for _, unit := range sensor.SupportedUnits() {
+			for _, unit := range sensor.ReportedUnits() {
 				_, err := sensor.SendSensorReport(unit, false)
 				if err != nil {
 					log.WithError(err).Errorf("failed to send sensor report for unit: %s", unit)
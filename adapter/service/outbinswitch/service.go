@@ -35,6 +35,7 @@ type Config struct {
 	Specification     *fimptype.Service
 	Controller        Controller
 	ReportingStrategy cache.ReportingStrategy
+	ReportingCache    cache.ReportingCache
 }
 
 // NewService creates a new instance of a output binary switch FIMP service.
@@ -48,12 +49,16 @@ func NewService(
 		cfg.ReportingStrategy = DefaultReportingStrategy
 	}
 
+	if cfg.ReportingCache == nil {
+		cfg.ReportingCache = cache.NewReportingCache()
+	}
+
 	return &service{
 		Service:           adapter.NewService(publisher, cfg.Specification),
 		controller:        cfg.Controller,
 		reportingStrategy: cfg.ReportingStrategy,
 
-		reportingCache: cache.NewReportingCache(),
+		reportingCache: cfg.ReportingCache,
 		lock:           &sync.Mutex{},
 	}
 }
@@ -68,6 +73,11 @@ type service struct {
 	reportingStrategy cache.ReportingStrategy
 }
 
+// OverrideReportingStrategy overrides the reporting strategy of the service. Nil restores the configured strategy.
+func (s *service) OverrideReportingStrategy(strategy cache.ReportingStrategy) {
+	s.reportingCache.OverrideStrategy(strategy)
+}
+
 // SendBinaryReport sends a binary report. Returns true if a report was sent.
 func (s *service) SendBinaryReport(force bool) (bool, error) {
 	s.lock.Lock()
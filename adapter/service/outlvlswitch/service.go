@@ -85,6 +85,7 @@ type Config struct {
 	Specification     *fimptype.Service
 	Controller        Controller
 	ReportingStrategy cache.ReportingStrategy
+	ReportingCache    cache.ReportingCache
 }
 
 // NewService creates new instance of a output level switch FIMP service.
@@ -98,12 +99,16 @@ func NewService(
 		cfg.ReportingStrategy = DefaultReportingStrategy
 	}
 
+	if cfg.ReportingCache == nil {
+		cfg.ReportingCache = cache.NewReportingCache()
+	}
+
 	s := &service{
 		Service:           adapter.NewService(publisher, cfg.Specification),
 		lock:              &sync.Mutex{},
 		controller:        cfg.Controller,
 		reportingStrategy: cfg.ReportingStrategy,
-		reportingCache:    cache.NewReportingCache(),
+		reportingCache:    cfg.ReportingCache,
 	}
 
 	if s.supportsLevelTransition() {
@@ -123,6 +128,11 @@ type service struct {
 	reportingStrategy cache.ReportingStrategy
 }
 
+// OverrideReportingStrategy overrides the reporting strategy of the service. Nil restores the configured strategy.
+func (s *service) OverrideReportingStrategy(strategy cache.ReportingStrategy) {
+	s.reportingCache.OverrideStrategy(strategy)
+}
+
 // SendLevelReport sends a level report. Returns true if a report was sent.
 // Depending on a caching and reporting configuration the service might decide to skip a report.
 // To make sure report is being sent regardless of circumstances set the force argument to true.
@@ -37,8 +37,9 @@ type Service interface {
 
 // Config represents a service configuration.
 type Config struct {
-	Specification *fimptype.Service
-	Controller    Controller
+	Specification  *fimptype.Service
+	Controller     Controller
+	ReportingCache cache.ReportingCache
 }
 
 // NewService creates new instance of a parameters FIMP service.
@@ -49,11 +50,15 @@ func NewService(
 	cfg.Specification.Name = Parameters
 	cfg.Specification.EnsureInterfaces(requiredInterfaces()...)
 
+	if cfg.ReportingCache == nil {
+		cfg.ReportingCache = cache.NewReportingCache()
+	}
+
 	return &service{
 		Service:           adapter.NewService(publisher, cfg.Specification),
 		controller:        cfg.Controller,
 		lock:              &sync.Mutex{},
-		reportingCache:    cache.NewReportingCache(),
+		reportingCache:    cfg.ReportingCache,
 		reportingStrategy: cache.ReportOnChangeOnly(),
 	}
 }
@@ -68,6 +73,11 @@ type service struct {
 	reportingStrategy cache.ReportingStrategy
 }
 
+// OverrideReportingStrategy overrides the reporting strategy of the service. Nil restores the configured strategy.
+func (s *service) OverrideReportingStrategy(strategy cache.ReportingStrategy) {
+	s.reportingCache.OverrideStrategy(strategy)
+}
+
 func (s *service) SetParameter(p *Parameter) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
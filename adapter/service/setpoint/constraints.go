@@ -0,0 +1,106 @@
+package setpoint
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/futurehomeno/fimpgo/fimptype"
+)
+
+// Constants defining handling of setpoints violating constraints of a service.
+const (
+	OutOfRangeReject OutOfRangePolicy = "reject"
+	OutOfRangeClamp  OutOfRangePolicy = "clamp"
+
+	RoundNearest RoundingMode = "nearest"
+	RoundDown    RoundingMode = "down"
+	RoundUp      RoundingMode = "up"
+)
+
+// ErrOutOfRange is returned when a setpoint is outside the supported range and the out of range policy is OutOfRangeReject.
+var ErrOutOfRange = errors.New("setpoint is out of range")
+
+// OutOfRangePolicy defines how a setpoint outside the supported range is handled.
+type OutOfRangePolicy string
+
+// RoundingMode defines how a setpoint is aligned with the supported step.
+type RoundingMode string
+
+// Constraint represents a supported range and step of a setpoint of a specific mode.
+// Zero step means that the setpoint is not aligned with any step.
+type Constraint struct {
+	Min  float64
+	Max  float64
+	Step float64
+}
+
+// supportedRange is a model of a supported range published in the specification.
+type supportedRange struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// Apply publishes setpoint constraints as supported ranges and steps under the provided properties of the specification.
+// Constraints complement the ranges and steps already present in the specification, overriding them for the configured modes.
+func Apply(specification *fimptype.Service, constraints map[string]Constraint, rangesProperty, stepsProperty string) {
+	if len(constraints) == 0 {
+		return
+	}
+
+	ranges := make(map[string]supportedRange)
+	steps := make(map[string]float64)
+
+	_ = specification.PropertyObject(rangesProperty, &ranges)
+	_ = specification.PropertyObject(stepsProperty, &steps)
+
+	for mode, constraint := range constraints {
+		ranges[mode] = supportedRange{Min: constraint.Min, Max: constraint.Max}
+
+		if constraint.Step > 0 {
+			steps[mode] = constraint.Step
+		}
+	}
+
+	if specification.Props == nil {
+		specification.Props = make(map[string]any)
+	}
+
+	specification.Props[rangesProperty] = ranges
+
+	if len(steps) > 0 {
+		specification.Props[stepsProperty] = steps
+	}
+}
+
+// RoundToStep aligns the value with the step using the provided rounding mode.
+func RoundToStep(value, step float64, mode RoundingMode) float64 {
+	if step <= 0 {
+		return value
+	}
+
+	// Tolerance prevents floating point errors from moving values already aligned with the step.
+	const tolerance = 1e-9
+
+	switch mode {
+	case RoundDown:
+		return math.Floor(value/step+tolerance) * step
+	case RoundUp:
+		return math.Ceil(value/step-tolerance) * step
+	default:
+		return math.Round(value/step) * step
+	}
+}
+
+// Constrain checks if the value of the mode is within the supported range and clamps it if required by the policy.
+func Constrain(mode string, value, minValue, maxValue float64, policy OutOfRangePolicy) (float64, error) {
+	if value >= minValue && value <= maxValue {
+		return value, nil
+	}
+
+	if policy == OutOfRangeClamp {
+		return math.Min(math.Max(value, minValue), maxValue), nil
+	}
+
+	return 0, fmt.Errorf("%w: value %.01f is outside of range %.01f - %.01f for mode %s", ErrOutOfRange, value, minValue, maxValue, mode)
+}
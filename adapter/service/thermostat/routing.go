@@ -6,6 +6,7 @@ import (
 	"github.com/futurehomeno/fimpgo"
 
 	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/adapter/schedule"
 	"github.com/futurehomeno/cliffhanger/router"
 )
 
@@ -18,6 +19,9 @@ const (
 	EvtSetpointReport    = "evt.setpoint.report"
 	CmdStateGetReport    = "cmd.state.get_report"
 	EvtStateReport       = "evt.state.report"
+	CmdScheduleSet       = schedule.CmdScheduleSet
+	CmdScheduleGetReport = schedule.CmdScheduleGetReport
+	EvtScheduleReport    = schedule.EvtScheduleReport
 
 	Thermostat = "thermostat"
 )
@@ -29,6 +33,8 @@ func RouteService(serviceRegistry adapter.ServiceRegistry) []*router.Routing {
 		RouteCmdModeGetReport(serviceRegistry),
 		RouteCmdSetpointGetReport(serviceRegistry),
 		RouteCmdStateGetReport(serviceRegistry),
+		RouteCmdScheduleSet(serviceRegistry),
+		RouteCmdScheduleGetReport(serviceRegistry),
 	}
 }
 
@@ -223,3 +229,19 @@ func HandleCmdStateGetReport(serviceRegistry adapter.ServiceRegistry) router.Mes
 		}),
 	)
 }
+
+func RouteCmdScheduleSet(serviceRegistry adapter.ServiceRegistry) *router.Routing {
+	return schedule.RouteCmdScheduleSet(serviceRegistry, Thermostat)
+}
+
+func HandleCmdScheduleSet(serviceRegistry adapter.ServiceRegistry) router.MessageHandler {
+	return schedule.HandleCmdScheduleSet(serviceRegistry)
+}
+
+func RouteCmdScheduleGetReport(serviceRegistry adapter.ServiceRegistry) *router.Routing {
+	return schedule.RouteCmdScheduleGetReport(serviceRegistry, Thermostat)
+}
+
+func HandleCmdScheduleGetReport(serviceRegistry adapter.ServiceRegistry) router.MessageHandler {
+	return schedule.HandleCmdScheduleGetReport(serviceRegistry)
+}
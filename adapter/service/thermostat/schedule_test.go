@@ -0,0 +1,72 @@
+package thermostat_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/futurehomeno/cliffhanger/adapter/schedule"
+	"github.com/futurehomeno/cliffhanger/adapter/service/thermostat"
+	mockedadapter "github.com/futurehomeno/cliffhanger/test/mocks/adapter"
+	mockedthermostat "github.com/futurehomeno/cliffhanger/test/mocks/adapter/service/thermostat"
+)
+
+func TestService_Schedule_Concurrency(t *testing.T) {
+	t.Parallel()
+
+	scheduler, err := schedule.NewScheduler(&schedule.SchedulerConfig{})
+	assert.NoError(t, err)
+
+	publisher := mockedadapter.NewServicePublisher(t)
+	publisher.On("PublishServiceMessage", mock.Anything, mock.Anything).Return(nil)
+
+	controller := mockedthermostat.NewController(t).
+		MockSetThermostatMode(thermostat.ModeHeat, nil, false)
+
+	s := thermostat.NewService(publisher, &thermostat.Config{
+		Specification: thermostat.Specification("test", "1", "1", nil, []string{thermostat.ModeHeat}, []string{thermostat.ModeHeat}, nil),
+		Controller:    controller,
+		Scheduler:     scheduler,
+	})
+
+	program := &schedule.Program{
+		Enabled:     true,
+		Transitions: []*schedule.Transition{{Day: schedule.Monday, Time: "00:00", Mode: thermostat.ModeHeat}},
+	}
+
+	assert.NoError(t, s.SetSchedule(program))
+
+	wg := &sync.WaitGroup{}
+	wg.Add(3)
+
+	run := func(fn func()) {
+		defer wg.Done()
+
+		for range 100 {
+			fn()
+		}
+	}
+
+	go run(func() { assert.NoError(t, s.ExecuteSchedule()) })
+	go run(func() { assert.NoError(t, s.SetSchedule(program)) })
+	go run(func() {
+		_, err := s.SendScheduleReport(true)
+		assert.NoError(t, err)
+	})
+
+	done := make(chan struct{})
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("schedule execution deadlocked with schedule commands")
+	}
+}
@@ -11,6 +11,8 @@ import (
 
 	"github.com/futurehomeno/cliffhanger/adapter"
 	"github.com/futurehomeno/cliffhanger/adapter/cache"
+	"github.com/futurehomeno/cliffhanger/adapter/schedule"
+	"github.com/futurehomeno/cliffhanger/adapter/service/setpoint"
 )
 
 // Constants defining important properties specific for the service.
@@ -18,7 +20,8 @@ const (
 	UnitC = "C"
 	UnitF = "F"
 
-	ModeOff = "off"
+	ModeOff  = "off"
+	ModeHeat = "heat"
 
 	StateHeat = "heat"
 	StateIdle = "idle"
@@ -26,6 +29,10 @@ const (
 	PropertySupportedModes     = "sup_modes"
 	PropertySupportedSetpoints = "sup_setpoints"
 	PropertySupportedStates    = "sup_states"
+	PropertySupportedRange     = "sup_range"
+	PropertySupportedRanges    = "sup_ranges"
+	PropertySupportedStep      = "sup_step"
+	PropertySupportedSteps     = "sup_steps"
 )
 
 // DefaultReportingStrategy is the default reporting strategy used by the service for periodic reports.
@@ -46,9 +53,18 @@ type Controller interface {
 	ThermostatStateReport() (string, error)
 }
 
+// ScheduleController is an optional interface representing an actual device with a native weekly program.
+// Devices lacking native support may be given a software schedule using schedule.Scheduler.
+type ScheduleController interface {
+	// SetThermostatSchedule sets a new weekly program.
+	SetThermostatSchedule(program *schedule.Program) error
+	// ThermostatScheduleReport returns the current weekly program.
+	ThermostatScheduleReport() (*schedule.Program, error)
+}
+
 // Service is an interface representing a thermostat FIMP service.
 type Service interface {
-	adapter.Service
+	schedule.Service
 
 	// SetMode sets the mode of the device.
 	SetMode(mode string) error
@@ -78,9 +94,19 @@ type Service interface {
 
 // Config represents a service configuration.
 type Config struct {
-	Specification     *fimptype.Service
-	Controller        Controller
-	ReportingStrategy cache.ReportingStrategy
+	Specification      *fimptype.Service
+	Controller         Controller
+	ReportingStrategy  cache.ReportingStrategy
+	ReportingCache     cache.ReportingCache
+	PollingCoordinator adapter.PollingCoordinator
+	// Scheduler is an optional software scheduler used if the controller does not implement ScheduleController.
+	Scheduler schedule.Scheduler
+	// SetpointConstraints are optional per mode constraints of setpoints, published as supported ranges and steps in the specification.
+	SetpointConstraints map[string]setpoint.Constraint
+	// OutOfRangePolicy defines how setpoints outside the supported range are handled. Defaults to OutOfRangeReject.
+	OutOfRangePolicy setpoint.OutOfRangePolicy
+	// RoundingMode defines how setpoints are aligned with the supported step. Defaults to RoundNearest.
+	RoundingMode setpoint.RoundingMode
 }
 
 // NewService creates new instance of a thermostat FIMP service.
@@ -96,23 +122,68 @@ func NewService(
 		cfg.ReportingStrategy = DefaultReportingStrategy
 	}
 
-	return &service{
-		Service:           adapter.NewService(publisher, cfg.Specification),
-		controller:        cfg.Controller,
-		lock:              &sync.Mutex{},
-		reportingStrategy: cfg.ReportingStrategy,
-		reportingCache:    cache.NewReportingCache(),
+	if cfg.ReportingCache == nil {
+		cfg.ReportingCache = cache.NewReportingCache()
+	}
+
+	if cfg.OutOfRangePolicy == "" {
+		cfg.OutOfRangePolicy = setpoint.OutOfRangeReject
+	}
+
+	if cfg.RoundingMode == "" {
+		cfg.RoundingMode = setpoint.RoundNearest
+	}
+
+	setpoint.Apply(cfg.Specification, cfg.SetpointConstraints, PropertySupportedRanges, PropertySupportedSteps)
+
+	s := &service{
+		Service:            adapter.NewService(publisher, cfg.Specification),
+		controller:         cfg.Controller,
+		lock:               &sync.Mutex{},
+		reportingStrategy:  cfg.ReportingStrategy,
+		reportingCache:     cfg.ReportingCache,
+		pollingCoordinator: cfg.PollingCoordinator,
+		outOfRangePolicy:   cfg.OutOfRangePolicy,
+		roundingMode:       cfg.RoundingMode,
+	}
+
+	s.Handler = schedule.NewHandler(&schedule.HandlerConfig{
+		Service:             s.Service,
+		Executor:            s,
+		Constraints:         s.scheduleConstraints,
+		Controller:          nativeSchedule(cfg.Controller),
+		Scheduler:           cfg.Scheduler,
+		Lock:                s.lock,
+		ReportingCache:      cfg.ReportingCache,
+		ReportingStrategy:   cfg.ReportingStrategy,
+		PollingCoordinator:  cfg.PollingCoordinator,
+		DataSourceDependent: cfg.Controller,
+	})
+
+	if s.SupportsSchedule() {
+		cfg.Specification.EnsureInterfaces(schedule.Interfaces()...)
 	}
+
+	return s
 }
 
 // service is a private implementation of a thermostat FIMP service.
 type service struct {
 	adapter.Service
+	schedule.Handler
+
+	controller         Controller
+	lock               *sync.Mutex
+	reportingCache     cache.ReportingCache
+	reportingStrategy  cache.ReportingStrategy
+	pollingCoordinator adapter.PollingCoordinator
+	outOfRangePolicy   setpoint.OutOfRangePolicy
+	roundingMode       setpoint.RoundingMode
+}
 
-	controller        Controller
-	lock              *sync.Mutex
-	reportingCache    cache.ReportingCache
-	reportingStrategy cache.ReportingStrategy
+// OverrideReportingStrategy overrides the reporting strategy of the service. Nil restores the configured strategy.
+func (s *service) OverrideReportingStrategy(strategy cache.ReportingStrategy) {
+	s.reportingCache.OverrideStrategy(strategy)
 }
 
 // SetMode sets mode of the device.
@@ -130,6 +201,8 @@ func (s *service) SetMode(mode string) error {
 		return fmt.Errorf("%s: failed to set mode %s: %w", s.Name(), normalizedMode, err)
 	}
 
+	adapter.ResetDataSources(s.pollingCoordinator, s.controller)
+
 	return nil
 }
 
@@ -143,11 +216,18 @@ func (s *service) SetSetpoint(mode string, value float64, unit string) error {
 		return fmt.Errorf("%s: setpoint mode is unsupported: %s", s.Name(), mode)
 	}
 
-	err := s.controller.SetThermostatSetpoint(normalizedMode, value, unit)
+	normalizedValue, err := s.normalizeValue(normalizedMode, value)
+	if err != nil {
+		return fmt.Errorf("%s: setpoint value is incorrect: %w", s.Name(), err)
+	}
+
+	err = s.controller.SetThermostatSetpoint(normalizedMode, normalizedValue, unit)
 	if err != nil {
-		return fmt.Errorf("%s: failed to set setpoint for mode %s for value %.01f: %w", s.Name(), normalizedMode, value, err)
+		return fmt.Errorf("%s: failed to set setpoint for mode %s for value %.01f: %w", s.Name(), normalizedMode, normalizedValue, err)
 	}
 
+	adapter.ResetDataSources(s.pollingCoordinator, s.controller)
+
 	return nil
 }
 
@@ -158,6 +238,11 @@ func (s *service) SendModeReport(force bool) (bool, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
+	err := adapter.PollDataSources(s.pollingCoordinator, s.controller)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to poll data sources: %w", s.Name(), err)
+	}
+
 	value, err := s.controller.ThermostatModeReport()
 	if err != nil {
 		return false, fmt.Errorf("%s: failed to retrieve mode report: %w", s.Name(), err)
@@ -198,6 +283,11 @@ func (s *service) SendSetpointReport(mode string, force bool) (bool, error) {
 		return false, fmt.Errorf("%s: setpoint mode is unsupported: %s", s.Name(), mode)
 	}
 
+	err := adapter.PollDataSources(s.pollingCoordinator, s.controller)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to poll data sources: %w", s.Name(), err)
+	}
+
 	value, unit, err := s.controller.ThermostatSetpointReport(normalizedMode)
 	if err != nil {
 		return false, fmt.Errorf("%s: failed to retrieve setpoint report for mode %s: %w", s.Name(), normalizedMode, err)
@@ -233,6 +323,11 @@ func (s *service) SendStateReport(force bool) (bool, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
+	err := adapter.PollDataSources(s.pollingCoordinator, s.controller)
+	if err != nil {
+		return false, fmt.Errorf("%s: failed to poll data sources: %w", s.Name(), err)
+	}
+
 	value, err := s.controller.ThermostatStateReport()
 	if err != nil {
 		return false, fmt.Errorf("%s: failed to retrieve state report: %w", s.Name(), err)
@@ -261,6 +356,14 @@ func (s *service) SendStateReport(force bool) (bool, error) {
 	return true, nil
 }
 
+// scheduleConstraints returns constraints of the weekly program supported by the thermostat.
+func (s *service) scheduleConstraints() *schedule.Constraints {
+	return &schedule.Constraints{
+		Modes:     s.SupportedModes(),
+		Setpoints: s.SupportedSetpoints(),
+	}
+}
+
 // SupportedModes returns modes that are supported by the thermostat.
 func (s *service) SupportedModes() []string {
 	return s.Service.Specification().PropertyStrings(PropertySupportedModes)
@@ -305,6 +408,55 @@ func (s *service) normalizeSetpoint(mode string) (string, bool) {
 	return "", false
 }
 
+// normalizeValue normalizes setpoint value for a specific mode.
+func (s *service) normalizeValue(mode string, value float64) (float64, error) {
+	value = setpoint.RoundToStep(value, s.supportedStep(mode), s.roundingMode)
+
+	supportedRange := s.supportedRange(mode)
+	if supportedRange == nil {
+		return value, nil
+	}
+
+	return setpoint.Constrain(mode, value, supportedRange.Min, supportedRange.Max, s.outOfRangePolicy)
+}
+
+// supportedStep returns step supported for a given mode.
+func (s *service) supportedStep(mode string) float64 {
+	var supportedSteps map[string]float64
+
+	_ = s.Service.Specification().PropertyObject(PropertySupportedSteps, &supportedSteps)
+
+	step, ok := supportedSteps[mode]
+	if ok {
+		return step
+	}
+
+	step, _ = s.Service.Specification().PropertyFloat(PropertySupportedStep)
+
+	return step
+}
+
+// supportedRange returns range supported for a given mode.
+func (s *service) supportedRange(mode string) *Range {
+	var supportedRanges map[string]*Range
+
+	_ = s.Service.Specification().PropertyObject(PropertySupportedRanges, &supportedRanges)
+
+	supportedRange, ok := supportedRanges[mode]
+	if ok {
+		return supportedRange
+	}
+
+	supportedRange = &Range{}
+	ok = s.Service.Specification().PropertyObject(PropertySupportedRange, supportedRange)
+
+	if ok {
+		return supportedRange
+	}
+
+	return nil
+}
+
 // Setpoint is an object representing a Thermostat setpoint.
 type Setpoint struct {
 	Type        string
@@ -358,3 +510,28 @@ func SetpointFromStringMap(input map[string]string) (*Setpoint, error) {
 		Unit:        unit,
 	}, nil
 }
+
+// nativeSchedule returns the native weekly program of the controller, or nil if the controller does not implement ScheduleController.
+func nativeSchedule(controller Controller) schedule.Controller {
+	c, ok := controller.(ScheduleController)
+	if !ok {
+		return nil
+	}
+
+	return &scheduleController{controller: c}
+}
+
+// scheduleController adapts ScheduleController to the schedule.Controller interface.
+type scheduleController struct {
+	controller ScheduleController
+}
+
+// SetSchedule sets a new weekly program of the device.
+func (c *scheduleController) SetSchedule(program *schedule.Program) error {
+	return c.controller.SetThermostatSchedule(program)
+}
+
+// ScheduleReport returns the current weekly program of the device.
+func (c *scheduleController) ScheduleReport() (*schedule.Program, error) {
+	return c.controller.ThermostatScheduleReport()
+}
@@ -0,0 +1,94 @@
+package thermostat_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/futurehomeno/cliffhanger/adapter/service/setpoint"
+	"github.com/futurehomeno/cliffhanger/adapter/service/thermostat"
+	mockedthermostat "github.com/futurehomeno/cliffhanger/test/mocks/adapter/service/thermostat"
+)
+
+func TestService_SetSetpoint_Constraints(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name     string
+		policy   setpoint.OutOfRangePolicy
+		rounding setpoint.RoundingMode
+		mode     string
+		value    float64
+		expected float64
+		wantErr  bool
+	}{
+		{name: "value within range", mode: "heat", value: 21.5, expected: 21.5},
+		{name: "rounding to nearest step", mode: "heat", value: 21.3, expected: 21.5},
+		{name: "rounding down", rounding: setpoint.RoundDown, mode: "heat", value: 21.4, expected: 21},
+		{name: "rounding up", rounding: setpoint.RoundUp, mode: "heat", value: 21.1, expected: 21.5},
+		{name: "mode specific step", mode: "eco", value: 16.4, expected: 16},
+		{name: "value out of range is rejected", mode: "heat", value: 35, wantErr: true},
+		{name: "value out of range is clamped", policy: setpoint.OutOfRangeClamp, mode: "heat", value: 35, expected: 30},
+		{name: "mode specific range", policy: setpoint.OutOfRangeClamp, mode: "eco", value: 21, expected: 18},
+		{name: "mode without constraints", mode: "cool", value: 24.3, expected: 24.3},
+		{name: "mode is matched case insensitively", mode: "ECO", value: 21.4, expected: 18, policy: setpoint.OutOfRangeClamp},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			controller := mockedthermostat.NewController(t)
+			if !tc.wantErr {
+				controller.On("SetThermostatSetpoint", strings.ToLower(tc.mode), tc.expected, "C").Return(nil).Once()
+			}
+
+			spec := thermostat.Specification("test", "1", "1", nil, []string{"heat"}, []string{"heat", "eco", "cool"}, nil)
+			s := thermostat.NewService(nil, &thermostat.Config{
+				Specification: spec,
+				Controller:    controller,
+				SetpointConstraints: map[string]setpoint.Constraint{
+					"heat": {Min: 5, Max: 30, Step: 0.5},
+					"eco":  {Min: 5, Max: 18, Step: 1},
+				},
+				OutOfRangePolicy: tc.policy,
+				RoundingMode:     tc.rounding,
+			})
+
+			err := s.SetSetpoint(tc.mode, tc.value, "C")
+			if tc.wantErr {
+				assert.True(t, errors.Is(err, setpoint.ErrOutOfRange))
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSpecification_SetpointConstraints(t *testing.T) {
+	t.Parallel()
+
+	spec := thermostat.Specification("test", "1", "1", nil, []string{"heat"}, []string{"heat"}, nil)
+	_ = thermostat.NewService(nil, &thermostat.Config{
+		Specification: spec,
+		Controller:    mockedthermostat.NewController(t),
+		SetpointConstraints: map[string]setpoint.Constraint{
+			"heat": {Min: 5, Max: 30, Step: 0.5},
+		},
+	})
+
+	var ranges map[string]thermostat.Range
+
+	var steps map[string]float64
+
+	assert.True(t, spec.PropertyObject(thermostat.PropertySupportedRanges, &ranges))
+	assert.True(t, spec.PropertyObject(thermostat.PropertySupportedSteps, &steps))
+	assert.Equal(t, map[string]thermostat.Range{"heat": {Min: 5, Max: 30}}, ranges)
+	assert.Equal(t, map[string]float64{"heat": 0.5}, steps)
+}
@@ -0,0 +1,478 @@
+package thermostat
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/futurehomeno/cliffhanger/database"
+	"github.com/futurehomeno/cliffhanger/task"
+	"github.com/futurehomeno/cliffhanger/utils"
+)
+
+const (
+	// AlgorithmHysteresis switches the actuator fully on or off around the setpoint.
+	AlgorithmHysteresis Algorithm = "hysteresis"
+	// AlgorithmPID drives the level actuator with the output of a PID regulator.
+	AlgorithmPID Algorithm = "pid"
+
+	// defaultHysteresis is a default width of the band around the setpoint in which the hysteresis algorithm keeps the output.
+	defaultHysteresis = 0.5
+	// defaultSensorTimeout is a default period without a successful temperature reading after which heating is turned off.
+	defaultSensorTimeout = 10 * time.Minute
+	// defaultSetpoint is a default heat setpoint.
+	defaultSetpoint = 21.0
+	// softwareControllerBucket is a database bucket in which modes and setpoints of software controllers are persisted.
+	softwareControllerBucket = "software_thermostat"
+)
+
+// Algorithm represents a control algorithm of the software controller.
+type Algorithm string
+
+// TemperatureSource is an interface representing a source of the measured temperature, e.g. a separate numeric sensor.
+type TemperatureSource interface {
+	// Temperature returns the current temperature in the unit of the controller.
+	Temperature() (float64, error)
+}
+
+// BinaryActuator is an interface representing an on/off heating actuator, e.g. a relay.
+type BinaryActuator interface {
+	// SetHeating turns the heating on or off.
+	SetHeating(on bool) error
+}
+
+// LevelActuator is an interface representing a heating actuator accepting a level, e.g. a dimmer or a valve.
+type LevelActuator interface {
+	// SetHeatingLevel sets the heating level in percents, from 0 to 100.
+	SetHeatingLevel(level float64) error
+}
+
+// SoftwareControllerConfig represents a configuration of the software controller.
+type SoftwareControllerConfig struct {
+	// Sensor is a source of the measured temperature.
+	Sensor TemperatureSource
+	// BinaryActuator is an on/off actuator. Exactly one of the binary and level actuators must be provided.
+	BinaryActuator BinaryActuator
+	// LevelActuator is a level actuator. Exactly one of the binary and level actuators must be provided.
+	LevelActuator LevelActuator
+	// Algorithm is the control algorithm. Defaults to hysteresis. PID requires the level actuator.
+	Algorithm Algorithm
+	// Unit is the unit of the temperature and setpoints. Defaults to Celsius.
+	Unit string
+	// Mode is the initial mode. Defaults to off.
+	Mode string
+	// Setpoint is the initial heat setpoint. Defaults to 21 degrees.
+	Setpoint *float64
+	// Hysteresis is a width of the band around the setpoint, half below and half above it, used by the hysteresis algorithm.
+	Hysteresis float64
+	// Kp, Ki and Kd are the proportional, integral and derivative gains of the PID algorithm.
+	Kp, Ki, Kd float64
+	// MinOnTime is a minimum time the actuator is kept on before it may be turned off.
+	MinOnTime time.Duration
+	// MinOffTime is a minimum time the actuator is kept off before it may be turned on.
+	MinOffTime time.Duration
+	// FrostProtection is an optional temperature maintained while the thermostat is off.
+	FrostProtection *float64
+	// SensorTimeout is a period without a successful temperature reading after which heating is turned off regardless of the minimum on time.
+	SensorTimeout time.Duration
+	// Database is an optional database used to persist the mode and the setpoint across restarts, taking precedence over the initial ones.
+	Database database.Database
+	// Key is a key under which the mode and the setpoint are persisted. It must be unique for every controller sharing the database.
+	Key string
+}
+
+// softwareControllerState represents the persisted mode and setpoint of the software controller.
+type softwareControllerState struct {
+	Mode     string  `json:"mode"`
+	Setpoint float64 `json:"setpoint"`
+}
+
+// withDefaults sets default values for all options which were not provided.
+func (c *SoftwareControllerConfig) withDefaults() *SoftwareControllerConfig {
+	if c.Algorithm == "" {
+		c.Algorithm = AlgorithmHysteresis
+	}
+
+	if c.Unit == "" {
+		c.Unit = UnitC
+	}
+
+	if c.Mode == "" {
+		c.Mode = ModeOff
+	}
+
+	if c.Setpoint == nil {
+		c.Setpoint = utils.Ptr(defaultSetpoint)
+	}
+
+	if c.Hysteresis <= 0 {
+		c.Hysteresis = defaultHysteresis
+	}
+
+	if c.SensorTimeout <= 0 {
+		c.SensorTimeout = defaultSensorTimeout
+	}
+
+	return c
+}
+
+// validate checks if the configuration is consistent.
+func (c *SoftwareControllerConfig) validate() error {
+	if c.Sensor == nil {
+		return errors.New("software thermostat: temperature sensor is missing")
+	}
+
+	if (c.BinaryActuator == nil) == (c.LevelActuator == nil) {
+		return errors.New("software thermostat: exactly one of binary and level actuators must be provided")
+	}
+
+	switch c.Algorithm {
+	case AlgorithmHysteresis:
+	case AlgorithmPID:
+		if c.LevelActuator == nil {
+			return errors.New("software thermostat: PID algorithm requires a level actuator")
+		}
+	default:
+		return fmt.Errorf("software thermostat: unsupported algorithm: %s", c.Algorithm)
+	}
+
+	if c.Mode != ModeOff && c.Mode != ModeHeat {
+		return fmt.Errorf("software thermostat: unsupported mode: %s", c.Mode)
+	}
+
+	return nil
+}
+
+// SoftwareController is a thermostat controller implemented in software on top of a separate temperature sensor and heating actuator.
+// It must be executed periodically, e.g. using TaskSoftwareController.
+type SoftwareController interface {
+	Controller
+
+	// Control reads the temperature and drives the actuator according to the mode, the setpoint and the safety rules.
+	Control() error
+	// SupportedModes returns modes supported by the controller.
+	SupportedModes() []string
+	// SupportedSetpoints returns setpoints supported by the controller.
+	SupportedSetpoints() []string
+	// SupportedStates returns states reported by the controller.
+	SupportedStates() []string
+}
+
+// NewSoftwareController creates new instance of a software thermostat controller.
+func NewSoftwareController(cfg *SoftwareControllerConfig) (SoftwareController, error) {
+	cfg = cfg.withDefaults()
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	c := &softwareController{
+		cfg:      cfg,
+		now:      time.Now,
+		mode:     cfg.Mode,
+		setpoint: *cfg.Setpoint,
+	}
+
+	if cfg.Database == nil {
+		return c, nil
+	}
+
+	state := &softwareControllerState{Mode: c.mode, Setpoint: c.setpoint}
+
+	_, err := cfg.Database.Get(softwareControllerBucket, cfg.Key, state)
+	if err != nil {
+		return nil, fmt.Errorf("software thermostat: failed to load state %s: %w", cfg.Key, err)
+	}
+
+	if state.Mode != ModeOff && state.Mode != ModeHeat {
+		return nil, fmt.Errorf("software thermostat: unsupported persisted mode: %s", state.Mode)
+	}
+
+	c.mode, c.setpoint = state.Mode, state.Setpoint
+
+	return c, nil
+}
+
+type softwareController struct {
+	cfg *SoftwareControllerConfig
+	now func() time.Time
+
+	lock         sync.Mutex
+	mode         string
+	setpoint     float64
+	level        float64
+	applied      bool
+	switchedAt   time.Time
+	lastReading  time.Time
+	lastError    float64
+	lastControl  time.Time
+	integral     float64
+	frostHeating bool
+}
+
+// SetThermostatMode sets a new thermostat mode.
+func (c *softwareController) SetThermostatMode(mode string) error {
+	if mode != ModeOff && mode != ModeHeat {
+		return fmt.Errorf("software thermostat: unsupported mode: %s", mode)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.persist(mode, c.setpoint); err != nil {
+		return err
+	}
+
+	c.mode = mode
+	c.integral = 0
+
+	return nil
+}
+
+// SetThermostatSetpoint sets a setpoint for a particular mode.
+func (c *softwareController) SetThermostatSetpoint(mode string, value float64, unit string) error {
+	if mode != ModeHeat {
+		return fmt.Errorf("software thermostat: unsupported setpoint: %s", mode)
+	}
+
+	if unit != "" && unit != c.cfg.Unit {
+		return fmt.Errorf("software thermostat: unsupported unit: %s", unit)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err := c.persist(c.mode, value); err != nil {
+		return err
+	}
+
+	c.setpoint = value
+
+	return nil
+}
+
+// persist stores the mode and the setpoint in the database, if one is configured.
+func (c *softwareController) persist(mode string, setpoint float64) error {
+	if c.cfg.Database == nil {
+		return nil
+	}
+
+	err := c.cfg.Database.Set(softwareControllerBucket, c.cfg.Key, &softwareControllerState{Mode: mode, Setpoint: setpoint})
+	if err != nil {
+		return fmt.Errorf("software thermostat: failed to persist state %s: %w", c.cfg.Key, err)
+	}
+
+	return nil
+}
+
+// ThermostatModeReport returns a current mode information.
+func (c *softwareController) ThermostatModeReport() (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.mode, nil
+}
+
+// ThermostatSetpointReport returns a current setpoint for given mode.
+func (c *softwareController) ThermostatSetpointReport(mode string) (float64, string, error) {
+	if mode != ModeHeat {
+		return 0, "", fmt.Errorf("software thermostat: unsupported setpoint: %s", mode)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.setpoint, c.cfg.Unit, nil
+}
+
+// ThermostatStateReport returns a current state of the thermostat.
+func (c *softwareController) ThermostatStateReport() (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.level > 0 {
+		return StateHeat, nil
+	}
+
+	return StateIdle, nil
+}
+
+// SupportedModes returns modes supported by the controller.
+func (c *softwareController) SupportedModes() []string {
+	return []string{ModeOff, ModeHeat}
+}
+
+// SupportedSetpoints returns setpoints supported by the controller.
+func (c *softwareController) SupportedSetpoints() []string {
+	return []string{ModeHeat}
+}
+
+// SupportedStates returns states reported by the controller.
+func (c *softwareController) SupportedStates() []string {
+	return []string{StateIdle, StateHeat}
+}
+
+// Control reads the temperature and drives the actuator according to the mode, the setpoint and the safety rules.
+func (c *softwareController) Control() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+
+	temperature, err := c.cfg.Sensor.Temperature()
+	if err != nil {
+		if c.lastReading.IsZero() || now.Sub(c.lastReading) >= c.cfg.SensorTimeout {
+			log.WithError(err).Warnf("software thermostat: temperature is unavailable, turning heating off for safety")
+
+			return c.apply(0, now, true)
+		}
+
+		return fmt.Errorf("software thermostat: failed to read temperature: %w", err)
+	}
+
+	c.lastReading = now
+
+	target, ok := c.target(temperature)
+	if !ok {
+		c.integral = 0
+		c.lastControl = time.Time{}
+
+		return c.apply(0, now, false)
+	}
+
+	var level float64
+
+	if c.cfg.Algorithm == AlgorithmPID && !c.frostHeating {
+		level = c.pid(target-temperature, now)
+	} else {
+		level = c.hysteresis(target, temperature)
+	}
+
+	return c.apply(level, now, false)
+}
+
+// target returns the temperature to be maintained, or false if heating is not required in the current mode.
+// Frost protection is applied with the hysteresis algorithm while the thermostat is off.
+func (c *softwareController) target(temperature float64) (float64, bool) {
+	if c.mode == ModeHeat {
+		c.frostHeating = false
+
+		return c.setpoint, true
+	}
+
+	if c.cfg.FrostProtection == nil {
+		c.frostHeating = false
+
+		return 0, false
+	}
+
+	frost := *c.cfg.FrostProtection
+
+	switch {
+	case temperature < frost-c.cfg.Hysteresis/2:
+		c.frostHeating = true
+	case temperature > frost+c.cfg.Hysteresis/2:
+		c.frostHeating = false
+	}
+
+	return frost, c.frostHeating
+}
+
+// hysteresis returns the output level of the hysteresis algorithm.
+func (c *softwareController) hysteresis(target, temperature float64) float64 {
+	switch {
+	case temperature < target-c.cfg.Hysteresis/2:
+		return 100
+	case temperature > target+c.cfg.Hysteresis/2:
+		return 0
+	default:
+		return c.level
+	}
+}
+
+// pid returns the output level of the PID algorithm, limiting the integral term to prevent windup.
+func (c *softwareController) pid(e float64, now time.Time) float64 {
+	var derivative float64
+
+	if !c.lastControl.IsZero() {
+		dt := now.Sub(c.lastControl).Seconds()
+		if dt > 0 {
+			c.integral += e * dt
+			derivative = (e - c.lastError) / dt
+		}
+	}
+
+	if c.cfg.Ki > 0 {
+		c.integral = math.Max(0, math.Min(c.integral, 100/c.cfg.Ki))
+	}
+
+	c.lastError = e
+	c.lastControl = now
+
+	return math.Max(0, math.Min(100, c.cfg.Kp*e+c.cfg.Ki*c.integral+c.cfg.Kd*derivative))
+}
+
+// apply drives the actuator with the provided level, respecting minimum on and off times unless the change is forced.
+func (c *softwareController) apply(level float64, now time.Time, force bool) error {
+	on, wasOn := level > 0, c.level > 0
+
+	if c.applied && !force && on != wasOn {
+		minimum := c.cfg.MinOffTime
+		if wasOn {
+			minimum = c.cfg.MinOnTime
+		}
+
+		if now.Sub(c.switchedAt) < minimum {
+			return nil
+		}
+	}
+
+	if c.applied && level == c.level {
+		return nil
+	}
+
+	var err error
+
+	if c.cfg.BinaryActuator != nil {
+		err = c.cfg.BinaryActuator.SetHeating(on)
+	} else {
+		err = c.cfg.LevelActuator.SetHeatingLevel(level)
+	}
+
+	if err != nil {
+		return fmt.Errorf("software thermostat: failed to drive actuator: %w", err)
+	}
+
+	if !c.applied || on != wasOn {
+		c.switchedAt = now
+	}
+
+	c.level = level
+	c.applied = true
+
+	return nil
+}
+
+// TaskSoftwareController creates a task executing the software controller and reporting state changes of the thermostat service.
+func TaskSoftwareController(controller SoftwareController, thermostat Service, frequency time.Duration, voters ...task.Voter) *task.Task {
+	return task.New(handleSoftwareController(controller, thermostat), frequency, voters...)
+}
+
+// handleSoftwareController creates handler of a software controller task.
+func handleSoftwareController(controller SoftwareController, thermostat Service) func() {
+	return func() {
+		err := controller.Control()
+		if err != nil {
+			log.WithError(err).Errorf("failed to execute software thermostat controller")
+		}
+
+		_, err = thermostat.SendStateReport(false)
+		if err != nil {
+			log.WithError(err).Errorf("failed to send thermostat state report")
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package thermostat
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	databasehelper "github.com/futurehomeno/cliffhanger/test/helper/database"
+	"github.com/futurehomeno/cliffhanger/utils"
+)
+
+type fakeTemperatureSource struct {
+	temperature float64
+	err         error
+}
+
+func (f *fakeTemperatureSource) Temperature() (float64, error) {
+	return f.temperature, f.err
+}
+
+type fakeBinaryActuator struct {
+	on    bool
+	calls int
+}
+
+func (f *fakeBinaryActuator) SetHeating(on bool) error {
+	f.on = on
+	f.calls++
+
+	return nil
+}
+
+type fakeLevelActuator struct {
+	level float64
+}
+
+func (f *fakeLevelActuator) SetHeatingLevel(level float64) error {
+	f.level = level
+
+	return nil
+}
+
+func newTestSoftwareController(t *testing.T, cfg *SoftwareControllerConfig, now *time.Time) *softwareController {
+	t.Helper()
+
+	c, err := NewSoftwareController(cfg)
+	assert.NoError(t, err)
+
+	sc := c.(*softwareController) //nolint:forcetypeassert
+	sc.now = func() time.Time { return *now }
+
+	return sc
+}
+
+func TestSoftwareController_Hysteresis(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sensor := &fakeTemperatureSource{temperature: 19}
+	relay := &fakeBinaryActuator{}
+
+	c := newTestSoftwareController(t, &SoftwareControllerConfig{
+		Sensor:         sensor,
+		BinaryActuator: relay,
+		Mode:           ModeHeat,
+		Setpoint:       utils.Ptr(21.0),
+		Hysteresis:     1,
+		MinOnTime:      5 * time.Minute,
+		MinOffTime:     5 * time.Minute,
+	}, &now)
+
+	assert.NoError(t, c.Control())
+	assert.True(t, relay.on)
+
+	state, err := c.ThermostatStateReport()
+	assert.NoError(t, err)
+	assert.Equal(t, StateHeat, state)
+
+	now = now.Add(time.Minute)
+	sensor.temperature = 21.2
+
+	assert.NoError(t, c.Control())
+	assert.True(t, relay.on, "output is kept within the band")
+
+	sensor.temperature = 22
+
+	assert.NoError(t, c.Control())
+	assert.True(t, relay.on, "minimum on time is respected")
+
+	now = now.Add(5 * time.Minute)
+
+	assert.NoError(t, c.Control())
+	assert.False(t, relay.on)
+
+	state, err = c.ThermostatStateReport()
+	assert.NoError(t, err)
+	assert.Equal(t, StateIdle, state)
+
+	assert.NoError(t, c.SetThermostatMode(ModeOff))
+	sensor.temperature = 10
+	now = now.Add(10 * time.Minute)
+
+	assert.NoError(t, c.Control())
+	assert.False(t, relay.on, "thermostat is off without frost protection")
+	assert.Equal(t, 2, relay.calls)
+}
+
+func TestSoftwareController_FrostProtection(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	frost := 7.0
+	sensor := &fakeTemperatureSource{temperature: 6}
+	relay := &fakeBinaryActuator{}
+
+	c := newTestSoftwareController(t, &SoftwareControllerConfig{
+		Sensor:          sensor,
+		BinaryActuator:  relay,
+		FrostProtection: &frost,
+		Hysteresis:      1,
+	}, &now)
+
+	assert.NoError(t, c.Control())
+	assert.True(t, relay.on)
+
+	sensor.temperature = 7.2
+
+	assert.NoError(t, c.Control())
+	assert.True(t, relay.on)
+
+	sensor.temperature = 8
+
+	assert.NoError(t, c.Control())
+	assert.False(t, relay.on)
+}
+
+func TestSoftwareController_SensorTimeout(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sensor := &fakeTemperatureSource{temperature: 18}
+	relay := &fakeBinaryActuator{}
+
+	c := newTestSoftwareController(t, &SoftwareControllerConfig{
+		Sensor:         sensor,
+		BinaryActuator: relay,
+		Mode:           ModeHeat,
+		MinOnTime:      time.Hour,
+		SensorTimeout:  10 * time.Minute,
+	}, &now)
+
+	assert.NoError(t, c.Control())
+	assert.True(t, relay.on)
+
+	sensor.err = errors.New("test")
+	now = now.Add(5 * time.Minute)
+
+	assert.Error(t, c.Control())
+	assert.True(t, relay.on)
+
+	now = now.Add(5 * time.Minute)
+
+	assert.NoError(t, c.Control())
+	assert.False(t, relay.on, "safety overrides minimum on time")
+}
+
+func TestSoftwareController_PID(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sensor := &fakeTemperatureSource{temperature: 20}
+	valve := &fakeLevelActuator{}
+
+	c := newTestSoftwareController(t, &SoftwareControllerConfig{
+		Sensor:        sensor,
+		LevelActuator: valve,
+		Algorithm:     AlgorithmPID,
+		Mode:          ModeHeat,
+		Setpoint:      utils.Ptr(21.0),
+		Kp:            20,
+		Ki:            0.01,
+	}, &now)
+
+	assert.NoError(t, c.Control())
+	assert.InDelta(t, 20, valve.level, 1e-9)
+
+	now = now.Add(100 * time.Second)
+
+	assert.NoError(t, c.Control())
+	assert.InDelta(t, 21, valve.level, 1e-9)
+
+	sensor.temperature = 30
+	now = now.Add(100 * time.Second)
+
+	assert.NoError(t, c.Control())
+	assert.InDelta(t, 0, valve.level, 1e-9)
+}
+
+func TestSoftwareController_Persistence(t *testing.T) {
+	t.Parallel()
+
+	db := databasehelper.NewDatabase(t)
+	cfg := func() *SoftwareControllerConfig {
+		return &SoftwareControllerConfig{
+			Sensor:         &fakeTemperatureSource{},
+			BinaryActuator: &fakeBinaryActuator{},
+			Setpoint:       utils.Ptr(0.0),
+			Database:       db,
+			Key:            "thermostat_1",
+		}
+	}
+
+	c, err := NewSoftwareController(cfg())
+	assert.NoError(t, err)
+
+	setpoint, _, err := c.ThermostatSetpointReport(ModeHeat)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, setpoint, "explicit zero setpoint is kept")
+
+	assert.NoError(t, c.SetThermostatMode(ModeHeat))
+	assert.NoError(t, c.SetThermostatSetpoint(ModeHeat, 23.5, UnitC))
+
+	c, err = NewSoftwareController(cfg())
+	assert.NoError(t, err)
+
+	mode, err := c.ThermostatModeReport()
+	assert.NoError(t, err)
+	assert.Equal(t, ModeHeat, mode)
+
+	setpoint, _, err = c.ThermostatSetpointReport(ModeHeat)
+	assert.NoError(t, err)
+	assert.Equal(t, 23.5, setpoint)
+}
+
+func TestNewSoftwareController_Validation(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewSoftwareController(&SoftwareControllerConfig{BinaryActuator: &fakeBinaryActuator{}})
+	assert.Error(t, err)
+
+	_, err = NewSoftwareController(&SoftwareControllerConfig{Sensor: &fakeTemperatureSource{}})
+	assert.Error(t, err)
+
+	_, err = NewSoftwareController(&SoftwareControllerConfig{
+		Sensor:         &fakeTemperatureSource{},
+		BinaryActuator: &fakeBinaryActuator{},
+		Algorithm:      AlgorithmPID,
+	})
+	assert.Error(t, err)
+}
@@ -8,6 +8,12 @@ import (
 	"github.com/futurehomeno/cliffhanger/router"
 )
 
+// Range represents setpoint acceptable range.
+type Range struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
 // Specification creates a service specification.
 func Specification(
 	resourceName,
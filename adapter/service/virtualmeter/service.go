@@ -37,6 +37,7 @@ type (
 		Specification     *fimptype.Service
 		Manager           Manager
 		ReportingStrategy cache.ReportingStrategy
+		ReportingCache    cache.ReportingCache
 	}
 
 	service struct {
@@ -61,17 +62,26 @@ func NewService(
 
 	mr := cfg.Manager.(*manager) //nolint:forcetypeassert
 
+	if cfg.ReportingCache == nil {
+		cfg.ReportingCache = cache.NewReportingCache()
+	}
+
 	s := &service{
 		Service:           adapter.NewService(publisher, cfg.Specification),
 		manager:           mr,
 		lock:              &sync.RWMutex{},
-		reportingCache:    cache.NewReportingCache(),
+		reportingCache:    cfg.ReportingCache,
 		reportingStrategy: cfg.ReportingStrategy,
 	}
 
 	return s
 }
 
+// OverrideReportingStrategy overrides the reporting strategy of the service. Nil restores the configured strategy.
+func (s *service) OverrideReportingStrategy(strategy cache.ReportingStrategy) {
+	s.reportingCache.OverrideStrategy(strategy)
+}
+
 func (s *service) SendModesReport(force bool) (bool, error) {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
@@ -10,7 +10,9 @@ import (
 
 const (
 	keyDevice = "device"
-	dbDomain  = "virtualManager"
+
+	// DatabaseDomain is a database domain under which virtual meter data is stored.
+	DatabaseDomain = "virtualManager"
 )
 
 type (
@@ -36,7 +38,7 @@ func (d *Device) Initialised() bool {
 
 func NewStorage(db database.Database) *Storage {
 	return &Storage{
-		db:   database.NewDomainDatabase(dbDomain, db),
+		db:   database.NewDomainDatabase(DatabaseDomain, db),
 		lock: sync.RWMutex{},
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/futurehomeno/fimpgo"
 
 	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/adapter/schedule"
 	"github.com/futurehomeno/cliffhanger/router"
 )
 
@@ -18,6 +19,9 @@ const (
 	EvtSetpointReport    = "evt.setpoint.report"
 	CmdStateGetReport    = "cmd.state.get_report"
 	EvtStateReport       = "evt.state.report"
+	CmdScheduleSet       = schedule.CmdScheduleSet
+	CmdScheduleGetReport = schedule.CmdScheduleGetReport
+	EvtScheduleReport    = schedule.EvtScheduleReport
 
 	WaterHeater = "water_heater"
 )
@@ -29,6 +33,8 @@ func RouteService(adapter adapter.ServiceRegistry) []*router.Routing {
 		RouteCmdModeGetReport(adapter),
 		RouteCmdSetpointGetReport(adapter),
 		RouteCmdStateGetReport(adapter),
+		RouteCmdScheduleSet(adapter),
+		RouteCmdScheduleGetReport(adapter),
 	}
 }
 
@@ -220,3 +226,19 @@ func HandleCmdStateGetReport(adapter adapter.ServiceRegistry) router.MessageHand
 		}),
 	)
 }
+
+func RouteCmdScheduleSet(adapter adapter.ServiceRegistry) *router.Routing {
+	return schedule.RouteCmdScheduleSet(adapter, WaterHeater)
+}
+
+func HandleCmdScheduleSet(adapter adapter.ServiceRegistry) router.MessageHandler {
+	return schedule.HandleCmdScheduleSet(adapter)
+}
+
+func RouteCmdScheduleGetReport(adapter adapter.ServiceRegistry) *router.Routing {
+	return schedule.RouteCmdScheduleGetReport(adapter, WaterHeater)
+}
+
+func HandleCmdScheduleGetReport(adapter adapter.ServiceRegistry) router.MessageHandler {
+	return schedule.HandleCmdScheduleGetReport(adapter)
+}
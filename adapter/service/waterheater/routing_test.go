@@ -0,0 +1,200 @@
+package waterheater_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/futurehomeno/fimpgo"
+	"github.com/futurehomeno/fimpgo/fimptype"
+	"github.com/stretchr/testify/require"
+
+	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/adapter/schedule"
+	"github.com/futurehomeno/cliffhanger/adapter/service/waterheater"
+	"github.com/futurehomeno/cliffhanger/router"
+	"github.com/futurehomeno/cliffhanger/task"
+	adapterhelper "github.com/futurehomeno/cliffhanger/test/helper/adapter"
+	databasehelper "github.com/futurehomeno/cliffhanger/test/helper/database"
+	mockedadapter "github.com/futurehomeno/cliffhanger/test/mocks/adapter"
+	mockedwaterheater "github.com/futurehomeno/cliffhanger/test/mocks/adapter/service/waterheater"
+	cliffSuite "github.com/futurehomeno/cliffhanger/test/suite"
+)
+
+const (
+	evtTopic = "pt:j1/mt:evt/rt:dev/rn:test_adapter/ad:1/sv:water_heater/ad:2"
+	cmdTopic = "pt:j1/mt:cmd/rt:dev/rn:test_adapter/ad:1/sv:water_heater/ad:2"
+)
+
+func TestRouteService(t *testing.T) { //nolint:paralleltest
+	program := &schedule.Program{
+		Enabled: true,
+		Transitions: []*schedule.Transition{
+			{Day: schedule.Monday, Time: "06:00", Mode: "normal", Setpoint: &schedule.Setpoint{Type: "normal", Temperature: 60, Unit: waterheater.UnitC}},
+			{Day: schedule.Monday, Time: "22:00", Mode: "eco"},
+		},
+	}
+
+	s := &cliffSuite.Suite{
+		Cases: []*cliffSuite.Case{
+			{
+				Name:     "mode set with setpoint",
+				TearDown: adapterhelper.TearDownAdapter("../../testdata/adapter/test_adapter"),
+				Setup: routeService(mockedwaterheater.NewController(t).
+					MockSetWaterHeaterMode("normal", nil, true).
+					MockWaterHeaterModeReport("normal", nil, true).
+					MockWaterHeaterSetpointReport("normal", 60.0, waterheater.UnitC, nil, true),
+				),
+				Nodes: []*cliffSuite.Node{
+					{
+						Name: "cmd.mode.set normal emits mode and setpoint reports",
+						Command: cliffSuite.NewMessageBuilder().
+							StringMessage(cmdTopic, waterheater.CmdModeSet, waterheater.WaterHeater, "normal").
+							Build(),
+						Expectations: []*cliffSuite.Expectation{
+							cliffSuite.ExpectString(evtTopic, waterheater.EvtModeReport, waterheater.WaterHeater, "normal"),
+							cliffSuite.ExpectObject(evtTopic, waterheater.EvtSetpointReport, waterheater.WaterHeater,
+								waterheater.NewSetpoint("normal", 60.0, waterheater.UnitC)),
+						},
+					},
+				},
+			},
+			{
+				Name:     "setpoint set controller error",
+				TearDown: adapterhelper.TearDownAdapter("../../testdata/adapter/test_adapter"),
+				Setup: routeService(mockedwaterheater.NewController(t).
+					MockSetWaterHeaterSetpoint("normal", 55.0, waterheater.UnitC, errors.New("controller error"), true),
+				),
+				Nodes: []*cliffSuite.Node{
+					{
+						Name: "cmd.setpoint.set returns error",
+						Command: cliffSuite.NewMessageBuilder().
+							ObjectMessage(cmdTopic, waterheater.CmdSetpointSet, waterheater.WaterHeater,
+								waterheater.NewSetpoint("normal", 55.0, waterheater.UnitC)).
+							Build(),
+						Expectations: []*cliffSuite.Expectation{
+							cliffSuite.ExpectError(evtTopic, waterheater.WaterHeater),
+						},
+					},
+				},
+			},
+			{
+				Name:     "software schedule",
+				TearDown: adapterhelper.TearDownAdapter("../../testdata/adapter/test_adapter"),
+				Setup:    routeScheduledService(mockedwaterheater.NewController(t)),
+				Nodes: []*cliffSuite.Node{
+					{
+						Name: "cmd.schedule.set emits schedule report",
+						Command: cliffSuite.NewMessageBuilder().
+							ObjectMessage(cmdTopic, waterheater.CmdScheduleSet, waterheater.WaterHeater, program).
+							Build(),
+						Expectations: []*cliffSuite.Expectation{
+							cliffSuite.ExpectObject(evtTopic, waterheater.EvtScheduleReport, waterheater.WaterHeater, program),
+						},
+					},
+					{
+						Name: "cmd.schedule.get_report returns schedule",
+						Command: cliffSuite.NewMessageBuilder().
+							NullMessage(cmdTopic, waterheater.CmdScheduleGetReport, waterheater.WaterHeater).
+							Build(),
+						Expectations: []*cliffSuite.Expectation{
+							cliffSuite.ExpectObject(evtTopic, waterheater.EvtScheduleReport, waterheater.WaterHeater, program),
+						},
+					},
+					{
+						Name: "cmd.schedule.set with unsupported mode returns error",
+						Command: cliffSuite.NewMessageBuilder().
+							ObjectMessage(cmdTopic, waterheater.CmdScheduleSet, waterheater.WaterHeater, &schedule.Program{
+								Enabled:     true,
+								Transitions: []*schedule.Transition{{Day: schedule.Monday, Time: "06:00", Mode: "boost"}},
+							}).
+							Build(),
+						Expectations: []*cliffSuite.Expectation{
+							cliffSuite.ExpectError(evtTopic, waterheater.WaterHeater),
+						},
+					},
+				},
+			},
+			{
+				Name:     "schedule not supported",
+				TearDown: adapterhelper.TearDownAdapter("../../testdata/adapter/test_adapter"),
+				Setup:    routeService(mockedwaterheater.NewController(t)),
+				Nodes: []*cliffSuite.Node{
+					{
+						Name: "cmd.schedule.get_report returns error",
+						Command: cliffSuite.NewMessageBuilder().
+							NullMessage(cmdTopic, waterheater.CmdScheduleGetReport, waterheater.WaterHeater).
+							Build(),
+						Expectations: []*cliffSuite.Expectation{
+							cliffSuite.ExpectError(evtTopic, waterheater.WaterHeater),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	s.Run(t)
+}
+
+func routeService(controller *mockedwaterheater.Controller) cliffSuite.BaseSetup {
+	return func(t *testing.T, mqtt *fimpgo.MqttTransport) ([]*router.Routing, []*task.Task, []cliffSuite.Mock) {
+		t.Helper()
+
+		return setupService(t, mqtt, controller, nil)
+	}
+}
+
+func routeScheduledService(controller *mockedwaterheater.Controller) cliffSuite.BaseSetup {
+	return func(t *testing.T, mqtt *fimpgo.MqttTransport) ([]*router.Routing, []*task.Task, []cliffSuite.Mock) {
+		t.Helper()
+
+		scheduler, err := schedule.NewScheduler(&schedule.SchedulerConfig{
+			Database: databasehelper.NewDatabase(t),
+			Key:      "water_heater_2",
+		})
+		require.NoError(t, err)
+
+		return setupService(t, mqtt, controller, scheduler)
+	}
+}
+
+func setupService(
+	t *testing.T,
+	mqtt *fimpgo.MqttTransport,
+	controller *mockedwaterheater.Controller,
+	scheduler schedule.Scheduler,
+) ([]*router.Routing, []*task.Task, []cliffSuite.Mock) {
+	t.Helper()
+
+	thingCfg := &adapter.ThingConfig{
+		InclusionReport: &fimptype.ThingInclusionReport{Address: "2"},
+		Connector:       mockedadapter.NewDefaultConnector(t),
+	}
+
+	svcCfg := &waterheater.Config{
+		Specification: waterheater.Specification(
+			"test_adapter",
+			"1",
+			"2",
+			nil,
+			[]string{"normal", "eco", waterheater.ModeOff},
+			[]string{"normal", "eco"},
+			[]string{waterheater.StateHeat, waterheater.StateIdle},
+			nil,
+			nil,
+			0,
+		),
+		Controller: controller,
+		Scheduler:  scheduler,
+	}
+
+	seed := &adapter.ThingSeed{ID: "B", CustomAddress: "2"}
+
+	factory := adapterhelper.FactoryHelper(func(a adapter.Adapter, p adapter.Publisher, ts adapter.ThingState) (adapter.Thing, error) {
+		return adapter.NewThing(p, ts, thingCfg, waterheater.NewService(p, svcCfg)), nil
+	})
+
+	ad := adapterhelper.PrepareSeededAdapter(t, "../../testdata/adapter/test_adapter", mqtt, factory, adapter.ThingSeeds{seed})
+
+	return waterheater.RouteService(ad), nil, nil
+}
@@ -2,7 +2,6 @@ package waterheater
 
 import (
 	"fmt"
-	"math"
 	"strings"
 	"sync"
 
@@ -11,6 +10,8 @@ import (
 
 	"github.com/futurehomeno/cliffhanger/adapter"
 	"github.com/futurehomeno/cliffhanger/adapter/cache"
+	"github.com/futurehomeno/cliffhanger/adapter/schedule"
+	"github.com/futurehomeno/cliffhanger/adapter/service/setpoint"
 )
 
 // Constants defining important properties specific for the service.
@@ -29,6 +30,7 @@ const (
 	PropertySupportedRange     = "sup_range"
 	PropertySupportedRanges    = "sup_ranges"
 	PropertySupportedStep      = "sup_step"
+	PropertySupportedSteps     = "sup_steps"
 )
 
 // DefaultReportingStrategy is the default reporting strategy used by the service for periodic reports.
@@ -49,9 +51,18 @@ type Controller interface {
 	WaterHeaterStateReport() (string, error)
 }
 
+// ScheduleController is an optional interface representing an actual device with a native weekly program.
+// Devices lacking native support may be given a software schedule using schedule.Scheduler.
+type ScheduleController interface {
+	// SetWaterHeaterSchedule sets a new weekly program.
+	SetWaterHeaterSchedule(program *schedule.Program) error
+	// WaterHeaterScheduleReport returns the current weekly program.
+	WaterHeaterScheduleReport() (*schedule.Program, error)
+}
+
 // Service is an interface representing a water heater FIMP service.
 type Service interface {
-	adapter.Service
+	schedule.Service
 
 	// SetMode sets the mode of the device.
 	SetMode(mode string) error
@@ -84,6 +95,15 @@ type Config struct {
 	Specification     *fimptype.Service
 	Controller        Controller
 	ReportingStrategy cache.ReportingStrategy
+	ReportingCache    cache.ReportingCache
+	// Scheduler is an optional software scheduler used if the controller does not implement ScheduleController.
+	Scheduler schedule.Scheduler
+	// SetpointConstraints are optional per mode constraints of setpoints, published as supported ranges and steps in the specification.
+	SetpointConstraints map[string]setpoint.Constraint
+	// OutOfRangePolicy defines how setpoints outside the supported range are handled. Defaults to OutOfRangeReject.
+	OutOfRangePolicy setpoint.OutOfRangePolicy
+	// RoundingMode defines how setpoints are aligned with the supported step. Defaults to RoundNearest.
+	RoundingMode setpoint.RoundingMode
 }
 
 // NewService creates new instance of a water heater FIMP service.
@@ -99,23 +119,64 @@ func NewService(
 		cfg.ReportingStrategy = DefaultReportingStrategy
 	}
 
-	return &service{
+	if cfg.ReportingCache == nil {
+		cfg.ReportingCache = cache.NewReportingCache()
+	}
+
+	if cfg.OutOfRangePolicy == "" {
+		cfg.OutOfRangePolicy = setpoint.OutOfRangeReject
+	}
+
+	if cfg.RoundingMode == "" {
+		cfg.RoundingMode = setpoint.RoundNearest
+	}
+
+	setpoint.Apply(cfg.Specification, cfg.SetpointConstraints, PropertySupportedRanges, PropertySupportedSteps)
+
+	s := &service{
 		Service:           adapter.NewService(publisher, cfg.Specification),
 		controller:        cfg.Controller,
 		lock:              &sync.Mutex{},
 		reportingStrategy: cfg.ReportingStrategy,
-		reportingCache:    cache.NewReportingCache(),
+		reportingCache:    cfg.ReportingCache,
+		outOfRangePolicy:  cfg.OutOfRangePolicy,
+		roundingMode:      cfg.RoundingMode,
+	}
+
+	s.Handler = schedule.NewHandler(&schedule.HandlerConfig{
+		Service:           s.Service,
+		Executor:          s,
+		Constraints:       s.scheduleConstraints,
+		Controller:        nativeSchedule(cfg.Controller),
+		Scheduler:         cfg.Scheduler,
+		Lock:              s.lock,
+		ReportingCache:    cfg.ReportingCache,
+		ReportingStrategy: cfg.ReportingStrategy,
+	})
+
+	if s.SupportsSchedule() {
+		cfg.Specification.EnsureInterfaces(schedule.Interfaces()...)
 	}
+
+	return s
 }
 
 // service is a private implementation of a water heater FIMP service.
 type service struct {
 	adapter.Service
+	schedule.Handler
 
 	controller        Controller
 	lock              *sync.Mutex
 	reportingCache    cache.ReportingCache
 	reportingStrategy cache.ReportingStrategy
+	outOfRangePolicy  setpoint.OutOfRangePolicy
+	roundingMode      setpoint.RoundingMode
+}
+
+// OverrideReportingStrategy overrides the reporting strategy of the service. Nil restores the configured strategy.
+func (s *service) OverrideReportingStrategy(strategy cache.ReportingStrategy) {
+	s.reportingCache.OverrideStrategy(strategy)
 }
 
 // SetMode sets mode of the device.
@@ -146,7 +207,7 @@ func (s *service) SetSetpoint(mode string, value float64, unit string) error {
 		return fmt.Errorf("%s: setpoint mode is unsupported: %s", s.Name(), mode)
 	}
 
-	normalizedValue, err := s.normalizeValue(mode, value)
+	normalizedValue, err := s.normalizeValue(normalizedMode, value)
 	if err != nil {
 		return fmt.Errorf("%s: setpoint value is incorrect: %w", s.Name(), err)
 	}
@@ -269,6 +330,14 @@ func (s *service) SendStateReport(force bool) (bool, error) {
 	return true, nil
 }
 
+// scheduleConstraints returns constraints of the weekly program supported by the water heater.
+func (s *service) scheduleConstraints() *schedule.Constraints {
+	return &schedule.Constraints{
+		Modes:     s.SupportedModes(),
+		Setpoints: s.SupportedSetpoints(),
+	}
+}
+
 // SupportedModes returns modes that are supported by the water heater.
 func (s *service) SupportedModes() []string {
 	return s.Service.Specification().PropertyStrings(PropertySupportedModes)
@@ -315,23 +384,30 @@ func (s *service) normalizeSetpoint(mode string) (string, bool) {
 
 // normalizeValue normalizes setpoint value for a specific mode.
 func (s *service) normalizeValue(mode string, value float64) (float64, error) {
-	step, ok := s.Service.Specification().PropertyFloat(PropertySupportedStep)
-	if ok && step > 0 {
-		value = math.Round(value/step) * step
-	}
+	value = setpoint.RoundToStep(value, s.supportedStep(mode), s.roundingMode)
 
 	supportedRange := s.supportedRange(mode)
 	if supportedRange == nil {
 		return value, nil
 	}
 
-	if value < supportedRange.Min || value > supportedRange.Max {
-		return 0, fmt.Errorf("%s: value %.01f is out of range %.01f - %.01f for mode %s",
-			s.Name(), value, supportedRange.Min, supportedRange.Max, mode,
-		)
+	return setpoint.Constrain(mode, value, supportedRange.Min, supportedRange.Max, s.outOfRangePolicy)
+}
+
+// supportedStep returns step supported for a given mode.
+func (s *service) supportedStep(mode string) float64 {
+	var supportedSteps map[string]float64
+
+	_ = s.Service.Specification().PropertyObject(PropertySupportedSteps, &supportedSteps)
+
+	step, ok := supportedSteps[mode]
+	if ok {
+		return step
 	}
 
-	return value, nil
+	step, _ = s.Service.Specification().PropertyFloat(PropertySupportedStep)
+
+	return step
 }
 
 // supportedRange returns range supported for a given mode.
@@ -370,3 +446,28 @@ func NewSetpoint(mode string, temp float64, unit string) *Setpoint {
 		Unit:        unit,
 	}
 }
+
+// nativeSchedule returns the native weekly program of the controller, or nil if the controller does not implement ScheduleController.
+func nativeSchedule(controller Controller) schedule.Controller {
+	c, ok := controller.(ScheduleController)
+	if !ok {
+		return nil
+	}
+
+	return &scheduleController{controller: c}
+}
+
+// scheduleController adapts ScheduleController to the schedule.Controller interface.
+type scheduleController struct {
+	controller ScheduleController
+}
+
+// SetSchedule sets a new weekly program of the device.
+func (c *scheduleController) SetSchedule(program *schedule.Program) error {
+	return c.controller.SetWaterHeaterSchedule(program)
+}
+
+// ScheduleReport returns the current weekly program of the device.
+func (c *scheduleController) ScheduleReport() (*schedule.Program, error) {
+	return c.controller.WaterHeaterScheduleReport()
+}
@@ -0,0 +1,70 @@
+package waterheater_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/futurehomeno/cliffhanger/adapter/service/setpoint"
+	"github.com/futurehomeno/cliffhanger/adapter/service/waterheater"
+	mockedwaterheater "github.com/futurehomeno/cliffhanger/test/mocks/adapter/service/waterheater"
+)
+
+func TestService_SetSetpoint_Constraints(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name         string
+		policy       setpoint.OutOfRangePolicy
+		mode         string
+		value        float64
+		expectedMode string
+		expected     float64
+		wantErr      bool
+	}{
+		{name: "rounding to nearest step", mode: "normal", value: 52.6, expectedMode: "normal", expected: 55},
+		{name: "value out of range is rejected", mode: "normal", value: 80, wantErr: true},
+		{name: "value out of range is clamped", policy: setpoint.OutOfRangeClamp, mode: "normal", value: 80, expectedMode: "normal", expected: 75},
+		{name: "mode specific constraint", mode: "vacation", value: 20, expectedMode: "vacation", expected: 20},
+		{name: "mode is matched case insensitively", policy: setpoint.OutOfRangeClamp, mode: "Vacation", value: 40, expectedMode: "vacation", expected: 30},
+		{name: "constraint from specification", mode: "boost", value: 62, expectedMode: "boost", expected: 62},
+	}
+
+	for _, tc := range tcs {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			controller := mockedwaterheater.NewController(t)
+			if !tc.wantErr {
+				controller.On("SetWaterHeaterSetpoint", tc.expectedMode, tc.expected, "C").Return(nil).Once()
+			}
+
+			spec := waterheater.Specification(
+				"test", "1", "1", nil,
+				[]string{"normal"}, []string{"normal", "vacation", "boost"}, nil,
+				nil, map[string]waterheater.Range{"boost": {Min: 60, Max: 85}}, 0,
+			)
+			s := waterheater.NewService(nil, &waterheater.Config{
+				Specification: spec,
+				Controller:    controller,
+				SetpointConstraints: map[string]setpoint.Constraint{
+					"normal":   {Min: 40, Max: 75, Step: 5},
+					"vacation": {Min: 10, Max: 30},
+				},
+				OutOfRangePolicy: tc.policy,
+			})
+
+			err := s.SetSetpoint(tc.mode, tc.value, "C")
+			if tc.wantErr {
+				assert.True(t, errors.Is(err, setpoint.ErrOutOfRange))
+
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
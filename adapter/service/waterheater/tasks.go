@@ -6,6 +6,7 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/adapter/schedule"
 	"github.com/futurehomeno/cliffhanger/task"
 )
 
@@ -49,6 +50,18 @@ func handleReporting(serviceRegistry adapter.ServiceRegistry) func() {
 					log.WithError(err).Errorf("failed to send water heater state report")
 				}
 			}
+
+			if waterHeater.SupportsSchedule() {
+				_, err := waterHeater.SendScheduleReport(false)
+				if err != nil {
+					log.WithError(err).Errorf("failed to send water heater schedule report")
+				}
+			}
 		}
 	}
 }
+
+// TaskSchedule creates a task executing software schedules of water heaters lacking a native weekly program.
+func TaskSchedule(serviceRegistry adapter.ServiceRegistry, frequency time.Duration, voters ...task.Voter) *task.Task {
+	return schedule.TaskSchedule(serviceRegistry, WaterHeater, frequency, voters...)
+}
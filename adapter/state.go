@@ -3,30 +3,50 @@ package adapter
 import (
 	"encoding/json"
 	"fmt"
-	"strconv"
+	"maps"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/futurehomeno/cliffhanger/adapter/cache"
 	"github.com/futurehomeno/cliffhanger/storage"
 )
 
+// maxReleasedAddresses is a maximum number of released addresses remembered by the adapter state.
+const maxReleasedAddresses = 256
+
 type adapterStateModel struct {
-	AddressIndex int                         `json:"address_index"`
-	Things       map[string]*thingStateModel `json:"things"`
+	AddressIndex int                              `json:"address_index"`
+	Things       map[string]*thingStateModel      `json:"things"`
+	Released     map[string]*releasedAddressModel `json:"released,omitempty"`
+}
+
+// releasedAddressModel is a model of an address previously assigned to a removed thing.
+type releasedAddressModel struct {
+	Address    string    `json:"address"`
+	ReleasedAt time.Time `json:"released_at"`
 }
 
 // thingStateModel is a model of a thing state record within the adapter state file.
 type thingStateModel struct {
-	ID                string          `json:"id"`
-	Address           string          `json:"address"`
-	Info              json.RawMessage `json:"info,omitempty"`
-	State             json.RawMessage `json:"state,omitempty"`
-	InclusionChecksum uint32          `json:"inclusion_checksum"`
+	ID                string            `json:"id"`
+	Address           string            `json:"address"`
+	Info              json.RawMessage   `json:"info,omitempty"`
+	State             json.RawMessage   `json:"state,omitempty"`
+	InclusionChecksum uint32            `json:"inclusion_checksum"`
+	Alias             string            `json:"alias,omitempty"`
+	Metadata          map[string]string `json:"metadata,omitempty"`
+
+	ReportingStrategies map[string]*cache.ReportingStrategySpec `json:"reporting_strategies,omitempty"`
 }
 
 // State is an interface representing a persistent state of the adapter and its things.
 type State interface {
-	// acquireAddress increments address index and returns its current value.
-	acquireAddress() (string, error)
+	// acquireAddress returns a new address for a thing with a given ID using the configured address allocator.
+	acquireAddress(id string) (string, error)
+	// releasedAddress returns an address previously assigned to a removed thing with a given ID.
+	releasedAddress(id string) (string, bool)
 	// all returns all persisted thing states.
 	all() []ThingState
 	// add persists a new thing state.
@@ -37,37 +57,104 @@ type State interface {
 	byID(id string) ThingState
 	// byAddress returns a thing state for a thing with a given address.
 	byAddress(address string) ThingState
+	// snapshot returns the current address index and copies of all persisted thing state models.
+	snapshot() (addressIndex int, models []*thingStateModel)
+	// prepare returns a thing state for a model which is not yet persisted.
+	prepare(model *thingStateModel) ThingState
+	// merge persists multiple new thing states and the address index at once. Nothing is changed if persisting fails.
+	merge(addressIndex int, models []*thingStateModel) error
+}
+
+// stateConfig is a configuration of the adapter state.
+type stateConfig struct {
+	filename  string
+	allocator AddressAllocator
+}
+
+// StateOption is an option of the adapter state.
+type StateOption func(cfg *stateConfig)
+
+// WithAddressAllocator sets a strategy of assigning addresses to new things. By default, addresses are assigned sequentially.
+func WithAddressAllocator(allocator AddressAllocator) StateOption {
+	return func(cfg *stateConfig) {
+		cfg.allocator = allocator
+	}
+}
+
+// WithStateFilename sets a name of the file in which the state is persisted. By default, it is adapter.json.
+// Multiple adapter instances sharing the same working directory must use distinct files, see StateFilename.
+func WithStateFilename(filename string) StateOption {
+	return func(cfg *stateConfig) {
+		cfg.filename = filename
+	}
+}
+
+// StateFilename returns a name of the state file for an adapter instance with a given address.
+// Instance with address "1" uses the default filename to stay compatible with single instance applications.
+func StateFilename(address string) string {
+	if address == "1" {
+		return "adapter.json"
+	}
+
+	return fmt.Sprintf("adapter_%s.json", address)
 }
 
-func NewState(workDir string) (State, error) {
-	storageService := storage.NewState(&adapterStateModel{}, workDir, "adapter.json")
+func NewState(workDir string, options ...StateOption) (State, error) {
+	cfg := &stateConfig{
+		filename:  "adapter.json",
+		allocator: NewSequentialAddressAllocator(),
+	}
+
+	for _, o := range options {
+		o(cfg)
+	}
+
+	storageService := storage.NewState(&adapterStateModel{}, workDir, cfg.filename)
 
 	if err := storageService.Load(); err != nil {
 		return nil, fmt.Errorf("state: failed to load the initial adapter state: %w", err)
 	}
 
 	return &state{
-		Storage: storageService,
+		Storage:   storageService,
+		allocator: cfg.allocator,
 	}, nil
 }
 
 type state struct {
 	storage.Storage[*adapterStateModel]
-	lock sync.RWMutex
+	lock      sync.RWMutex
+	allocator AddressAllocator
 }
 
-// acquireAddress increments address index and returns its current value.
-func (s *state) acquireAddress() (string, error) {
+// acquireAddress returns a new address for a thing with a given ID using the configured address allocator.
+func (s *state) acquireAddress(id string) (string, error) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	s.Model().AddressIndex++
+	address, err := s.allocator.Allocate(id, newAddressRegistry(s.Model()))
+	if err != nil {
+		return "", fmt.Errorf("state: failed to allocate address for a thing with ID %s: %w", id, err)
+	}
 
 	if err := s.Save(); err != nil {
 		return "", fmt.Errorf("state: failed to persist address index: %w", err)
 	}
 
-	return strconv.Itoa(s.Model().AddressIndex), nil
+	return address, nil
+}
+
+// releasedAddress returns an address previously assigned to a removed thing with a given ID.
+func (s *state) releasedAddress(id string) (string, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	r, ok := s.Model().Released[id]
+	if !ok {
+		return "", false
+	}
+
+	return r.Address, true
 }
 
 func (s *state) all() []ThingState {
@@ -92,6 +179,7 @@ func (s *state) add(model *thingStateModel) (ThingState, error) {
 	}
 
 	s.Model().Things[model.ID] = model
+	delete(s.Model().Released, model.ID)
 
 	if err := s.Save(); err != nil {
 		return nil, fmt.Errorf("state: failed to persist state of a thing with ID %s: %w", model.ID, err)
@@ -104,6 +192,10 @@ func (s *state) remove(id string) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
+	if m, ok := s.Model().Things[id]; ok {
+		s.release(m)
+	}
+
 	delete(s.Model().Things, id)
 
 	if err := s.Save(); err != nil {
@@ -113,6 +205,32 @@ func (s *state) remove(id string) error {
 	return nil
 }
 
+// release remembers the address of a removed thing, forgetting the oldest released address if the limit is exceeded.
+func (s *state) release(m *thingStateModel) {
+	if s.Model().Released == nil {
+		s.Model().Released = make(map[string]*releasedAddressModel)
+	}
+
+	s.Model().Released[m.ID] = &releasedAddressModel{
+		Address:    m.Address,
+		ReleasedAt: time.Now(),
+	}
+
+	if len(s.Model().Released) <= maxReleasedAddresses {
+		return
+	}
+
+	var oldest string
+
+	for id, r := range s.Model().Released {
+		if oldest == "" || r.ReleasedAt.Before(s.Model().Released[oldest].ReleasedAt) {
+			oldest = id
+		}
+	}
+
+	delete(s.Model().Released, oldest)
+}
+
 func (s *state) byID(id string) ThingState {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
@@ -154,6 +272,20 @@ type ThingState interface {
 	InclusionChecksum() uint32
 	// SetInclusionChecksum persists the checksum of the inclusion report in the thing state.
 	SetInclusionChecksum(checksum uint32) error
+	// Alias returns the user defined alias of the thing. Returns an empty string if alias was not set.
+	Alias() string
+	// SetAlias persists the user defined alias of the thing. Empty alias resets it to the product name.
+	SetAlias(alias string) error
+	// Metadata returns a copy of custom metadata attached to the thing, e.g. location hints, notes or installer tags.
+	Metadata() map[string]string
+	// SetMetadata persists custom metadata attached to the thing, replacing the previous one.
+	SetMetadata(metadata map[string]string) error
+	// ReportingStrategies returns reporting strategies overridden for services of the thing, indexed by service topics.
+	// Keys of overrides limited to a single event are extended with the event type, see ReportingStrategyKey.
+	ReportingStrategies() map[string]*cache.ReportingStrategySpec
+	// SetReportingStrategy persists reporting strategy overridden for a service of the thing, or for a single event of the service
+	// if the specification names one. Default strategy removes the override.
+	SetReportingStrategy(topic string, spec *cache.ReportingStrategySpec) error
 }
 
 // newThingState creates new instance of a thing state proxy service.
@@ -254,3 +386,217 @@ func (s *thingState) SetInclusionChecksum(checksum uint32) error {
 
 	return nil
 }
+
+func (s *thingState) Alias() string {
+	s.state.lock.RLock()
+	defer s.state.lock.RUnlock()
+
+	return s.model.Alias
+}
+
+func (s *thingState) SetAlias(alias string) error {
+	s.state.lock.Lock()
+	defer s.state.lock.Unlock()
+
+	previous := s.model.Alias
+	s.model.Alias = alias
+
+	err := s.state.Save()
+	if err != nil {
+		s.model.Alias = previous
+
+		return fmt.Errorf("thing state: failed to persist alias of a thing with ID %s: %w", s.model.ID, err)
+	}
+
+	return nil
+}
+
+func (s *thingState) Metadata() map[string]string {
+	s.state.lock.RLock()
+	defer s.state.lock.RUnlock()
+
+	return maps.Clone(s.model.Metadata)
+}
+
+func (s *thingState) SetMetadata(metadata map[string]string) error {
+	s.state.lock.Lock()
+	defer s.state.lock.Unlock()
+
+	previous := s.model.Metadata
+
+	if len(metadata) == 0 {
+		s.model.Metadata = nil
+	} else {
+		s.model.Metadata = maps.Clone(metadata)
+	}
+
+	err := s.state.Save()
+	if err != nil {
+		s.model.Metadata = previous
+
+		return fmt.Errorf("thing state: failed to persist metadata of a thing with ID %s: %w", s.model.ID, err)
+	}
+
+	return nil
+}
+
+// ReportingStrategyKey returns a key under which the reporting strategy override of a service topic is persisted.
+// Keys of overrides limited to a single event are extended with the event type.
+func ReportingStrategyKey(topic, event string) string {
+	if event == "" {
+		return topic
+	}
+
+	return topic + "#" + event
+}
+
+func (s *thingState) ReportingStrategies() map[string]*cache.ReportingStrategySpec {
+	s.state.lock.RLock()
+	defer s.state.lock.RUnlock()
+
+	return maps.Clone(s.model.ReportingStrategies)
+}
+
+func (s *thingState) SetReportingStrategy(topic string, spec *cache.ReportingStrategySpec) error {
+	s.state.lock.Lock()
+	defer s.state.lock.Unlock()
+
+	previous := maps.Clone(s.model.ReportingStrategies)
+
+	key := topic
+	if spec != nil {
+		key = ReportingStrategyKey(topic, spec.Event)
+	}
+
+	if spec.IsDefault() {
+		delete(s.model.ReportingStrategies, key)
+	} else {
+		if s.model.ReportingStrategies == nil {
+			s.model.ReportingStrategies = make(map[string]*cache.ReportingStrategySpec)
+		}
+
+		specCopy := *spec
+		s.model.ReportingStrategies[key] = &specCopy
+	}
+
+	if len(s.model.ReportingStrategies) == 0 {
+		s.model.ReportingStrategies = nil
+	}
+
+	err := s.state.Save()
+	if err != nil {
+		s.model.ReportingStrategies = previous
+
+		return fmt.Errorf("thing state: failed to persist reporting strategy of a thing with ID %s: %w", s.model.ID, err)
+	}
+
+	return nil
+}
+
+func (s *state) snapshot() (int, []*thingStateModel) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	models := make([]*thingStateModel, 0, len(s.Model().Things))
+
+	for _, m := range s.Model().Things {
+		models = append(models, &thingStateModel{
+			ID:                m.ID,
+			Address:           m.Address,
+			Info:              slices.Clone(m.Info),
+			State:             slices.Clone(m.State),
+			InclusionChecksum: m.InclusionChecksum,
+			Alias:             m.Alias,
+			Metadata:          maps.Clone(m.Metadata),
+
+			ReportingStrategies: maps.Clone(m.ReportingStrategies),
+		})
+	}
+
+	slices.SortFunc(models, func(a, b *thingStateModel) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return s.Model().AddressIndex, models
+}
+
+func (s *state) prepare(model *thingStateModel) ThingState {
+	return newThingState(s, model)
+}
+
+func (s *state) merge(addressIndex int, models []*thingStateModel) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.Model().Things == nil {
+		s.Model().Things = make(map[string]*thingStateModel)
+	}
+
+	previousIndex := s.Model().AddressIndex
+	previousThings := maps.Clone(s.Model().Things)
+	previousReleased := maps.Clone(s.Model().Released)
+
+	s.Model().AddressIndex = addressIndex
+
+	for _, m := range models {
+		s.Model().Things[m.ID] = m
+		delete(s.Model().Released, m.ID)
+	}
+
+	if err := s.Save(); err != nil {
+		s.Model().AddressIndex = previousIndex
+		s.Model().Things = previousThings
+		s.Model().Released = previousReleased
+
+		return fmt.Errorf("state: failed to persist merged thing states: %w", err)
+	}
+
+	return nil
+}
+
+// newAddressRegistry creates a view of the adapter state model for address allocators.
+// It must be used only while holding the state lock.
+func newAddressRegistry(model *adapterStateModel) AddressRegistry {
+	inUse := make(map[string]bool, len(model.Things))
+
+	for _, m := range model.Things {
+		inUse[m.Address] = true
+	}
+
+	return &addressRegistry{
+		model: model,
+		inUse: inUse,
+	}
+}
+
+type addressRegistry struct {
+	model *adapterStateModel
+	inUse map[string]bool
+}
+
+func (r *addressRegistry) Index() int {
+	return r.model.AddressIndex
+}
+
+func (r *addressRegistry) SetIndex(index int) {
+	r.model.AddressIndex = index
+}
+
+func (r *addressRegistry) InUse(address string) bool {
+	return r.inUse[address]
+}
+
+func (r *addressRegistry) Released(id string) (string, time.Time, bool) {
+	m, ok := r.model.Released[id]
+	if !ok {
+		return "", time.Time{}, false
+	}
+
+	return m.Address, m.ReleasedAt, true
+}
+
+func (r *addressRegistry) ForgetReleased(before time.Time) {
+	maps.DeleteFunc(r.model.Released, func(_ string, m *releasedAddressModel) bool {
+		return m.ReleasedAt.Before(before)
+	})
+}
@@ -4,16 +4,21 @@ import (
 	"encoding/json"
 	"fmt"
 	"hash/crc32"
+	"maps"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/futurehomeno/fimpgo"
 	"github.com/futurehomeno/fimpgo/fimptype"
+	log "github.com/sirupsen/logrus"
 
 	"github.com/futurehomeno/cliffhanger/adapter/cache"
 )
 
+// PropSetThingMetadata is a name of the property set of the inclusion report carrying custom metadata attached to the thing.
+const PropSetThingMetadata = "thing_metadata"
+
 type ThingRegistry interface {
 	Things() []Thing
 	ThingByAddress(address string) Thing
@@ -61,6 +66,7 @@ type ThingConfig struct {
 	Connector                     Connector
 	InclusionReport               *fimptype.ThingInclusionReport
 	ConnectivityReportingStrategy cache.ReportingStrategy
+	ReportingCache                cache.ReportingCache
 }
 
 type Thing interface {
@@ -86,6 +92,43 @@ type Thing interface {
 	Disconnect()
 }
 
+// LabeledThing is an optional interface of a thing allowing to manage its user defined alias and custom metadata.
+// Both are persisted in the thing state and applied to inclusion and connectivity reports of the thing.
+type LabeledThing interface {
+	Thing
+
+	// Alias returns the user defined alias of the thing. Returns an empty string if alias was not set.
+	Alias() string
+	// SetAlias persists the user defined alias of the thing. Empty alias resets it to the product name.
+	SetAlias(alias string) error
+	// Metadata returns custom metadata attached to the thing.
+	Metadata() map[string]string
+	// SetMetadata persists custom metadata attached to the thing, replacing the previous one.
+	SetMetadata(metadata map[string]string) error
+}
+
+// ReportingThing is an optional interface of a thing allowing to override reporting strategies of its services at runtime.
+// Overrides are persisted in the thing state and restored when the thing is registered within the adapter.
+type ReportingThing interface {
+	Thing
+
+	// ReportingStrategy returns the reporting strategy overridden for a service under the provided topic, or for a single event of the service
+	// if the event type is not empty. Returns nil if the default one is used.
+	ReportingStrategy(topic, event string) *cache.ReportingStrategySpec
+	// SetReportingStrategy overrides the reporting strategy of a service under the provided topic, or of a single event of the service
+	// if the specification names one. Default strategy removes the override.
+	SetReportingStrategy(topic string, spec *cache.ReportingStrategySpec) error
+
+	// restoreReportingStrategies applies reporting strategies persisted in the thing state to services of the thing.
+	restoreReportingStrategies()
+}
+
+// ThingMetadata is the object expected as value of the set metadata command.
+type ThingMetadata struct {
+	Address  string            `json:"address"`
+	Metadata map[string]string `json:"metadata"`
+}
+
 func NewThing(
 	publisher Publisher,
 	state ThingState,
@@ -96,6 +139,10 @@ func NewThing(
 		cfg.ConnectivityReportingStrategy = cache.ReportAtLeastEvery(time.Hour)
 	}
 
+	if cfg.ReportingCache == nil {
+		cfg.ReportingCache = cache.NewReportingCache()
+	}
+
 	cfg.InclusionReport.Services = nil
 
 	servicesIndex := make(map[string]Service)
@@ -109,7 +156,7 @@ func NewThing(
 		publisher:                     publisher,
 		state:                         state,
 		connector:                     cfg.Connector,
-		reportingCache:                cache.NewReportingCache(),
+		reportingCache:                cfg.ReportingCache,
 		connectivityReportingStrategy: cfg.ConnectivityReportingStrategy,
 		inclusionReport:               cfg.InclusionReport,
 		services:                      servicesIndex,
@@ -169,9 +216,143 @@ func (t *thing) InclusionReport() *fimptype.ThingInclusionReport {
 	return t.inclusionReport
 }
 
+func (t *thing) Alias() string {
+	return t.state.Alias()
+}
+
+func (t *thing) SetAlias(alias string) error {
+	err := t.state.SetAlias(alias)
+	if err != nil {
+		return fmt.Errorf("thing: failed to set alias: %w", err)
+	}
+
+	return nil
+}
+
+func (t *thing) Metadata() map[string]string {
+	return t.state.Metadata()
+}
+
+func (t *thing) SetMetadata(metadata map[string]string) error {
+	err := t.state.SetMetadata(metadata)
+	if err != nil {
+		return fmt.Errorf("thing: failed to set metadata: %w", err)
+	}
+
+	return nil
+}
+
+func (t *thing) ReportingStrategy(topic, event string) *cache.ReportingStrategySpec {
+	s := t.ServiceByTopic(topic)
+	if s == nil {
+		return nil
+	}
+
+	return t.state.ReportingStrategies()[ReportingStrategyKey(s.Topic(), event)]
+}
+
+func (t *thing) SetReportingStrategy(topic string, spec *cache.ReportingStrategySpec) error {
+	s := t.ServiceByTopic(topic)
+	if s == nil {
+		return fmt.Errorf("thing: service not found under the provided topic: %s", topic)
+	}
+
+	strategy, err := spec.Strategy()
+	if err != nil {
+		return fmt.Errorf("thing: invalid reporting strategy: %w", err)
+	}
+
+	event := ""
+	if spec != nil {
+		event = spec.Event
+	}
+
+	// Override is applied before it is persisted, so an event unsupported by the service is rejected.
+	err = overrideReportingStrategy(s, event, strategy)
+	if err != nil {
+		return err
+	}
+
+	err = t.state.SetReportingStrategy(s.Topic(), spec)
+	if err != nil {
+		return fmt.Errorf("thing: failed to set reporting strategy: %w", err)
+	}
+
+	return nil
+}
+
+func (t *thing) restoreReportingStrategies() {
+	for topic, spec := range t.state.ReportingStrategies() {
+		s := t.ServiceByTopic(strings.TrimSuffix(topic, "#"+spec.Event))
+		if s == nil {
+			continue
+		}
+
+		strategy, err := spec.Strategy()
+		if err == nil {
+			err = overrideReportingStrategy(s, spec.Event, strategy)
+		}
+
+		if err != nil {
+			log.WithError(err).WithField("topic", topic).Errorf("thing: failed to restore reporting strategy")
+		}
+	}
+}
+
+// overrideReportingStrategy overrides the reporting strategy of the service, or of a single event of the service if the event type is not empty.
+func overrideReportingStrategy(s Service, event string, strategy cache.ReportingStrategy) error {
+	if event == "" {
+		overrider, ok := s.(ReportingStrategyOverrider)
+		if !ok {
+			return fmt.Errorf("thing: service %s does not support reporting strategy overrides", s.Name())
+		}
+
+		overrider.OverrideReportingStrategy(strategy)
+
+		return nil
+	}
+
+	overrider, ok := s.(EventReportingStrategyOverrider)
+	if !ok {
+		return fmt.Errorf("thing: service %s does not support reporting strategy overrides of single events", s.Name())
+	}
+
+	if err := overrider.OverrideEventReportingStrategy(event, strategy); err != nil {
+		return fmt.Errorf("thing: failed to override reporting strategy of event %s: %w", event, err)
+	}
+
+	return nil
+}
+
+// labeledInclusionReport returns a copy of the inclusion report with the alias and metadata persisted in the thing state applied.
+func (t *thing) labeledInclusionReport() *fimptype.ThingInclusionReport {
+	report := *t.InclusionReport()
+
+	if alias := t.state.Alias(); alias != "" {
+		report.ProductName = alias
+	}
+
+	if metadata := t.state.Metadata(); len(metadata) > 0 {
+		propSet := make(map[string]any, len(metadata))
+
+		for k, v := range metadata {
+			propSet[k] = v
+		}
+
+		report.PropSets = maps.Clone(report.PropSets)
+		if report.PropSets == nil {
+			report.PropSets = make(map[string]map[string]any)
+		}
+
+		report.PropSets[PropSetThingMetadata] = propSet
+	}
+
+	return &report
+}
+
 // If force is true, report is sent even if it did not change from previously sent one.
 func (t *thing) SendInclusionReport(force bool) (bool, error) {
-	report := t.InclusionReport()
+	report := t.labeledInclusionReport()
 
 	t.lock.Lock()
 	defer t.lock.Unlock()
@@ -190,7 +371,7 @@ func (t *thing) SendInclusionReport(force bool) (bool, error) {
 	message := fimpgo.NewObjectMessage(
 		EvtThingInclusionReport,
 		"",
-		t.inclusionReport,
+		report,
 		nil,
 		nil,
 		nil,
@@ -217,13 +398,19 @@ func (t *thing) ConnectivityReport() *ConnectivityReport {
 
 	connectivityDetails := t.connector.Connectivity()
 
+	alias := t.state.Alias()
+	if alias == "" {
+		alias = t.inclusionReport.ProductName
+	}
+
 	report := &ConnectivityReport{
 		Address:             t.Address(),
 		Hash:                t.inclusionReport.ProductHash,
-		Alias:               t.inclusionReport.ProductName,
+		Alias:               alias,
 		PowerSource:         t.inclusionReport.PowerSource,
 		WakeupInterval:      t.inclusionReport.WakeUpInterval,
 		CommTechnology:      t.inclusionReport.CommTechnology,
+		Metadata:            t.state.Metadata(),
 		ConnectivityDetails: connectivityDetails,
 	}
 
@@ -280,6 +467,8 @@ func (t *thing) SendPingReport() error {
 		PingDetails: pingDetails,
 	}
 
+	t.publisher.PublishThingEvent(newPingEvent(t, report))
+
 	message := fimpgo.NewObjectMessage(
 		EvtPingReport,
 		"",
@@ -0,0 +1,373 @@
+package adapter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/futurehomeno/cliffhanger/event"
+)
+
+const (
+	// TopologyRootAddress is an address of the root node of the topology graph representing the adapter itself.
+	TopologyRootAddress = "adapter"
+
+	// TopologyFormatJSON is a format of the topology report carrying the graph as an object.
+	TopologyFormatJSON = "json"
+	// TopologyFormatDOT is a format of the topology report carrying the graph as a Graphviz DOT document.
+	TopologyFormatDOT = "dot"
+)
+
+// weakConnQualities are connection qualities indicating a weak link.
+var weakConnQualities = []ConnQualityT{
+	ConnQualityLow,
+	ConnQualityPoor,
+	ConnQualityVeryPoor,
+	ConnQualityNoSignal,
+}
+
+// TopologyNode represents a node of the topology graph, either a thing of the adapter or an intermediate node of the network.
+type TopologyNode struct {
+	Address     string       `json:"address"`
+	Type        string       `json:"type,omitempty"`
+	Thing       bool         `json:"thing"`
+	ConnStatus  ConnStatusT  `json:"status,omitempty"`
+	ConnQuality ConnQualityT `json:"conn_quality,omitempty"`
+}
+
+// TopologyLink represents a directed link of the topology graph leading from the adapter towards a thing.
+type TopologyLink struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Quality string `json:"quality,omitempty"`
+	Weak    bool   `json:"weak"`
+}
+
+// SinglePointOfFailure represents a node of the topology graph whose failure would cut off the listed things from the adapter.
+type SinglePointOfFailure struct {
+	Address    string   `json:"address"`
+	Dependents []string `json:"dependents"`
+}
+
+// TopologyGraph is the object sent as value in the topology report describing the network of the adapter.
+type TopologyGraph struct {
+	Nodes                 []*TopologyNode         `json:"nodes"`
+	Links                 []*TopologyLink         `json:"links"`
+	WeakLinks             int                     `json:"weak_links"`
+	SinglePointsOfFailure []*SinglePointOfFailure `json:"single_points_of_failure"`
+}
+
+// Topology is a service collecting routes of things from their ping results and building a topology graph of the network.
+// It processes ping events published by things and exclusion events published by the adapter, so it must be registered
+// in an event listener using NewTopologyHandler.
+type Topology interface {
+	event.Processor
+
+	// Route returns the most recent route to a thing under the provided address.
+	Route(address string) ([]ConnectionNode, bool)
+	// Graph builds a topology graph of all things of the provided adapter.
+	Graph(adapter Adapter) *TopologyGraph
+	// Forget removes the route to a thing under the provided address.
+	Forget(address string)
+}
+
+// NewTopology creates new instance of the topology service.
+func NewTopology() Topology {
+	return &topology{
+		routes: make(map[string][]ConnectionNode),
+	}
+}
+
+// NewTopologyHandler creates a new event handler feeding the topology service with ping and exclusion events.
+func NewTopologyHandler(topology Topology, bufferSize int) *event.Handler {
+	return event.NewHandler(topology, "adapter_topology", bufferSize, event.Or(WaitForPingEvent(), WaitForExclusionEvent()))
+}
+
+type topology struct {
+	lock   sync.RWMutex
+	routes map[string][]ConnectionNode
+}
+
+// Process records a route to a thing carried by a ping event and forgets a route to a thing carried by an exclusion event.
+func (t *topology) Process(e event.Event) {
+	if exclusionEvent, ok := e.(*ExclusionEvent); ok {
+		t.Forget(exclusionEvent.Address())
+
+		return
+	}
+
+	pingEvent, ok := e.(*PingEvent)
+	if !ok || pingEvent.Report == nil {
+		log.Warnf("[cliff] Received event type=%T exp=*adapter.PingEvent", e)
+
+		return
+	}
+
+	if pingEvent.Report.PingDetails == nil || pingEvent.Report.Status != PingResultSuccess {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.routes[pingEvent.Address()] = slices.Clone(pingEvent.Report.Nodes)
+}
+
+// Route returns the most recent route to a thing under the provided address.
+func (t *topology) Route(address string) ([]ConnectionNode, bool) {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	route, ok := t.routes[address]
+
+	return slices.Clone(route), ok
+}
+
+// Forget removes the route to a thing under the provided address.
+func (t *topology) Forget(address string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.routes, address)
+}
+
+// Graph builds a topology graph of all things of the provided adapter.
+func (t *topology) Graph(adapter Adapter) *TopologyGraph {
+	b := newTopologyBuilder()
+
+	for _, th := range adapter.Things() {
+		connectivity := th.ConnectivityReport()
+
+		node := b.node(th.Address())
+		node.Thing = true
+		node.ConnStatus = connectivity.ConnStatus
+		node.ConnQuality = connectivity.ConnQuality
+
+		route, _ := t.Route(th.Address())
+		previous := TopologyRootAddress
+
+		for _, hop := range route {
+			if hop.Address == "" || hop.Address == th.Address() {
+				continue
+			}
+
+			if n := b.node(hop.Address); n.Type == "" {
+				n.Type = hop.Type
+			}
+
+			b.link(previous, hop.Address, hop.Value)
+			previous = hop.Address
+		}
+
+		b.link(previous, th.Address(), string(connectivity.ConnQuality))
+	}
+
+	return b.build()
+}
+
+// topologyBuilder is a helper accumulating nodes and links of the topology graph.
+type topologyBuilder struct {
+	nodes map[string]*TopologyNode
+	links map[[2]string]*TopologyLink
+}
+
+func newTopologyBuilder() *topologyBuilder {
+	b := &topologyBuilder{
+		nodes: make(map[string]*TopologyNode),
+		links: make(map[[2]string]*TopologyLink),
+	}
+
+	b.node(TopologyRootAddress).Type = TopologyRootAddress
+
+	return b
+}
+
+// node returns a node under the provided address, creating it if needed.
+func (b *topologyBuilder) node(address string) *TopologyNode {
+	n, ok := b.nodes[address]
+	if !ok {
+		n = &TopologyNode{Address: address}
+		b.nodes[address] = n
+	}
+
+	return n
+}
+
+// link adds a link between two nodes. If the link already exists, its quality is updated only if it was unknown.
+func (b *topologyBuilder) link(from, to, quality string) {
+	if quality == string(ConnQualityUndefined) {
+		quality = ""
+	}
+
+	key := [2]string{from, to}
+
+	l, ok := b.links[key]
+	if !ok {
+		l = &TopologyLink{From: from, To: to}
+		b.links[key] = l
+	}
+
+	if l.Quality == "" {
+		l.Quality = quality
+		l.Weak = slices.Contains(weakConnQualities, ConnQualityT(quality))
+	}
+}
+
+// build returns the topology graph with nodes and links sorted by addresses and single points of failure identified.
+func (b *topologyBuilder) build() *TopologyGraph {
+	g := &TopologyGraph{
+		Nodes:                 make([]*TopologyNode, 0, len(b.nodes)),
+		Links:                 make([]*TopologyLink, 0, len(b.links)),
+		SinglePointsOfFailure: make([]*SinglePointOfFailure, 0),
+	}
+
+	for _, n := range b.nodes {
+		g.Nodes = append(g.Nodes, n)
+	}
+
+	for _, l := range b.links {
+		g.Links = append(g.Links, l)
+
+		if l.Weak {
+			g.WeakLinks++
+		}
+	}
+
+	slices.SortFunc(g.Nodes, func(a, b *TopologyNode) int {
+		return strings.Compare(a.Address, b.Address)
+	})
+
+	slices.SortFunc(g.Links, func(a, b *TopologyLink) int {
+		if c := strings.Compare(a.From, b.From); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.To, b.To)
+	})
+
+	reachable := g.reachable("")
+
+	for _, n := range g.Nodes {
+		if n.Address == TopologyRootAddress {
+			continue
+		}
+
+		without := g.reachable(n.Address)
+
+		var dependents []string
+
+		for _, d := range g.Nodes {
+			if d.Thing && d.Address != n.Address && reachable[d.Address] && !without[d.Address] {
+				dependents = append(dependents, d.Address)
+			}
+		}
+
+		if len(dependents) > 0 {
+			g.SinglePointsOfFailure = append(g.SinglePointsOfFailure, &SinglePointOfFailure{
+				Address:    n.Address,
+				Dependents: dependents,
+			})
+		}
+	}
+
+	return g
+}
+
+// reachable returns addresses of all nodes reachable from the root, skipping the excluded node.
+func (g *TopologyGraph) reachable(excluded string) map[string]bool {
+	visited := map[string]bool{TopologyRootAddress: true}
+	queue := []string{TopologyRootAddress}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, l := range g.Links {
+			if l.From != current || l.To == excluded || visited[l.To] {
+				continue
+			}
+
+			visited[l.To] = true
+			queue = append(queue, l.To)
+		}
+	}
+
+	return visited
+}
+
+// DOT renders the topology graph as a Graphviz DOT document.
+// Weak links are drawn in red dashed lines, while single points of failure are highlighted in orange.
+func (g *TopologyGraph) DOT() string {
+	spof := make(map[string]bool, len(g.SinglePointsOfFailure))
+
+	for _, s := range g.SinglePointsOfFailure {
+		spof[s.Address] = true
+	}
+
+	sb := &strings.Builder{}
+
+	sb.WriteString("digraph topology {\n")
+
+	for _, n := range g.Nodes {
+		label := dotQuote(n.Address)
+		if n.Type != "" && n.Type != n.Address {
+			label = dotQuote(n.Address + "\n" + n.Type)
+		}
+
+		attributes := []string{"label=" + label}
+
+		switch {
+		case n.Address == TopologyRootAddress:
+			attributes = append(attributes, "shape=doublecircle")
+		case n.Thing:
+			attributes = append(attributes, "shape=box")
+		default:
+			attributes = append(attributes, "shape=ellipse")
+		}
+
+		if n.ConnStatus == ConnStatusDown {
+			attributes = append(attributes, "color=gray", "fontcolor=gray")
+		}
+
+		if spof[n.Address] {
+			attributes = append(attributes, "style=filled", "fillcolor=orange")
+		}
+
+		fmt.Fprintf(sb, "  %s [%s];\n", dotQuote(n.Address), strings.Join(attributes, ", "))
+	}
+
+	for _, l := range g.Links {
+		var attributes []string
+
+		if l.Quality != "" {
+			attributes = append(attributes, "label="+dotQuote(l.Quality))
+		}
+
+		if l.Weak {
+			attributes = append(attributes, "color=red", "style=dashed")
+		}
+
+		if len(attributes) == 0 {
+			fmt.Fprintf(sb, "  %s -> %s;\n", dotQuote(l.From), dotQuote(l.To))
+
+			continue
+		}
+
+		fmt.Fprintf(sb, "  %s -> %s [%s];\n", dotQuote(l.From), dotQuote(l.To), strings.Join(attributes, ", "))
+	}
+
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// dotQuote returns a quoted DOT identifier. Line breaks are rendered as DOT escape sequences.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+
+	return `"` + s + `"`
+}
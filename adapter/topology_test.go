@@ -0,0 +1,155 @@
+package adapter_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/futurehomeno/fimpgo"
+	"github.com/futurehomeno/fimpgo/fimptype"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/router"
+	"github.com/futurehomeno/cliffhanger/task"
+	adapterhelper "github.com/futurehomeno/cliffhanger/test/helper/adapter"
+	mockedadapter "github.com/futurehomeno/cliffhanger/test/mocks/adapter"
+	"github.com/futurehomeno/cliffhanger/test/suite"
+)
+
+func TestRouteTopology(t *testing.T) { //nolint:paralleltest
+	topology := adapter.NewTopology()
+
+	s := &suite.Suite{
+		Cases: []*suite.Case{
+			{
+				Name:     "topology graph is built from routes of things",
+				TearDown: adapterhelper.TearDownAdapter(testAdapterWorkDir),
+				Setup:    setupTopology(topology),
+				Nodes: []*suite.Node{
+					{
+						Name:    "get topology as json",
+						Command: suite.NullMessage(testAdapterCmdTopic, adapter.CmdNetworkGetTopology, testAdapterName),
+						Expectations: []*suite.Expectation{
+							expectTopologyReport(func(g *adapter.TopologyGraph) bool {
+								return len(g.Nodes) == 6 &&
+									len(g.Links) == 5 &&
+									g.WeakLinks == 1 &&
+									assert.ObjectsAreEqual([]*adapter.SinglePointOfFailure{
+										{Address: "10", Dependents: []string{"3", "4"}},
+										{Address: "11", Dependents: []string{"4"}},
+									}, g.SinglePointsOfFailure)
+							}),
+						},
+					},
+					{
+						Name:    "get topology as dot",
+						Command: suite.StringMessage(testAdapterCmdTopic, adapter.CmdNetworkGetTopology, testAdapterName, adapter.TopologyFormatDOT),
+						Expectations: []*suite.Expectation{
+							suite.NewExpectation().
+								ExpectTopic(testAdapterEvtTopic).
+								ExpectType(adapter.EvtNetworkTopologyReport).
+								ExpectService(testAdapterName).
+								Expect(router.MessageVoterFn(func(m *fimpgo.Message) bool {
+									dot, err := m.Payload.GetStringValue()
+
+									return err == nil &&
+										strings.HasPrefix(dot, "digraph topology {") &&
+										strings.Contains(dot, `"adapter" -> "10" [label="poor", color=red, style=dashed];`) &&
+										strings.Contains(dot, `"10" [label="10\nrepeater", shape=ellipse, style=filled, fillcolor=orange];`)
+								})).
+								ExactlyOnce(),
+						},
+					},
+					{
+						Name:    "unsupported format responds with error",
+						Command: suite.StringMessage(testAdapterCmdTopic, adapter.CmdNetworkGetTopology, testAdapterName, "xml"),
+						Expectations: []*suite.Expectation{
+							suite.ExpectError(testAdapterEvtTopic, testAdapterName).ExactlyOnce(),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	s.Run(t)
+}
+
+func TestTopology_Route(t *testing.T) {
+	t.Parallel()
+
+	topology := adapter.NewTopology()
+
+	topology.Process(newTestPingEvent("2", adapter.PingResultSuccess, adapter.ConnectionNode{Address: "10"}))
+	topology.Process(newTestPingEvent("2", adapter.PingResultFailed))
+
+	route, ok := topology.Route("2")
+	assert.True(t, ok)
+	assert.Equal(t, []adapter.ConnectionNode{{Address: "10"}}, route, "route must not be overridden by a failed ping")
+
+	_, ok = topology.Route("3")
+	assert.False(t, ok)
+
+	topology.Process(&adapter.ExclusionEvent{ThingEvent: adapter.NewThingEvent("2", nil)})
+
+	_, ok = topology.Route("2")
+	assert.False(t, ok, "route to an excluded thing must be forgotten")
+}
+
+func setupTopology(topology adapter.Topology) suite.BaseSetup {
+	return func(t *testing.T, mqtt *fimpgo.MqttTransport) ([]*router.Routing, []*task.Task, []suite.Mock) {
+		t.Helper()
+
+		factory := adapterhelper.FactoryHelper(func(_ adapter.Adapter, publisher adapter.Publisher, thingState adapter.ThingState) (adapter.Thing, error) {
+			return adapter.NewThing(publisher, thingState, &adapter.ThingConfig{
+				InclusionReport: &fimptype.ThingInclusionReport{Address: thingState.Address()},
+				Connector:       mockedadapter.NewDefaultConnector(t),
+			}), nil
+		})
+
+		ad := adapterhelper.PrepareSeededAdapter(t, testAdapterWorkDir, mqtt, factory, adapter.ThingSeeds{
+			{ID: "B", CustomAddress: testThingAddressB},
+			{ID: "C", CustomAddress: testThingAddressC},
+			{ID: "D", CustomAddress: "4"},
+		})
+
+		topology.Process(newTestPingEvent(testThingAddressB, adapter.PingResultSuccess))
+		topology.Process(newTestPingEvent(testThingAddressC, adapter.PingResultSuccess,
+			adapter.ConnectionNode{Address: "10", Type: "repeater", Value: "poor"},
+		))
+		topology.Process(newTestPingEvent("4", adapter.PingResultSuccess,
+			adapter.ConnectionNode{Address: "10", Type: "repeater", Value: "poor"},
+			adapter.ConnectionNode{Address: "11", Type: "repeater", Value: "good"},
+			adapter.ConnectionNode{Address: "4"},
+		))
+
+		return adapter.RouteTopology(ad, topology), nil, nil
+	}
+}
+
+func newTestPingEvent(address string, status adapter.PingResult, nodes ...adapter.ConnectionNode) *adapter.PingEvent {
+	return &adapter.PingEvent{
+		ThingEvent: adapter.NewThingEvent(address, nil),
+		Report: &adapter.PingReport{
+			Address:     address,
+			PingDetails: &adapter.PingDetails{Status: status, Nodes: nodes},
+		},
+	}
+}
+
+func expectTopologyReport(check func(g *adapter.TopologyGraph) bool) *suite.Expectation {
+	return suite.NewExpectation().
+		ExpectTopic(testAdapterEvtTopic).
+		ExpectType(adapter.EvtNetworkTopologyReport).
+		ExpectService(testAdapterName).
+		Expect(router.MessageVoterFn(func(m *fimpgo.Message) bool {
+			graph := &adapter.TopologyGraph{}
+
+			if err := m.Payload.GetObjectValue(graph); err != nil {
+				return false
+			}
+
+			return check(graph)
+		})).
+		ExactlyOnce()
+}
@@ -0,0 +1,212 @@
+package unitconv
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Quantity represents a physical quantity. Only units of the same quantity are convertible.
+type Quantity string
+
+// Constants defining quantities of units registered in the default registry.
+const (
+	QuantityTemperature    Quantity = "temperature"
+	QuantityPower          Quantity = "power"
+	QuantityEnergy         Quantity = "energy"
+	QuantityApparentPower  Quantity = "apparent_power"
+	QuantityApparentEnergy Quantity = "apparent_energy"
+	QuantityReactivePower  Quantity = "reactive_power"
+	QuantityReactiveEnergy Quantity = "reactive_energy"
+	QuantityVolume         Quantity = "volume"
+	QuantityPressure       Quantity = "pressure"
+	QuantitySpeed          Quantity = "speed"
+)
+
+// Registry is a registry of units and conversions between compatible units.
+type Registry interface {
+	// Register registers a unit of the quantity, defined by a linear conversion to the base unit of the quantity,
+	// i.e. the base value equals to value * factor + offset.
+	Register(unit string, quantity Quantity, factor, offset float64)
+	// Convert converts the value between compatible units.
+	Convert(value float64, from, to string) (float64, error)
+	// Compatible returns true if the value can be converted between the units.
+	Compatible(from, to string) bool
+	// Resolve returns a unit from the provided native units in which the value of the target unit can be obtained.
+	// The target unit itself is preferred over converted ones.
+	Resolve(target string, native []string) (string, bool)
+	// Expand returns the provided units followed by all other registered units compatible with any of them.
+	Expand(units []string) []string
+}
+
+// NewRegistry creates new instance of an empty units registry.
+func NewRegistry() Registry {
+	return &registry{
+		definitions: make(map[string]definition),
+	}
+}
+
+// NewDefaultRegistry creates new instance of a units registry containing common units used by FIMP services.
+func NewDefaultRegistry() Registry {
+	r := NewRegistry()
+
+	r.Register("C", QuantityTemperature, 1, 0)
+	r.Register("F", QuantityTemperature, 5.0/9, -32*5.0/9)
+	r.Register("K", QuantityTemperature, 1, -273.15)
+
+	r.Register("W", QuantityPower, 1, 0)
+	r.Register("kW", QuantityPower, 1e3, 0)
+	r.Register("MW", QuantityPower, 1e6, 0)
+
+	r.Register("Wh", QuantityEnergy, 1, 0)
+	r.Register("kWh", QuantityEnergy, 1e3, 0)
+	r.Register("MWh", QuantityEnergy, 1e6, 0)
+
+	r.Register("VA", QuantityApparentPower, 1, 0)
+	r.Register("kVA", QuantityApparentPower, 1e3, 0)
+	r.Register("VAh", QuantityApparentEnergy, 1, 0)
+	r.Register("kVAh", QuantityApparentEnergy, 1e3, 0)
+
+	r.Register("VAr", QuantityReactivePower, 1, 0)
+	r.Register("kVAr", QuantityReactivePower, 1e3, 0)
+	r.Register("VArh", QuantityReactiveEnergy, 1, 0)
+	r.Register("kVArh", QuantityReactiveEnergy, 1e3, 0)
+
+	r.Register("cub_m", QuantityVolume, 1, 0)
+	r.Register("cub_f", QuantityVolume, 0.028316846592, 0)
+	r.Register("gallon", QuantityVolume, 0.003785411784, 0)
+	r.Register("L", QuantityVolume, 1e-3, 0)
+
+	r.Register("mbar", QuantityPressure, 1, 0)
+	r.Register("hPa", QuantityPressure, 1, 0)
+	r.Register("kPa", QuantityPressure, 10, 0)
+	r.Register("bar", QuantityPressure, 1e3, 0)
+
+	r.Register("kph", QuantitySpeed, 1, 0)
+	r.Register("m/s", QuantitySpeed, 3.6, 0)
+	r.Register("mph", QuantitySpeed, 1.609344, 0)
+
+	return r
+}
+
+// definition represents a registered unit.
+type definition struct {
+	quantity Quantity
+	factor   float64
+	offset   float64
+	order    int
+}
+
+type registry struct {
+	lock        sync.RWMutex
+	definitions map[string]definition
+}
+
+// Register registers a unit of the quantity, defined by a linear conversion to the base unit of the quantity.
+func (r *registry) Register(unit string, quantity Quantity, factor, offset float64) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	order := len(r.definitions)
+	if d, ok := r.definitions[unit]; ok {
+		order = d.order
+	}
+
+	r.definitions[unit] = definition{
+		quantity: quantity,
+		factor:   factor,
+		offset:   offset,
+		order:    order,
+	}
+}
+
+// Convert converts the value between compatible units.
+func (r *registry) Convert(value float64, from, to string) (float64, error) {
+	if from == to {
+		return value, nil
+	}
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	fromDefinition, ok := r.definitions[from]
+	if !ok {
+		return 0, fmt.Errorf("unit conversion: unit is not registered: %s", from)
+	}
+
+	toDefinition, ok := r.definitions[to]
+	if !ok {
+		return 0, fmt.Errorf("unit conversion: unit is not registered: %s", to)
+	}
+
+	if fromDefinition.quantity != toDefinition.quantity {
+		return 0, fmt.Errorf("unit conversion: units %s and %s are not compatible", from, to)
+	}
+
+	base := value*fromDefinition.factor + fromDefinition.offset
+
+	return (base - toDefinition.offset) / toDefinition.factor, nil
+}
+
+// Compatible returns true if the value can be converted between the units.
+func (r *registry) Compatible(from, to string) bool {
+	if from == to {
+		return true
+	}
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	fromDefinition, ok := r.definitions[from]
+	if !ok {
+		return false
+	}
+
+	toDefinition, ok := r.definitions[to]
+
+	return ok && fromDefinition.quantity == toDefinition.quantity
+}
+
+// Resolve returns a unit from the provided native units in which the value of the target unit can be obtained.
+func (r *registry) Resolve(target string, native []string) (string, bool) {
+	if slices.Contains(native, target) {
+		return target, true
+	}
+
+	for _, unit := range native {
+		if r.Compatible(unit, target) {
+			return unit, true
+		}
+	}
+
+	return "", false
+}
+
+// Expand returns the provided units followed by all other registered units compatible with any of them.
+func (r *registry) Expand(units []string) []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	expanded := slices.Clone(units)
+	quantities := make(map[Quantity]bool)
+
+	for _, unit := range units {
+		if d, ok := r.definitions[unit]; ok {
+			quantities[d.quantity] = true
+		}
+	}
+
+	candidates := make([]string, 0)
+
+	for unit, d := range r.definitions {
+		if quantities[d.quantity] && !slices.Contains(expanded, unit) {
+			candidates = append(candidates, unit)
+		}
+	}
+
+	slices.SortFunc(candidates, func(a, b string) int {
+		return r.definitions[a].order - r.definitions[b].order
+	})
+
+	return append(expanded, candidates...)
+}
@@ -0,0 +1,88 @@
+package unitconv_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/futurehomeno/cliffhanger/adapter/unitconv"
+)
+
+func TestRegistry_Convert(t *testing.T) {
+	t.Parallel()
+
+	tcs := []struct {
+		name     string
+		value    float64
+		from     string
+		to       string
+		expected float64
+		wantErr  bool
+	}{
+		{name: "identity", value: 21.5, from: "C", to: "C", expected: 21.5},
+		{name: "celsius to fahrenheit", value: 100, from: "C", to: "F", expected: 212},
+		{name: "fahrenheit to celsius", value: 32, from: "F", to: "C", expected: 0},
+		{name: "kelvin to celsius", value: 273.15, from: "K", to: "C", expected: 0},
+		{name: "watts to kilowatts", value: 1500, from: "W", to: "kW", expected: 1.5},
+		{name: "kilowatt hours to watt hours", value: 2.5, from: "kWh", to: "Wh", expected: 2500},
+		{name: "cubic meters to liters", value: 1.2, from: "cub_m", to: "L", expected: 1200},
+		{name: "kilopascals to millibars", value: 101.3, from: "kPa", to: "mbar", expected: 1013},
+		{name: "incompatible units", value: 1, from: "W", to: "kWh", wantErr: true},
+		{name: "unknown unit", value: 1, from: "W", to: "unknown", wantErr: true},
+	}
+
+	r := unitconv.NewDefaultRegistry()
+
+	for _, tc := range tcs {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := r.Convert(tc.value, tc.from, tc.to)
+			if tc.wantErr {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tc.expected, got, 1e-9)
+		})
+	}
+}
+
+func TestRegistry_Resolve(t *testing.T) {
+	t.Parallel()
+
+	r := unitconv.NewDefaultRegistry()
+
+	unit, ok := r.Resolve("F", []string{"C"})
+	assert.True(t, ok)
+	assert.Equal(t, "C", unit)
+
+	unit, ok = r.Resolve("W", []string{"kWh", "W"})
+	assert.True(t, ok)
+	assert.Equal(t, "W", unit)
+
+	_, ok = r.Resolve("kW", []string{"kWh"})
+	assert.False(t, ok)
+
+	unit, ok = r.Resolve("pm25", []string{"pm25"})
+	assert.True(t, ok)
+	assert.Equal(t, "pm25", unit)
+}
+
+func TestRegistry_Expand(t *testing.T) {
+	t.Parallel()
+
+	r := unitconv.NewDefaultRegistry()
+
+	assert.Equal(t, []string{"kWh", "W", "kW", "MW", "Wh", "MWh"}, r.Expand([]string{"kWh", "W"}))
+	assert.Equal(t, []string{"C", "F", "K"}, r.Expand([]string{"C"}))
+	assert.Equal(t, []string{"pm25"}, r.Expand([]string{"pm25"}))
+
+	r.Register("Btu", unitconv.QuantityEnergy, 0.29307107, 0)
+
+	assert.Equal(t, []string{"Wh", "kWh", "MWh", "Btu"}, r.Expand([]string{"Wh"}))
+}
@@ -0,0 +1,365 @@
+package adapter
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/futurehomeno/cliffhanger/adapter/cache"
+	"github.com/futurehomeno/cliffhanger/backoff"
+	"github.com/futurehomeno/cliffhanger/task"
+)
+
+// constants defining default option values for the watchdog.
+const (
+	defaultWatchdogFailureThreshold     = 3
+	defaultWatchdogInitialBackoff       = 30 * time.Second
+	defaultWatchdogRepeatedBackoff      = 5 * time.Minute
+	defaultWatchdogFinalBackoff         = 30 * time.Minute
+	defaultWatchdogInitialFailureCount  = 3
+	defaultWatchdogRepeatedFailureCount = 6
+)
+
+// WatchdogConfig represents a configuration of the connectivity watchdog.
+type WatchdogConfig struct {
+	// FailureThreshold is a number of consecutive failures after which a thing is considered to be down.
+	FailureThreshold int
+	// MarkFailed adds failed operationability to connectivity details of a thing considered to be down.
+	MarkFailed bool
+	// InitialBackoff, RepeatedBackoff and FinalBackoff define delays between consecutive reconnection attempts.
+	InitialBackoff  time.Duration
+	RepeatedBackoff time.Duration
+	FinalBackoff    time.Duration
+	// InitialFailureCount and RepeatedFailureCount define after how many reconnection attempts the next backoff delay is applied.
+	InitialFailureCount  uint32
+	RepeatedFailureCount uint32
+}
+
+// withDefaults sets default values for all options which were not provided.
+func (c *WatchdogConfig) withDefaults() *WatchdogConfig {
+	if c.FailureThreshold <= 0 {
+		c.FailureThreshold = defaultWatchdogFailureThreshold
+	}
+
+	if c.InitialBackoff == 0 {
+		c.InitialBackoff = defaultWatchdogInitialBackoff
+	}
+
+	if c.RepeatedBackoff == 0 {
+		c.RepeatedBackoff = defaultWatchdogRepeatedBackoff
+	}
+
+	if c.FinalBackoff == 0 {
+		c.FinalBackoff = defaultWatchdogFinalBackoff
+	}
+
+	if c.InitialFailureCount == 0 {
+		c.InitialFailureCount = defaultWatchdogInitialFailureCount
+	}
+
+	if c.RepeatedFailureCount == 0 {
+		c.RepeatedFailureCount = defaultWatchdogRepeatedFailureCount
+	}
+
+	return c
+}
+
+// Watchdog is a service tracking consecutive communication failures of things and deriving their connectivity status.
+// Once a thing exceeds configured failure threshold it is reported as down until a successful communication is recorded.
+type Watchdog interface {
+	// Success records a successful communication with a thing under the provided address.
+	Success(address string)
+	// Failure records a failed communication with a thing under the provided address.
+	Failure(address string)
+	// IsDown returns true if the thing under the provided address is considered to be down.
+	IsDown(address string) bool
+	// Forget removes all information tracked for a thing under the provided address.
+	Forget(address string)
+	// Connector wraps the provided connector of a thing, so its connectivity details reflect the state tracked by the watchdog.
+	Connector(address string, connector Connector) Connector
+
+	// check sends connectivity report if the thing status changed and attempts to reconnect the thing if it is down.
+	check(t Thing)
+}
+
+// NewWatchdog creates new instance of a connectivity watchdog.
+func NewWatchdog(cfg *WatchdogConfig) Watchdog {
+	if cfg == nil {
+		cfg = &WatchdogConfig{}
+	}
+
+	return &watchdog{
+		cfg:     cfg.withDefaults(),
+		entries: make(map[string]*watchdogEntry),
+	}
+}
+
+// watchdog is a private implementation of the watchdog service.
+type watchdog struct {
+	cfg     *WatchdogConfig
+	lock    sync.Mutex
+	entries map[string]*watchdogEntry
+}
+
+// watchdogEntry holds information tracked for a single thing.
+type watchdogEntry struct {
+	connector Connector
+	failures  int
+	down      bool
+	changed   bool
+	backoff   backoff.Stateful
+}
+
+// Success records a successful communication with a thing under the provided address.
+func (w *watchdog) Success(address string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	e := w.entry(address)
+
+	e.failures = 0
+	e.backoff.Reset()
+
+	if e.down {
+		e.down = false
+		e.changed = true
+	}
+}
+
+// Failure records a failed communication with a thing under the provided address.
+func (w *watchdog) Failure(address string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	e := w.entry(address)
+
+	e.failures++
+
+	if !e.down && e.failures >= w.cfg.FailureThreshold {
+		e.down = true
+		e.changed = true
+	}
+}
+
+// IsDown returns true if the thing under the provided address is considered to be down.
+func (w *watchdog) IsDown(address string) bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	e, ok := w.entries[address]
+	if !ok {
+		return false
+	}
+
+	return e.down
+}
+
+// Forget removes all information tracked for a thing under the provided address.
+func (w *watchdog) Forget(address string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	delete(w.entries, address)
+}
+
+// Connector wraps the provided connector of a thing, so its connectivity details reflect the state tracked by the watchdog.
+func (w *watchdog) Connector(address string, connector Connector) Connector {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	// A new connector means a new thing under the address, so any previously tracked state is discarded.
+	delete(w.entries, address)
+	w.entry(address).connector = connector
+
+	override := &connectivityOverride{
+		isDown:        func() bool { return w.IsDown(address) },
+		onPingSuccess: func() { w.Success(address) },
+		onDisconnect:  func() { w.Forget(address) },
+	}
+
+	if w.cfg.MarkFailed {
+		override.operationability = OperationabilityFailed
+	}
+
+	return overrideConnector(connector, override)
+}
+
+// check sends connectivity report if the thing status changed and attempts to reconnect the thing if it is down.
+func (w *watchdog) check(t Thing) {
+	if w.popChanged(t.Address()) {
+		_, err := t.SendConnectivityReport(true)
+		if err != nil {
+			log.WithError(err).WithField("address", t.Address()).Errorf("watchdog: failed to send connectivity report")
+		}
+	}
+
+	connector, ok := w.reconnectCandidate(t.Address())
+	if !ok {
+		return
+	}
+
+	log.WithField("address", t.Address()).Infof("watchdog: attempting to reconnect the thing")
+
+	t.Connect()
+
+	if connector == nil {
+		return
+	}
+
+	details := connector.Ping()
+	if details == nil || details.Status != PingResultSuccess {
+		return
+	}
+
+	w.Success(t.Address())
+
+	if w.popChanged(t.Address()) {
+		_, err := t.SendConnectivityReport(true)
+		if err != nil {
+			log.WithError(err).WithField("address", t.Address()).Errorf("watchdog: failed to send connectivity report")
+		}
+	}
+}
+
+// reconnectCandidate returns the connector of a thing and true if the thing is down and a reconnection attempt is due.
+func (w *watchdog) reconnectCandidate(address string) (Connector, bool) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	e, ok := w.entries[address]
+	if !ok || !e.down || e.backoff.Should() {
+		return nil, false
+	}
+
+	e.backoff.Fail()
+
+	return e.connector, true
+}
+
+// popChanged returns true if the status of a thing changed since the last check and clears the flag.
+func (w *watchdog) popChanged(address string) bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	e, ok := w.entries[address]
+	if !ok || !e.changed {
+		return false
+	}
+
+	e.changed = false
+
+	return true
+}
+
+// entry returns an entry for the provided address, creating it if necessary. Must be called under lock.
+func (w *watchdog) entry(address string) *watchdogEntry {
+	e, ok := w.entries[address]
+	if !ok {
+		e = &watchdogEntry{
+			backoff: backoff.NewStateful(
+				w.cfg.InitialBackoff,
+				w.cfg.RepeatedBackoff,
+				w.cfg.FinalBackoff,
+				w.cfg.InitialFailureCount,
+				w.cfg.RepeatedFailureCount,
+			),
+		}
+
+		w.entries[address] = e
+	}
+
+	return e
+}
+
+// WatchRefresher wraps the provided refresher, so all its failures and successes are recorded by the watchdog for the thing under provided address.
+// Stale values served on failure by a refresher created with cache.WithStaleOnFailure are recorded as failures. The returned refresher
+// implements cache.AgeAwareRefresher if the provided one does.
+func WatchRefresher[T any](refresher cache.Refresher[T], watchdog Watchdog, address string) cache.Refresher[T] {
+	r := &watchedRefresher[T]{
+		Refresher: refresher,
+		watchdog:  watchdog,
+		address:   address,
+	}
+
+	if ageAware, ok := refresher.(cache.AgeAwareRefresher[T]); ok {
+		return &watchedAgeAwareRefresher[T]{
+			watchedRefresher: r,
+			ageAware:         ageAware,
+		}
+	}
+
+	return r
+}
+
+// watchedRefresher is a refresher decorator recording outcomes of refresh calls in the watchdog.
+type watchedRefresher[T any] struct {
+	cache.Refresher[T]
+
+	watchdog Watchdog
+	address  string
+}
+
+// Refresh refreshes data if required and returns it.
+func (r *watchedRefresher[T]) Refresh() (T, error) {
+	if ageAware, ok := r.Refresher.(cache.AgeAwareRefresher[T]); ok {
+		value, _, stale, err := ageAware.RefreshWithAge()
+
+		r.record(stale, err)
+
+		return value, err
+	}
+
+	value, err := r.Refresher.Refresh()
+
+	r.record(false, err)
+
+	return value, err
+}
+
+// record records a failure if the refresh returned an error or served a stale value, and a success otherwise.
+func (r *watchedRefresher[T]) record(stale bool, err error) {
+	if err != nil || stale {
+		r.watchdog.Failure(r.address)
+
+		return
+	}
+
+	r.watchdog.Success(r.address)
+}
+
+// watchedAgeAwareRefresher is a watched refresher decorator preserving the age awareness of the wrapped refresher.
+type watchedAgeAwareRefresher[T any] struct {
+	*watchedRefresher[T]
+
+	ageAware cache.AgeAwareRefresher[T]
+}
+
+// RefreshWithAge refreshes data if required and returns it together with its age and staleness.
+func (r *watchedAgeAwareRefresher[T]) RefreshWithAge() (T, time.Duration, bool, error) {
+	value, age, stale, err := r.ageAware.RefreshWithAge()
+
+	r.record(stale, err)
+
+	return value, age, stale, err
+}
+
+// IsStale returns true if the last invocation of the refresh function failed.
+func (r *watchedAgeAwareRefresher[T]) IsStale() bool {
+	return r.ageAware.IsStale()
+}
+
+// TaskWatchdog creates a task reporting connectivity changes detected by the watchdog and reconnecting things which are down.
+func TaskWatchdog(adapter Adapter, watchdog Watchdog, interval time.Duration, voters ...task.Voter) *task.Task {
+	voters = append(voters, IsInitialized(adapter))
+
+	return task.New(handleWatchdog(adapter, watchdog), interval, voters...)
+}
+
+func handleWatchdog(adapter Adapter, watchdog Watchdog) func() {
+	return func() {
+		for _, t := range adapter.Things() {
+			watchdog.check(t)
+		}
+	}
+}
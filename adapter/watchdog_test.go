@@ -0,0 +1,209 @@
+package adapter_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/futurehomeno/fimpgo"
+	"github.com/futurehomeno/fimpgo/fimptype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/futurehomeno/cliffhanger/adapter"
+	"github.com/futurehomeno/cliffhanger/adapter/cache"
+	"github.com/futurehomeno/cliffhanger/router"
+	"github.com/futurehomeno/cliffhanger/task"
+	adapterhelper "github.com/futurehomeno/cliffhanger/test/helper/adapter"
+	mockedadapter "github.com/futurehomeno/cliffhanger/test/mocks/adapter"
+	"github.com/futurehomeno/cliffhanger/test/suite"
+)
+
+func TestWatchdog_Connector(t *testing.T) {
+	t.Parallel()
+
+	connector := mockedadapter.NewConnector(t).MockConnectivity(&adapter.ConnectivityDetails{
+		ConnStatus:       adapter.ConnStatusUp,
+		Operationability: []adapter.OperationabilityT{adapter.OperationabilityReady},
+		ConnQuality:      adapter.ConnQualityHigh,
+		ConnType:         adapter.ConnTypeDirect,
+	}, false)
+
+	w := adapter.NewWatchdog(&adapter.WatchdogConfig{FailureThreshold: 2, MarkFailed: true})
+	c := w.Connector("2", connector)
+
+	_, ok := c.(adapter.ControllableConnector)
+	assert.False(t, ok, "wrapped connector must not become controllable")
+
+	w.Failure("2")
+	assert.False(t, w.IsDown("2"))
+	assert.Equal(t, adapter.ConnStatusUp, c.Connectivity().ConnStatus)
+
+	w.Failure("2")
+	assert.True(t, w.IsDown("2"))
+
+	details := c.Connectivity()
+	assert.Equal(t, adapter.ConnStatusDown, details.ConnStatus)
+	assert.Equal(t, adapter.ConnQualityHigh, details.ConnQuality)
+	assert.Equal(t, []adapter.OperationabilityT{adapter.OperationabilityReady, adapter.OperationabilityFailed}, details.Operationability)
+
+	w.Success("2")
+	assert.False(t, w.IsDown("2"))
+	assert.Equal(t, adapter.ConnStatusUp, c.Connectivity().ConnStatus)
+	assert.Equal(t, []adapter.OperationabilityT{adapter.OperationabilityReady}, c.Connectivity().Operationability)
+}
+
+func TestWatchdog_ControllableConnector(t *testing.T) {
+	t.Parallel()
+
+	w := adapter.NewWatchdog(nil)
+	c := w.Connector("2", mockedadapter.NewControllableConnector(t))
+
+	_, ok := c.(adapter.ControllableConnector)
+	assert.True(t, ok, "wrapped connector must remain controllable")
+}
+
+func TestWatchRefresher(t *testing.T) {
+	t.Parallel()
+
+	fail := true
+
+	refresher := cache.NewRefresher(func() (string, error) {
+		if fail {
+			return "", errors.New("test")
+		}
+
+		return "test", nil
+	}, time.Hour)
+
+	w := adapter.NewWatchdog(&adapter.WatchdogConfig{FailureThreshold: 3})
+	r := adapter.WatchRefresher(refresher, w, "2")
+
+	for range 3 {
+		_, err := r.Refresh()
+		assert.Error(t, err)
+	}
+
+	assert.True(t, w.IsDown("2"))
+
+	fail = false
+
+	got, err := r.Refresh()
+	assert.NoError(t, err)
+	assert.Equal(t, "test", got)
+	assert.False(t, w.IsDown("2"))
+}
+
+func TestWatchRefresher_StaleOnFailure(t *testing.T) {
+	t.Parallel()
+
+	fail := false
+
+	refresher := cache.NewRefresher(func() (string, error) {
+		if fail {
+			return "", errors.New("test")
+		}
+
+		return "test", nil
+	}, time.Nanosecond, cache.WithStaleOnFailure(0))
+
+	w := adapter.NewWatchdog(&adapter.WatchdogConfig{FailureThreshold: 3})
+
+	r, ok := adapter.WatchRefresher(refresher, w, "2").(cache.AgeAwareRefresher[string])
+	require.True(t, ok, "age awareness of the wrapped refresher is preserved")
+
+	got, err := r.Refresh()
+	assert.NoError(t, err)
+	assert.Equal(t, "test", got)
+	assert.False(t, r.IsStale())
+
+	fail = true
+
+	time.Sleep(time.Millisecond)
+
+	got, err = r.Refresh()
+	assert.NoError(t, err)
+	assert.Equal(t, "test", got)
+	assert.True(t, r.IsStale())
+
+	for range 2 {
+		time.Sleep(time.Millisecond)
+
+		got, _, stale, err := r.RefreshWithAge()
+		assert.NoError(t, err)
+		assert.Equal(t, "test", got)
+		assert.True(t, stale)
+	}
+
+	assert.True(t, w.IsDown("2"))
+}
+
+func TestTaskWatchdog(t *testing.T) { //nolint:paralleltest
+	w := adapter.NewWatchdog(&adapter.WatchdogConfig{FailureThreshold: 2, MarkFailed: true})
+
+	s := &suite.Suite{
+		Cases: []*suite.Case{
+			{
+				Name:     "watchdog reports a thing as down and reconnects it",
+				TearDown: adapterhelper.TearDownAdapter(testAdapterWorkDir),
+				Setup:    setupWatchdogTask(w),
+				Nodes: []*suite.Node{
+					{
+						Name: "consecutive failures flip the thing down and a successful reconnect brings it back up",
+						InitCallbacks: []suite.Callback{
+							func(t *testing.T) {
+								t.Helper()
+
+								w.Failure(testThingAddressB)
+								w.Failure(testThingAddressB)
+							},
+						},
+						Timeout: 500 * time.Millisecond,
+						Expectations: []*suite.Expectation{
+							expectNodeReportWithStatus(testThingAddressB, adapter.ConnStatusDown).
+								Expect(router.MessageVoterFn(func(m *fimpgo.Message) bool {
+									report := &adapter.ConnectivityReport{}
+
+									if err := m.Payload.GetObjectValue(report); err != nil {
+										return false
+									}
+
+									return len(report.Operationability) == 1 && report.Operationability[0] == adapter.OperationabilityFailed
+								})).
+								ExactlyOnce(),
+							expectNodeReportWithStatus(testThingAddressB, adapter.ConnStatusUp).ExactlyOnce(),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	s.Run(t)
+}
+
+func setupWatchdogTask(w adapter.Watchdog) suite.BaseSetup {
+	return func(t *testing.T, mqtt *fimpgo.MqttTransport) ([]*router.Routing, []*task.Task, []suite.Mock) {
+		t.Helper()
+
+		connector := mockedadapter.NewDefaultControllableConnector(t)
+		connector.On("Ping").Return(&adapter.PingDetails{Status: adapter.PingResultSuccess}).Once()
+
+		factory := adapterhelper.FactoryHelper(func(_ adapter.Adapter, publisher adapter.Publisher, thingState adapter.ThingState) (adapter.Thing, error) {
+			cfg := &adapter.ThingConfig{
+				InclusionReport: &fimptype.ThingInclusionReport{Address: thingState.Address()},
+				Connector:       w.Connector(thingState.Address(), connector),
+			}
+
+			return adapter.NewThing(publisher, thingState, cfg), nil
+		})
+
+		seeds := adapter.ThingSeeds{
+			{ID: "B", CustomAddress: testThingAddressB},
+		}
+
+		ad := adapterhelper.PrepareSeededAdapter(t, testAdapterWorkDir, mqtt, factory, seeds)
+
+		return nil, []*task.Task{adapter.TaskWatchdog(ad, w, reportingInterval)}, []suite.Mock{connector}
+	}
+}
@@ -158,6 +158,49 @@ func TestDatabase_Recovery(t *testing.T) { //nolint:paralleltest
 	assert.Equal(t, []string{"test_key1", "test_key2", "test_key3", "test_key4", "test_key5"}, keys)
 }
 
+func TestDatabase_DumpDomain_RestoreDomain(t *testing.T) { //nolint:paralleltest
+	root := makeTestDatabase(t, true)
+	db := database.NewDomainDatabase("test_domain", root)
+	other := database.NewDomainDatabase("other_domain", root)
+
+	assert.NoError(t, db.Set("test_bucket", "test_key1", "test_value1"))
+	assert.NoError(t, db.SetWithExpiry("test_bucket", "test_key2", "test_value2", time.Hour))
+	assert.NoError(t, other.Set("test_bucket", "test_key3", "test_value3"))
+
+	entries, err := database.DumpDomain(root, "test_domain")
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "test_bucket:test_key1", entries[0].Key)
+	assert.JSONEq(t, `"test_value1"`, string(entries[0].Value))
+	assert.Nil(t, entries[0].ExpiresAt)
+	assert.NotNil(t, entries[1].ExpiresAt)
+
+	assert.NoError(t, db.Set("test_bucket", "test_key4", "test_value4"))
+	assert.NoError(t, database.RestoreDomain(root, "test_domain", entries[:1], true))
+
+	keys, err := db.Keys("test_bucket")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test_key1"}, keys)
+
+	assert.NoError(t, database.RestoreDomain(db, "test_bucket", []*database.Entry{{Key: "test_key5", Value: []byte(`"test_value5"`)}}, false))
+
+	keys, err = db.Keys("test_bucket")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test_key1", "test_key5"}, keys)
+
+	keys, err = other.Keys("test_bucket")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test_key3"}, keys)
+
+	err = database.RestoreDomain(root, "test_domain", []*database.Entry{{Key: "test_key6", Value: []byte("{")}}, false)
+
+	assert.Error(t, err)
+}
+
 func makeTestDatabase(t *testing.T, cleanup bool) database.Database {
 	t.Helper()
 
@@ -0,0 +1,175 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tidwall/buntdb"
+)
+
+// Entry is a raw database entry used to transfer data between databases.
+type Entry struct {
+	// Key is a key of the entry relative to the dumped prefix.
+	Key string `json:"key"`
+	// Value is a raw JSON value of the entry.
+	Value json.RawMessage `json:"value"`
+	// ExpiresAt is an optional expiry time of the entry.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Dumper is an optional interface of a database allowing to dump and restore raw entries.
+type Dumper interface {
+	// Dump returns all entries stored under the provided key prefix.
+	Dump(prefix string) ([]*Entry, error)
+	// Restore stores all provided entries under the provided key prefix within a single transaction.
+	// If replace is true all entries stored under the prefix are deleted beforehand.
+	Restore(prefix string, entries []*Entry, replace bool) error
+}
+
+// DumpDomain returns all entries of the provided domain, if the database supports dumping.
+func DumpDomain(db Database, domain string) ([]*Entry, error) {
+	d, ok := db.(Dumper)
+	if !ok {
+		return nil, errors.New("database: dumping is not supported by the database")
+	}
+
+	return d.Dump(domain + ":")
+}
+
+// RestoreDomain restores the provided entries of the domain, if the database supports restoring.
+func RestoreDomain(db Database, domain string, entries []*Entry, replace bool) error {
+	d, ok := db.(Dumper)
+	if !ok {
+		return errors.New("database: restoring is not supported by the database")
+	}
+
+	return d.Restore(domain+":", entries, replace)
+}
+
+// Dump returns all entries stored under the provided key prefix.
+func (d *database) Dump(prefix string) ([]*Entry, error) {
+	var entries []*Entry
+
+	err := d.db.View(func(tx *buntdb.Tx) error {
+		var keys []string
+
+		err := tx.AscendKeys(prefix+"*", func(key, _ string) bool {
+			keys = append(keys, key)
+
+			return true
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			value, err := tx.Get(key)
+			if err != nil {
+				return err
+			}
+
+			entry := &Entry{
+				Key:   strings.TrimPrefix(key, prefix),
+				Value: json.RawMessage(value),
+			}
+
+			ttl, err := tx.TTL(key)
+			if err != nil {
+				return err
+			}
+
+			if ttl > 0 {
+				expiresAt := time.Now().Add(ttl)
+				entry.ExpiresAt = &expiresAt
+			}
+
+			entries = append(entries, entry)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("database: failed to dump entries with prefix %s: %w", prefix, err)
+	}
+
+	return entries, nil
+}
+
+// Restore stores all provided entries under the provided key prefix within a single transaction.
+// If replace is true all entries stored under the prefix are deleted beforehand.
+func (d *database) Restore(prefix string, entries []*Entry, replace bool) error {
+	err := d.db.Update(func(tx *buntdb.Tx) error {
+		if replace {
+			var keys []string
+
+			err := tx.AscendKeys(prefix+"*", func(key, _ string) bool {
+				keys = append(keys, key)
+
+				return true
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, key := range keys {
+				if _, err := tx.Delete(key); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, entry := range entries {
+			if !json.Valid(entry.Value) {
+				return fmt.Errorf("value of the key %s is not a valid JSON", entry.Key)
+			}
+
+			var options *buntdb.SetOptions
+
+			if entry.ExpiresAt != nil {
+				ttl := time.Until(*entry.ExpiresAt)
+				if ttl <= 0 {
+					continue
+				}
+
+				options = &buntdb.SetOptions{
+					Expires: true,
+					TTL:     ttl,
+				}
+			}
+
+			if _, _, err := tx.Set(prefix+entry.Key, string(entry.Value), options); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("database: failed to restore entries with prefix %s: %w", prefix, err)
+	}
+
+	return nil
+}
+
+// Dump returns all entries stored under the provided key prefix within the domain.
+func (d *domainDatabase) Dump(prefix string) ([]*Entry, error) {
+	dumper, ok := d.Database.(Dumper)
+	if !ok {
+		return nil, errors.New("database: dumping is not supported by the database")
+	}
+
+	return dumper.Dump(d.domain + ":" + prefix)
+}
+
+// Restore stores all provided entries under the provided key prefix within the domain.
+func (d *domainDatabase) Restore(prefix string, entries []*Entry, replace bool) error {
+	dumper, ok := d.Database.(Dumper)
+	if !ok {
+		return errors.New("database: restoring is not supported by the database")
+	}
+
+	return dumper.Restore(d.domain+":"+prefix, entries, replace)
+}
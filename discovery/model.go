@@ -18,5 +18,6 @@ type resourceT struct {
 	PackageName  string                 `json:"package_name"`
 	InstanceID   string                 `json:"instance_id"`
 	Version      string                 `json:"version"`
+	Addresses    []string               `json:"resource_addresses,omitempty"`
 	States       *lifecycle.AppStateT   `json:"app_state,omitempty"`
 }
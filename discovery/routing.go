@@ -17,10 +17,27 @@ const (
 	EvtDiscoveryReport  = "evt.discovery.report"
 )
 
+// InstanceProvider provides addresses of all resource instances running within the application, e.g. multiple adapter instances.
+type InstanceProvider interface {
+	// Addresses returns addresses of all resource instances.
+	Addresses() []string
+}
+
 // appLifecycle may be nil; when provided, each reply includes fresh app states.
 func Route(resourceName fimptype.ResourceNameT, resourceType fimptype.ResourceTypeT, packageName, instanceID, version string, appLifecycle *lifecycle.Lifecycle) *router.Routing {
+	return RouteInstances(resourceName, resourceType, packageName, instanceID, version, appLifecycle, nil)
+}
+
+// RouteInstances works as Route, but each reply additionally includes addresses of all resource instances. Instances may be nil.
+func RouteInstances(
+	resourceName fimptype.ResourceNameT,
+	resourceType fimptype.ResourceTypeT,
+	packageName, instanceID, version string,
+	appLifecycle *lifecycle.Lifecycle,
+	instances InstanceProvider,
+) *router.Routing {
 	return router.NewRouting(
-		Handle(resourceName, resourceType, packageName, instanceID, version, appLifecycle),
+		HandleInstances(resourceName, resourceType, packageName, instanceID, version, appLifecycle, instances),
 		router.ForTopic(Topic),
 		router.ForType(CmdDiscoveryRequest),
 	)
@@ -28,6 +45,17 @@ func Route(resourceName fimptype.ResourceNameT, resourceType fimptype.ResourceTy
 
 // appLifecycle may be nil; when provided, each reply includes fresh app states.
 func Handle(resourceName fimptype.ResourceNameT, resourceType fimptype.ResourceTypeT, packageName, instanceID, version string, appLifecycle *lifecycle.Lifecycle) router.MessageHandler {
+	return HandleInstances(resourceName, resourceType, packageName, instanceID, version, appLifecycle, nil)
+}
+
+// HandleInstances works as Handle, but each reply additionally includes addresses of all resource instances. Instances may be nil.
+func HandleInstances(
+	resourceName fimptype.ResourceNameT,
+	resourceType fimptype.ResourceTypeT,
+	packageName, instanceID, version string,
+	appLifecycle *lifecycle.Lifecycle,
+	instances InstanceProvider,
+) router.MessageHandler {
 	return router.NewMessageHandler(
 		router.MessageProcessorFn(func(message *fimpgo.Message) (*fimpgo.FimpMessage, error) {
 			reply := &resourceT{
@@ -41,6 +69,10 @@ func Handle(resourceName fimptype.ResourceNameT, resourceType fimptype.ResourceT
 				reply.States = appLifecycle.AllStates()
 			}
 
+			if instances != nil {
+				reply.Addresses = instances.Addresses()
+			}
+
 			return fimpgo.NewObjectMessage(
 				EvtDiscoveryReport,
 				Service,
@@ -86,3 +86,38 @@ func TestHandle_WithLifecycle_EmitsFreshStatesOnEachRequest(t *testing.T) {
 	assert.NotEqual(t, firstStates["app"], secondStates["app"], "second reply must reflect state change")
 	assert.Equal(t, string(lifecycle.AppHealthRunning), secondStates["app"])
 }
+
+type testInstances []string
+
+func (i testInstances) Addresses() []string {
+	return i
+}
+
+func TestHandleInstances_EmitsReportWithAllInstanceAddresses(t *testing.T) {
+	t.Parallel()
+
+	handler := discovery.HandleInstances("my_adapter", discovery.ResourceTypeAd, "my_adapter", "1", "1.0.0", nil, testInstances{"1", "2"})
+
+	reply := handler.Handle(newDiscoveryRequest(t))
+	require.NotNil(t, reply)
+
+	raw, err := json.Marshal(reply.Payload.Value)
+	require.NoError(t, err)
+
+	var report map[string]any
+	require.NoError(t, json.Unmarshal(raw, &report))
+
+	assert.Equal(t, "my_adapter", report["resource_name"])
+	assert.Equal(t, []any{"1", "2"}, report["resource_addresses"])
+
+	reply = discovery.Handle("my_adapter", discovery.ResourceTypeAd, "my_adapter", "1", "1.0.0", nil).Handle(newDiscoveryRequest(t))
+	require.NotNil(t, reply)
+
+	raw, err = json.Marshal(reply.Payload.Value)
+	require.NoError(t, err)
+
+	report = nil
+	require.NoError(t, json.Unmarshal(raw, &report))
+
+	assert.NotContains(t, report, "resource_addresses")
+}
@@ -87,22 +87,34 @@ func (tp *TopicPattern) sa() string {
 }
 
 func TopicPatternAdapter(resourceName fimptype.ResourceNameT, msgType fimptype.MsgTypeT) string {
+	return TopicPatternAdapterWithAddress(resourceName, "1", msgType)
+}
+
+// TopicPatternAdapterWithAddress returns a topic pattern for an adapter instance with the provided address.
+// If the address is empty, the pattern matches all instances of the adapter.
+func TopicPatternAdapterWithAddress(resourceName fimptype.ResourceNameT, resourceAddress string, msgType fimptype.MsgTypeT) string {
 	return (&TopicPattern{
 		PayloadType:     fimpgo.DefaultPayload,
 		MessageType:     msgType,
 		ResourceType:    fimptype.ResourceTypeAdapter,
 		ResourceName:    resourceName,
-		ResourceAddress: "1",
+		ResourceAddress: resourceAddress,
 	}).String()
 }
 
 func TopicPatternDevice(resourceName fimptype.ResourceNameT, msgType fimptype.MsgTypeT) string {
+	return TopicPatternDeviceWithAddress(resourceName, "1", msgType)
+}
+
+// TopicPatternDeviceWithAddress returns a topic pattern for devices of an adapter instance with the provided address.
+// If the address is empty, the pattern matches devices of all instances of the adapter.
+func TopicPatternDeviceWithAddress(resourceName fimptype.ResourceNameT, resourceAddress string, msgType fimptype.MsgTypeT) string {
 	return (&TopicPattern{
 		PayloadType:     fimpgo.DefaultPayload,
 		MessageType:     msgType,
 		ResourceType:    fimptype.ResourceTypeDevice,
 		ResourceName:    resourceName,
-		ResourceAddress: "1",
+		ResourceAddress: resourceAddress,
 	}).String()
 }
 
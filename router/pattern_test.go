@@ -85,6 +85,15 @@ func TestTopicPatternHelpers(t *testing.T) {
 	got = router.TopicPatternDevice("test_resource", fimptype.MsgTypeCmd)
 	assert.Equal(t, "pt:j1/mt:cmd/rt:dev/rn:test_resource/ad:1/+/+", got)
 
+	got = router.TopicPatternAdapterWithAddress("test_resource", "2", fimptype.MsgTypeCmd)
+	assert.Equal(t, "pt:j1/mt:cmd/rt:ad/rn:test_resource/ad:2", got)
+
+	got = router.TopicPatternAdapterWithAddress("test_resource", "", fimptype.MsgTypeCmd)
+	assert.Equal(t, "pt:j1/mt:cmd/rt:ad/rn:test_resource/+", got)
+
+	got = router.TopicPatternDeviceWithAddress("test_resource", "2", fimptype.MsgTypeCmd)
+	assert.Equal(t, "pt:j1/mt:cmd/rt:dev/rn:test_resource/ad:2/+/+", got)
+
 	got = router.TopicPatternApplication("test_resource", fimptype.MsgTypeEvt)
 	assert.Equal(t, "pt:j1/mt:evt/rt:app/rn:test_resource/ad:1", got)
 
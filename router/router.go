@@ -295,10 +295,21 @@ func WithPanicCallback(f func(message *fimpgo.Message, err any)) Option {
 	})
 }
 
-// WithStatsCallback returns an option that sets a callback function that provides message processing statistics.
+// WithStatsCallback returns an option that adds a callback function that provides message processing statistics.
+// The option can be provided multiple times, in which case all callbacks are invoked in the order they were provided.
 func WithStatsCallback(f func(Stats)) Option {
 	return optionFn(func(cfg *config) {
-		cfg.statsCallback = f
+		previous := cfg.statsCallback
+		if previous == nil {
+			cfg.statsCallback = f
+
+			return
+		}
+
+		cfg.statsCallback = func(stats Stats) {
+			previous(stats)
+			f(stats)
+		}
 	})
 }
 
@@ -69,6 +69,13 @@ func ForResourceName(resourceName fimptype.ResourceNameT) MessageVoter {
 	})
 }
 
+// ForResourceAddress is a message voter allowing a routing to handle message only if it is relevant.
+func ForResourceAddress(resourceAddress string) MessageVoter {
+	return MessageVoterFn(func(message *fimpgo.Message) bool {
+		return message.Addr != nil && message.Addr.ResourceAddress == resourceAddress
+	})
+}
+
 // ForService is a message voter allowing a routing to handle message only if it is relevant.
 func ForService(service fimptype.ServiceNameT) MessageVoter {
 	return MessageVoterFn(func(message *fimpgo.Message) bool {
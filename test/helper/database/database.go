@@ -0,0 +1,25 @@
+package databasehelper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/futurehomeno/cliffhanger/database"
+)
+
+// NewDatabase creates and starts a database in a temporary directory of the test. The database is stopped when the test finishes.
+func NewDatabase(t *testing.T) database.Database {
+	t.Helper()
+
+	db, err := database.NewDatabase(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, db.Start())
+
+	t.Cleanup(func() {
+		assert.NoError(t, db.Stop())
+	})
+
+	return db
+}
@@ -0,0 +1,205 @@
+package ocpphelper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/futurehomeno/cliffhanger/adapter/ocpp"
+)
+
+// Simulator is an in-process OCPP 1.6J charge point with a single connector, connected to a central system under test.
+// Calls sent by the central system are recorded and answered with configured responses, by default accepting every request.
+type Simulator struct {
+	conn *ocpp.Connection
+
+	lock      sync.Mutex
+	responses map[string]any
+	calls     map[string][]json.RawMessage
+}
+
+// NewSimulator connects a new simulator as a charger of the provided identity to the central system listening on the address.
+// The simulator is disconnected when the test finishes.
+func NewSimulator(t *testing.T, address, id string) *Simulator {
+	t.Helper()
+
+	s := &Simulator{
+		calls: make(map[string][]json.RawMessage),
+		responses: map[string]any{
+			ocpp.ActionRemoteStartTransaction: &ocpp.RemoteStartTransactionResponse{Status: ocpp.RemoteStatusAccepted},
+			ocpp.ActionRemoteStopTransaction:  &ocpp.RemoteStopTransactionResponse{Status: ocpp.RemoteStatusAccepted},
+			ocpp.ActionSetChargingProfile:     &ocpp.SetChargingProfileResponse{Status: ocpp.ChargingProfileStatusAccepted},
+			ocpp.ActionChangeConfiguration:    &ocpp.ChangeConfigurationResponse{Status: ocpp.ConfigurationStatusAccepted},
+		},
+	}
+
+	dialer := &websocket.Dialer{
+		Subprotocols:     []string{ocpp.Subprotocol},
+		HandshakeTimeout: 5 * time.Second,
+	}
+
+	ws, resp, err := dialer.DialContext(context.Background(), fmt.Sprintf("ws://%s/ocpp/%s", address, id), nil)
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+
+	if err != nil {
+		t.Fatalf("ocpp helper: failed to connect charger %s: %s", id, err)
+	}
+
+	s.conn = ocpp.NewConnection(ws, s, 5*time.Second)
+	s.conn.Start()
+
+	t.Cleanup(func() {
+		_ = s.conn.Close()
+	})
+
+	return s
+}
+
+// Respond configures the response sent to calls of the provided action.
+func (s *Simulator) Respond(action string, response any) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.responses[action] = response
+}
+
+// Calls returns payloads of all received calls of the provided action.
+func (s *Simulator) Calls(action string) []json.RawMessage {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return append([]json.RawMessage(nil), s.calls[action]...)
+}
+
+// LastCall decodes the payload of the last received call of the provided action into the request.
+func (s *Simulator) LastCall(t *testing.T, action string, request any) {
+	t.Helper()
+
+	calls := s.Calls(action)
+	if len(calls) == 0 {
+		t.Fatalf("ocpp helper: no %s call was received", action)
+	}
+
+	if err := json.Unmarshal(calls[len(calls)-1], request); err != nil {
+		t.Fatalf("ocpp helper: failed to unmarshal %s call: %s", action, err)
+	}
+}
+
+// HandleCall records the call received from the central system and returns the configured response.
+func (s *Simulator) HandleCall(action string, payload json.RawMessage) (any, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.calls[action] = append(s.calls[action], payload)
+
+	response, ok := s.responses[action]
+	if !ok {
+		return nil, ocpp.NewCallError(ocpp.ErrorCodeNotImplemented, "action %s is not supported", action)
+	}
+
+	return response, nil
+}
+
+// Call sends a call to the central system.
+func (s *Simulator) Call(action string, request, response any) error {
+	return s.conn.Call(action, request, response)
+}
+
+// BootNotification sends a boot notification of the provided vendor and model.
+func (s *Simulator) BootNotification(t *testing.T, vendor, model string) *ocpp.BootNotificationResponse {
+	t.Helper()
+
+	response := &ocpp.BootNotificationResponse{}
+	s.mustCall(t, ocpp.ActionBootNotification, &ocpp.BootNotificationRequest{
+		ChargePointVendor:       vendor,
+		ChargePointModel:        model,
+		ChargePointSerialNumber: "SN-1",
+		FirmwareVersion:         "1.0.0",
+	}, response)
+
+	return response
+}
+
+// StatusNotification sends a status notification of the connector.
+func (s *Simulator) StatusNotification(t *testing.T, status ocpp.ChargePointStatus) {
+	t.Helper()
+
+	s.mustCall(t, ocpp.ActionStatusNotification, &ocpp.StatusNotificationRequest{
+		ConnectorID: ocpp.ConnectorID,
+		ErrorCode:   "NoError",
+		Status:      status,
+	}, &ocpp.StatusNotificationResponse{})
+}
+
+// StartTransaction starts a transaction of the ID tag with the provided energy register in Wh.
+func (s *Simulator) StartTransaction(t *testing.T, idTag string, meterStart int) *ocpp.StartTransactionResponse {
+	t.Helper()
+
+	response := &ocpp.StartTransactionResponse{}
+	s.mustCall(t, ocpp.ActionStartTransaction, &ocpp.StartTransactionRequest{
+		ConnectorID: ocpp.ConnectorID,
+		IDTag:       idTag,
+		MeterStart:  meterStart,
+		Timestamp:   time.Now().UTC(),
+	}, response)
+
+	return response
+}
+
+// MeterValues sends the energy register in Wh, active power in W and phase currents in A.
+func (s *Simulator) MeterValues(t *testing.T, transactionID int, energy, power float64, currents ...float64) {
+	t.Helper()
+
+	values := []*ocpp.SampledValue{
+		{Value: formatFloat(energy), Measurand: ocpp.MeasurandEnergyActiveImportRegister, Unit: ocpp.UnitWh},
+		{Value: formatFloat(power), Measurand: ocpp.MeasurandPowerActiveImport, Unit: ocpp.UnitW},
+	}
+
+	for i, current := range currents {
+		values = append(values, &ocpp.SampledValue{
+			Value:     formatFloat(current),
+			Measurand: ocpp.MeasurandCurrentImport,
+			Phase:     fmt.Sprintf("L%d", i+1),
+			Unit:      ocpp.UnitA,
+		})
+	}
+
+	s.mustCall(t, ocpp.ActionMeterValues, &ocpp.MeterValuesRequest{
+		ConnectorID:   ocpp.ConnectorID,
+		TransactionID: &transactionID,
+		MeterValue:    []*ocpp.MeterValue{{Timestamp: time.Now().UTC(), SampledValue: values}},
+	}, &ocpp.MeterValuesResponse{})
+}
+
+// StopTransaction stops the transaction with the provided energy register in Wh.
+func (s *Simulator) StopTransaction(t *testing.T, transactionID, meterStop int, reason string) {
+	t.Helper()
+
+	s.mustCall(t, ocpp.ActionStopTransaction, &ocpp.StopTransactionRequest{
+		TransactionID: transactionID,
+		MeterStop:     meterStop,
+		Timestamp:     time.Now().UTC(),
+		Reason:        reason,
+	}, &ocpp.StopTransactionResponse{})
+}
+
+// mustCall sends a call to the central system and fails the test on error.
+func (s *Simulator) mustCall(t *testing.T, action string, request, response any) {
+	t.Helper()
+
+	if err := s.Call(action, request, response); err != nil {
+		t.Fatalf("ocpp helper: %s call failed: %s", action, err)
+	}
+}
+
+// formatFloat formats the value of a sampled value.
+func formatFloat(v float64) string {
+	return fmt.Sprintf("%g", v)
+}
@@ -27,3 +27,15 @@ func (_m *Connector) MockConnectivity(details *adapter.ConnectivityDetails, once
 
 	return _m
 }
+
+func (_m *Connector) MockPing(details *adapter.PingDetails, once bool) *Connector {
+	c := _m.On("Ping").Return(details)
+
+	if once {
+		c.Once()
+	} else {
+		c.Maybe()
+	}
+
+	return _m
+}
@@ -27,6 +27,62 @@ func (_m *Service) EXPECT() *Service_Expecter {
 	return &Service_Expecter{mock: &_m.Mock}
 }
 
+// Authorize provides a mock function with given fields: tagID
+func (_m *Service) Authorize(tagID string) (bool, error) {
+	ret := _m.Called(tagID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authorize")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (bool, error)); ok {
+		return rf(tagID)
+	}
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(tagID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tagID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_Authorize_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Authorize'
+type Service_Authorize_Call struct {
+	*mock.Call
+}
+
+// Authorize is a helper method to define mock.On call
+//   - tagID string
+func (_e *Service_Expecter) Authorize(tagID interface{}) *Service_Authorize_Call {
+	return &Service_Authorize_Call{Call: _e.mock.On("Authorize", tagID)}
+}
+
+func (_c *Service_Authorize_Call) Run(run func(tagID string)) *Service_Authorize_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *Service_Authorize_Call) Return(_a0 bool, _a1 error) *Service_Authorize_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_Authorize_Call) RunAndReturn(run func(string) (bool, error)) *Service_Authorize_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // IsCableLockAware provides a mock function with no fields
 func (_m *Service) IsCableLockAware() bool {
 	ret := _m.Called()
@@ -195,6 +251,51 @@ func (_c *Service_PublishEvent_Call) RunAndReturn(run func(adapter.ServiceEvent)
 	return _c
 }
 
+// SendAuthorizationListReport provides a mock function with no fields
+func (_m *Service) SendAuthorizationListReport() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendAuthorizationListReport")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Service_SendAuthorizationListReport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendAuthorizationListReport'
+type Service_SendAuthorizationListReport_Call struct {
+	*mock.Call
+}
+
+// SendAuthorizationListReport is a helper method to define mock.On call
+func (_e *Service_Expecter) SendAuthorizationListReport() *Service_SendAuthorizationListReport_Call {
+	return &Service_SendAuthorizationListReport_Call{Call: _e.mock.On("SendAuthorizationListReport")}
+}
+
+func (_c *Service_SendAuthorizationListReport_Call) Run(run func()) *Service_SendAuthorizationListReport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Service_SendAuthorizationListReport_Call) Return(_a0 error) *Service_SendAuthorizationListReport_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_SendAuthorizationListReport_Call) RunAndReturn(run func() error) *Service_SendAuthorizationListReport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SendCableLockReport provides a mock function with given fields: force
 func (_m *Service) SendCableLockReport(force bool) (bool, error) {
 	ret := _m.Called(force)
@@ -465,6 +566,52 @@ func (_c *Service_SendPhaseModeReport_Call) RunAndReturn(run func(bool) (bool, e
 	return _c
 }
 
+// SendSessionHistoryReport provides a mock function with given fields: request
+func (_m *Service) SendSessionHistoryReport(request *chargepoint.SessionHistoryRequest) error {
+	ret := _m.Called(request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendSessionHistoryReport")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*chargepoint.SessionHistoryRequest) error); ok {
+		r0 = rf(request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Service_SendSessionHistoryReport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendSessionHistoryReport'
+type Service_SendSessionHistoryReport_Call struct {
+	*mock.Call
+}
+
+// SendSessionHistoryReport is a helper method to define mock.On call
+//   - request *chargepoint.SessionHistoryRequest
+func (_e *Service_Expecter) SendSessionHistoryReport(request interface{}) *Service_SendSessionHistoryReport_Call {
+	return &Service_SendSessionHistoryReport_Call{Call: _e.mock.On("SendSessionHistoryReport", request)}
+}
+
+func (_c *Service_SendSessionHistoryReport_Call) Run(run func(request *chargepoint.SessionHistoryRequest)) *Service_SendSessionHistoryReport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*chargepoint.SessionHistoryRequest))
+	})
+	return _c
+}
+
+func (_c *Service_SendSessionHistoryReport_Call) Return(_a0 error) *Service_SendSessionHistoryReport_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_SendSessionHistoryReport_Call) RunAndReturn(run func(*chargepoint.SessionHistoryRequest) error) *Service_SendSessionHistoryReport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SendStateReport provides a mock function with given fields: force
 func (_m *Service) SendStateReport(force bool) (bool, error) {
 	ret := _m.Called(force)
@@ -521,6 +668,52 @@ func (_c *Service_SendStateReport_Call) RunAndReturn(run func(bool) (bool, error
 	return _c
 }
 
+// SetAuthorizationList provides a mock function with given fields: list
+func (_m *Service) SetAuthorizationList(list *chargepoint.AuthorizationList) error {
+	ret := _m.Called(list)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetAuthorizationList")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*chargepoint.AuthorizationList) error); ok {
+		r0 = rf(list)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Service_SetAuthorizationList_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetAuthorizationList'
+type Service_SetAuthorizationList_Call struct {
+	*mock.Call
+}
+
+// SetAuthorizationList is a helper method to define mock.On call
+//   - list *chargepoint.AuthorizationList
+func (_e *Service_Expecter) SetAuthorizationList(list interface{}) *Service_SetAuthorizationList_Call {
+	return &Service_SetAuthorizationList_Call{Call: _e.mock.On("SetAuthorizationList", list)}
+}
+
+func (_c *Service_SetAuthorizationList_Call) Run(run func(list *chargepoint.AuthorizationList)) *Service_SetAuthorizationList_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*chargepoint.AuthorizationList))
+	})
+	return _c
+}
+
+func (_c *Service_SetAuthorizationList_Call) Return(_a0 error) *Service_SetAuthorizationList_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_SetAuthorizationList_Call) RunAndReturn(run func(*chargepoint.AuthorizationList) error) *Service_SetAuthorizationList_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetCableLock provides a mock function with given fields: _a0
 func (_m *Service) SetCableLock(_a0 bool) error {
 	ret := _m.Called(_a0)
@@ -1070,6 +1263,96 @@ func (_c *Service_SupportsAdjustingPhaseModes_Call) RunAndReturn(run func() bool
 	return _c
 }
 
+// SupportsAuthorization provides a mock function with no fields
+func (_m *Service) SupportsAuthorization() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for SupportsAuthorization")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Service_SupportsAuthorization_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SupportsAuthorization'
+type Service_SupportsAuthorization_Call struct {
+	*mock.Call
+}
+
+// SupportsAuthorization is a helper method to define mock.On call
+func (_e *Service_Expecter) SupportsAuthorization() *Service_SupportsAuthorization_Call {
+	return &Service_SupportsAuthorization_Call{Call: _e.mock.On("SupportsAuthorization")}
+}
+
+func (_c *Service_SupportsAuthorization_Call) Run(run func()) *Service_SupportsAuthorization_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Service_SupportsAuthorization_Call) Return(_a0 bool) *Service_SupportsAuthorization_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_SupportsAuthorization_Call) RunAndReturn(run func() bool) *Service_SupportsAuthorization_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SupportsSessionHistory provides a mock function with no fields
+func (_m *Service) SupportsSessionHistory() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for SupportsSessionHistory")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Service_SupportsSessionHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SupportsSessionHistory'
+type Service_SupportsSessionHistory_Call struct {
+	*mock.Call
+}
+
+// SupportsSessionHistory is a helper method to define mock.On call
+func (_e *Service_Expecter) SupportsSessionHistory() *Service_SupportsSessionHistory_Call {
+	return &Service_SupportsSessionHistory_Call{Call: _e.mock.On("SupportsSessionHistory")}
+}
+
+func (_c *Service_SupportsSessionHistory_Call) Run(run func()) *Service_SupportsSessionHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Service_SupportsSessionHistory_Call) Return(_a0 bool) *Service_SupportsSessionHistory_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_SupportsSessionHistory_Call) RunAndReturn(run func() bool) *Service_SupportsSessionHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Topic provides a mock function with no fields
 func (_m *Service) Topic() string {
 	ret := _m.Called()
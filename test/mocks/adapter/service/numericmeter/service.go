@@ -104,6 +104,100 @@ func (_c *Service_PublishEvent_Call) RunAndReturn(run func(adapter.ServiceEvent)
 	return _c
 }
 
+// ReportedExportUnits provides a mock function with no fields
+func (_m *Service) ReportedExportUnits() numericmeter.Units {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReportedExportUnits")
+	}
+
+	var r0 numericmeter.Units
+	if rf, ok := ret.Get(0).(func() numericmeter.Units); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(numericmeter.Units)
+		}
+	}
+
+	return r0
+}
+
+// Service_ReportedExportUnits_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReportedExportUnits'
+type Service_ReportedExportUnits_Call struct {
+	*mock.Call
+}
+
+// ReportedExportUnits is a helper method to define mock.On call
+func (_e *Service_Expecter) ReportedExportUnits() *Service_ReportedExportUnits_Call {
+	return &Service_ReportedExportUnits_Call{Call: _e.mock.On("ReportedExportUnits")}
+}
+
+func (_c *Service_ReportedExportUnits_Call) Run(run func()) *Service_ReportedExportUnits_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Service_ReportedExportUnits_Call) Return(_a0 numericmeter.Units) *Service_ReportedExportUnits_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_ReportedExportUnits_Call) RunAndReturn(run func() numericmeter.Units) *Service_ReportedExportUnits_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReportedUnits provides a mock function with no fields
+func (_m *Service) ReportedUnits() numericmeter.Units {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReportedUnits")
+	}
+
+	var r0 numericmeter.Units
+	if rf, ok := ret.Get(0).(func() numericmeter.Units); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(numericmeter.Units)
+		}
+	}
+
+	return r0
+}
+
+// Service_ReportedUnits_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReportedUnits'
+type Service_ReportedUnits_Call struct {
+	*mock.Call
+}
+
+// ReportedUnits is a helper method to define mock.On call
+func (_e *Service_Expecter) ReportedUnits() *Service_ReportedUnits_Call {
+	return &Service_ReportedUnits_Call{Call: _e.mock.On("ReportedUnits")}
+}
+
+func (_c *Service_ReportedUnits_Call) Run(run func()) *Service_ReportedUnits_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Service_ReportedUnits_Call) Return(_a0 numericmeter.Units) *Service_ReportedUnits_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_ReportedUnits_Call) RunAndReturn(run func() numericmeter.Units) *Service_ReportedUnits_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ResetMeter provides a mock function with no fields
 func (_m *Service) ResetMeter() error {
 	ret := _m.Called()
@@ -309,6 +403,52 @@ func (_c *Service_SendMeterExtendedReport_Call) RunAndReturn(run func(numericmet
 	return _c
 }
 
+// SendMeterHistoryReport provides a mock function with given fields: request
+func (_m *Service) SendMeterHistoryReport(request *numericmeter.HistoryRequest) error {
+	ret := _m.Called(request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendMeterHistoryReport")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*numericmeter.HistoryRequest) error); ok {
+		r0 = rf(request)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Service_SendMeterHistoryReport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendMeterHistoryReport'
+type Service_SendMeterHistoryReport_Call struct {
+	*mock.Call
+}
+
+// SendMeterHistoryReport is a helper method to define mock.On call
+//   - request *numericmeter.HistoryRequest
+func (_e *Service_Expecter) SendMeterHistoryReport(request interface{}) *Service_SendMeterHistoryReport_Call {
+	return &Service_SendMeterHistoryReport_Call{Call: _e.mock.On("SendMeterHistoryReport", request)}
+}
+
+func (_c *Service_SendMeterHistoryReport_Call) Run(run func(request *numericmeter.HistoryRequest)) *Service_SendMeterHistoryReport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*numericmeter.HistoryRequest))
+	})
+	return _c
+}
+
+func (_c *Service_SendMeterHistoryReport_Call) Return(_a0 error) *Service_SendMeterHistoryReport_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_SendMeterHistoryReport_Call) RunAndReturn(run func(*numericmeter.HistoryRequest) error) *Service_SendMeterHistoryReport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SendMeterReport provides a mock function with given fields: unit, force
 func (_m *Service) SendMeterReport(unit numericmeter.Unit, force bool) (bool, error) {
 	ret := _m.Called(unit, force)
@@ -644,6 +784,51 @@ func (_c *Service_SupportsExtendedReport_Call) RunAndReturn(run func() bool) *Se
 	return _c
 }
 
+// SupportsHistory provides a mock function with no fields
+func (_m *Service) SupportsHistory() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for SupportsHistory")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Service_SupportsHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SupportsHistory'
+type Service_SupportsHistory_Call struct {
+	*mock.Call
+}
+
+// SupportsHistory is a helper method to define mock.On call
+func (_e *Service_Expecter) SupportsHistory() *Service_SupportsHistory_Call {
+	return &Service_SupportsHistory_Call{Call: _e.mock.On("SupportsHistory")}
+}
+
+func (_c *Service_SupportsHistory_Call) Run(run func()) *Service_SupportsHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Service_SupportsHistory_Call) Return(_a0 bool) *Service_SupportsHistory_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_SupportsHistory_Call) RunAndReturn(run func() bool) *Service_SupportsHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Topic provides a mock function with no fields
 func (_m *Service) Topic() string {
 	ret := _m.Called()
@@ -102,6 +102,53 @@ func (_c *Service_PublishEvent_Call) RunAndReturn(run func(adapter.ServiceEvent)
 	return _c
 }
 
+// ReportedUnits provides a mock function with no fields
+func (_m *Service) ReportedUnits() []string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReportedUnits")
+	}
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// Service_ReportedUnits_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReportedUnits'
+type Service_ReportedUnits_Call struct {
+	*mock.Call
+}
+
+// ReportedUnits is a helper method to define mock.On call
+func (_e *Service_Expecter) ReportedUnits() *Service_ReportedUnits_Call {
+	return &Service_ReportedUnits_Call{Call: _e.mock.On("ReportedUnits")}
+}
+
+func (_c *Service_ReportedUnits_Call) Run(run func()) *Service_ReportedUnits_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Service_ReportedUnits_Call) Return(_a0 []string) *Service_ReportedUnits_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_ReportedUnits_Call) RunAndReturn(run func() []string) *Service_ReportedUnits_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SendMessage provides a mock function with given fields: message
 func (_m *Service) SendMessage(message *fimpgo.FimpMessage) error {
 	ret := _m.Called(message)
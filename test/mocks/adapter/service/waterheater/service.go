@@ -9,6 +9,8 @@ import (
 	fimptype "github.com/futurehomeno/fimpgo/fimptype"
 
 	mock "github.com/stretchr/testify/mock"
+
+	schedule "github.com/futurehomeno/cliffhanger/adapter/schedule"
 )
 
 // Service is an autogenerated mock type for the Service type
@@ -24,6 +26,51 @@ func (_m *Service) EXPECT() *Service_Expecter {
 	return &Service_Expecter{mock: &_m.Mock}
 }
 
+// ExecuteSchedule provides a mock function with no fields
+func (_m *Service) ExecuteSchedule() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExecuteSchedule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Service_ExecuteSchedule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExecuteSchedule'
+type Service_ExecuteSchedule_Call struct {
+	*mock.Call
+}
+
+// ExecuteSchedule is a helper method to define mock.On call
+func (_e *Service_Expecter) ExecuteSchedule() *Service_ExecuteSchedule_Call {
+	return &Service_ExecuteSchedule_Call{Call: _e.mock.On("ExecuteSchedule")}
+}
+
+func (_c *Service_ExecuteSchedule_Call) Run(run func()) *Service_ExecuteSchedule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Service_ExecuteSchedule_Call) Return(_a0 error) *Service_ExecuteSchedule_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_ExecuteSchedule_Call) RunAndReturn(run func() error) *Service_ExecuteSchedule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Name provides a mock function with no fields
 func (_m *Service) Name() fimptype.ServiceNameT {
 	ret := _m.Called()
@@ -204,6 +251,62 @@ func (_c *Service_SendModeReport_Call) RunAndReturn(run func(bool) (bool, error)
 	return _c
 }
 
+// SendScheduleReport provides a mock function with given fields: force
+func (_m *Service) SendScheduleReport(force bool) (bool, error) {
+	ret := _m.Called(force)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendScheduleReport")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(bool) (bool, error)); ok {
+		return rf(force)
+	}
+	if rf, ok := ret.Get(0).(func(bool) bool); ok {
+		r0 = rf(force)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(bool) error); ok {
+		r1 = rf(force)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Service_SendScheduleReport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendScheduleReport'
+type Service_SendScheduleReport_Call struct {
+	*mock.Call
+}
+
+// SendScheduleReport is a helper method to define mock.On call
+//   - force bool
+func (_e *Service_Expecter) SendScheduleReport(force interface{}) *Service_SendScheduleReport_Call {
+	return &Service_SendScheduleReport_Call{Call: _e.mock.On("SendScheduleReport", force)}
+}
+
+func (_c *Service_SendScheduleReport_Call) Run(run func(force bool)) *Service_SendScheduleReport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(bool))
+	})
+	return _c
+}
+
+func (_c *Service_SendScheduleReport_Call) Return(_a0 bool, _a1 error) *Service_SendScheduleReport_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Service_SendScheduleReport_Call) RunAndReturn(run func(bool) (bool, error)) *Service_SendScheduleReport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SendSetpointReport provides a mock function with given fields: mode, force
 func (_m *Service) SendSetpointReport(mode string, force bool) (bool, error) {
 	ret := _m.Called(mode, force)
@@ -363,6 +466,52 @@ func (_c *Service_SetMode_Call) RunAndReturn(run func(string) error) *Service_Se
 	return _c
 }
 
+// SetSchedule provides a mock function with given fields: program
+func (_m *Service) SetSchedule(program *schedule.Program) error {
+	ret := _m.Called(program)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetSchedule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*schedule.Program) error); ok {
+		r0 = rf(program)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Service_SetSchedule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetSchedule'
+type Service_SetSchedule_Call struct {
+	*mock.Call
+}
+
+// SetSchedule is a helper method to define mock.On call
+//   - program *schedule.Program
+func (_e *Service_Expecter) SetSchedule(program interface{}) *Service_SetSchedule_Call {
+	return &Service_SetSchedule_Call{Call: _e.mock.On("SetSchedule", program)}
+}
+
+func (_c *Service_SetSchedule_Call) Run(run func(program *schedule.Program)) *Service_SetSchedule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(*schedule.Program))
+	})
+	return _c
+}
+
+func (_c *Service_SetSchedule_Call) Return(_a0 error) *Service_SetSchedule_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_SetSchedule_Call) RunAndReturn(run func(*schedule.Program) error) *Service_SetSchedule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetSetpoint provides a mock function with given fields: mode, value, unit
 func (_m *Service) SetSetpoint(mode string, value float64, unit string) error {
 	ret := _m.Called(mode, value, unit)
@@ -599,6 +748,51 @@ func (_c *Service_SupportedStates_Call) RunAndReturn(run func() []string) *Servi
 	return _c
 }
 
+// SupportsSchedule provides a mock function with no fields
+func (_m *Service) SupportsSchedule() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for SupportsSchedule")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Service_SupportsSchedule_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SupportsSchedule'
+type Service_SupportsSchedule_Call struct {
+	*mock.Call
+}
+
+// SupportsSchedule is a helper method to define mock.On call
+func (_e *Service_Expecter) SupportsSchedule() *Service_SupportsSchedule_Call {
+	return &Service_SupportsSchedule_Call{Call: _e.mock.On("SupportsSchedule")}
+}
+
+func (_c *Service_SupportsSchedule_Call) Run(run func()) *Service_SupportsSchedule_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Service_SupportsSchedule_Call) Return(_a0 bool) *Service_SupportsSchedule_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Service_SupportsSchedule_Call) RunAndReturn(run func() bool) *Service_SupportsSchedule_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SupportsSetpoint provides a mock function with given fields: setpoint
 func (_m *Service) SupportsSetpoint(setpoint string) bool {
 	ret := _m.Called(setpoint)
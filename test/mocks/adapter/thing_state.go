@@ -2,7 +2,11 @@
 
 package mockedadapter
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	cache "github.com/futurehomeno/cliffhanger/adapter/cache"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // ThingState is an autogenerated mock type for the ThingState type
 type ThingState struct {
@@ -62,6 +66,51 @@ func (_c *ThingState_Address_Call) RunAndReturn(run func() string) *ThingState_A
 	return _c
 }
 
+// Alias provides a mock function with no fields
+func (_m *ThingState) Alias() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Alias")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// ThingState_Alias_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Alias'
+type ThingState_Alias_Call struct {
+	*mock.Call
+}
+
+// Alias is a helper method to define mock.On call
+func (_e *ThingState_Expecter) Alias() *ThingState_Alias_Call {
+	return &ThingState_Alias_Call{Call: _e.mock.On("Alias")}
+}
+
+func (_c *ThingState_Alias_Call) Run(run func()) *ThingState_Alias_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *ThingState_Alias_Call) Return(_a0 string) *ThingState_Alias_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ThingState_Alias_Call) RunAndReturn(run func() string) *ThingState_Alias_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ID provides a mock function with no fields
 func (_m *ThingState) ID() string {
 	ret := _m.Called()
@@ -198,6 +247,146 @@ func (_c *ThingState_Info_Call) RunAndReturn(run func(interface{}) error) *Thing
 	return _c
 }
 
+// Metadata provides a mock function with no fields
+func (_m *ThingState) Metadata() map[string]string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Metadata")
+	}
+
+	var r0 map[string]string
+	if rf, ok := ret.Get(0).(func() map[string]string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]string)
+		}
+	}
+
+	return r0
+}
+
+// ThingState_Metadata_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Metadata'
+type ThingState_Metadata_Call struct {
+	*mock.Call
+}
+
+// Metadata is a helper method to define mock.On call
+func (_e *ThingState_Expecter) Metadata() *ThingState_Metadata_Call {
+	return &ThingState_Metadata_Call{Call: _e.mock.On("Metadata")}
+}
+
+func (_c *ThingState_Metadata_Call) Run(run func()) *ThingState_Metadata_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *ThingState_Metadata_Call) Return(_a0 map[string]string) *ThingState_Metadata_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ThingState_Metadata_Call) RunAndReturn(run func() map[string]string) *ThingState_Metadata_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReportingStrategies provides a mock function with no fields
+func (_m *ThingState) ReportingStrategies() map[string]*cache.ReportingStrategySpec {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReportingStrategies")
+	}
+
+	var r0 map[string]*cache.ReportingStrategySpec
+	if rf, ok := ret.Get(0).(func() map[string]*cache.ReportingStrategySpec); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]*cache.ReportingStrategySpec)
+		}
+	}
+
+	return r0
+}
+
+// ThingState_ReportingStrategies_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReportingStrategies'
+type ThingState_ReportingStrategies_Call struct {
+	*mock.Call
+}
+
+// ReportingStrategies is a helper method to define mock.On call
+func (_e *ThingState_Expecter) ReportingStrategies() *ThingState_ReportingStrategies_Call {
+	return &ThingState_ReportingStrategies_Call{Call: _e.mock.On("ReportingStrategies")}
+}
+
+func (_c *ThingState_ReportingStrategies_Call) Run(run func()) *ThingState_ReportingStrategies_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *ThingState_ReportingStrategies_Call) Return(_a0 map[string]*cache.ReportingStrategySpec) *ThingState_ReportingStrategies_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ThingState_ReportingStrategies_Call) RunAndReturn(run func() map[string]*cache.ReportingStrategySpec) *ThingState_ReportingStrategies_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetAlias provides a mock function with given fields: alias
+func (_m *ThingState) SetAlias(alias string) error {
+	ret := _m.Called(alias)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetAlias")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(alias)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ThingState_SetAlias_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetAlias'
+type ThingState_SetAlias_Call struct {
+	*mock.Call
+}
+
+// SetAlias is a helper method to define mock.On call
+//   - alias string
+func (_e *ThingState_Expecter) SetAlias(alias interface{}) *ThingState_SetAlias_Call {
+	return &ThingState_SetAlias_Call{Call: _e.mock.On("SetAlias", alias)}
+}
+
+func (_c *ThingState_SetAlias_Call) Run(run func(alias string)) *ThingState_SetAlias_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *ThingState_SetAlias_Call) Return(_a0 error) *ThingState_SetAlias_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ThingState_SetAlias_Call) RunAndReturn(run func(string) error) *ThingState_SetAlias_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetInclusionChecksum provides a mock function with given fields: checksum
 func (_m *ThingState) SetInclusionChecksum(checksum uint32) error {
 	ret := _m.Called(checksum)
@@ -244,6 +433,99 @@ func (_c *ThingState_SetInclusionChecksum_Call) RunAndReturn(run func(uint32) er
 	return _c
 }
 
+// SetMetadata provides a mock function with given fields: metadata
+func (_m *ThingState) SetMetadata(metadata map[string]string) error {
+	ret := _m.Called(metadata)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetMetadata")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(map[string]string) error); ok {
+		r0 = rf(metadata)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ThingState_SetMetadata_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetMetadata'
+type ThingState_SetMetadata_Call struct {
+	*mock.Call
+}
+
+// SetMetadata is a helper method to define mock.On call
+//   - metadata map[string]string
+func (_e *ThingState_Expecter) SetMetadata(metadata interface{}) *ThingState_SetMetadata_Call {
+	return &ThingState_SetMetadata_Call{Call: _e.mock.On("SetMetadata", metadata)}
+}
+
+func (_c *ThingState_SetMetadata_Call) Run(run func(metadata map[string]string)) *ThingState_SetMetadata_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(map[string]string))
+	})
+	return _c
+}
+
+func (_c *ThingState_SetMetadata_Call) Return(_a0 error) *ThingState_SetMetadata_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ThingState_SetMetadata_Call) RunAndReturn(run func(map[string]string) error) *ThingState_SetMetadata_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetReportingStrategy provides a mock function with given fields: topic, spec
+func (_m *ThingState) SetReportingStrategy(topic string, spec *cache.ReportingStrategySpec) error {
+	ret := _m.Called(topic, spec)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetReportingStrategy")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *cache.ReportingStrategySpec) error); ok {
+		r0 = rf(topic, spec)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ThingState_SetReportingStrategy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetReportingStrategy'
+type ThingState_SetReportingStrategy_Call struct {
+	*mock.Call
+}
+
+// SetReportingStrategy is a helper method to define mock.On call
+//   - topic string
+//   - spec *cache.ReportingStrategySpec
+func (_e *ThingState_Expecter) SetReportingStrategy(topic interface{}, spec interface{}) *ThingState_SetReportingStrategy_Call {
+	return &ThingState_SetReportingStrategy_Call{Call: _e.mock.On("SetReportingStrategy", topic, spec)}
+}
+
+func (_c *ThingState_SetReportingStrategy_Call) Run(run func(topic string, spec *cache.ReportingStrategySpec)) *ThingState_SetReportingStrategy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(*cache.ReportingStrategySpec))
+	})
+	return _c
+}
+
+func (_c *ThingState_SetReportingStrategy_Call) Return(_a0 error) *ThingState_SetReportingStrategy_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ThingState_SetReportingStrategy_Call) RunAndReturn(run func(string, *cache.ReportingStrategySpec) error) *ThingState_SetReportingStrategy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetState provides a mock function with given fields: model
 func (_m *ThingState) SetState(model interface{}) error {
 	ret := _m.Called(model)